@@ -0,0 +1,54 @@
+package readability
+
+import (
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ampURL returns the absolute URL of <link rel="amphtml">, or empty string if not exists.
+func ampURL(doc *goquery.Document, reqURL string) string {
+	href, ok := doc.Find(`link[rel~="amphtml"]`).First().Attr("href")
+	if !ok {
+		return ""
+	}
+	u, err := absPath(href, reqURL)
+	if err != nil {
+		logger.Warnf("ampURL failed: %v", err)
+		return ""
+	}
+	return u
+}
+
+// extractFromAMP returns contents extracted from the AMP version of the page
+// if opt.FollowAMP is set and c has a too short description, otherwise returns c.
+// The AMP version is used only if it has a longer description than c.
+func extractFromAMP(c *Content, opt *Option, fetch func(string) (*fetchedPage, error)) *Content {
+	if !opt.FollowAMP || c.AMPURL == "" || len(c.Description) >= opt.RetryLength {
+		return c
+	}
+	if opt.DryRun {
+		c.planRequest(RequestAMP, c.AMPURL)
+		return c
+	}
+
+	page, err := fetch(c.AMPURL)
+	if err != nil {
+		logger.Warnf("extractFromAMP failed: %v", err)
+		return c
+	}
+	ampOpt := copyOption(opt)
+	ampOpt.FollowAMP = false
+	amp, err := extractFromDocument(page.doc, c.AMPURL, ampOpt)
+	if err != nil {
+		logger.Warnf("extractFromAMP failed: %v", err)
+		return c
+	}
+	if len(amp.Description) <= len(c.Description) {
+		return c
+	}
+	amp.setFetched(page, opt)
+	// the AMP version is of the same page
+	amp.Redirects, amp.NormalizedURL = c.Redirects, c.NormalizedURL
+	amp.AMPURL = c.AMPURL
+	amp.FromAMP = true
+	return amp
+}
@@ -0,0 +1,50 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAMPURL(t *testing.T) {
+	html := `<html><head><link rel="amphtml" href="/amp/story.html"></head></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.Equal(t, "http://example.com/amp/story.html", ampURL(doc, "http://example.com/story.html"))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<html></html>`))
+	assert.Equal(t, "", ampURL(doc, "http://example.com/story.html"))
+}
+
+func TestExtractFromAMP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/story":
+			fmt.Fprint(w, `<html><head><title>Story</title><link rel="amphtml" href="/story/amp"></head><body><p>Loading...</p></body></html>`)
+		case "/story/amp":
+			fmt.Fprint(w, articleHTML)
+		}
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	e := NewExtractor(opt)
+	c, err := e.Extract(context.Background(), ts.URL+"/story")
+	assert.Nil(t, err)
+	assert.Equal(t, ts.URL+"/story/amp", c.AMPURL)
+	assert.False(t, c.FromAMP)
+	assert.NotContains(t, c.Description, "Lorem ipsum")
+
+	opt.FollowAMP = true
+	c, err = e.Extract(context.Background(), ts.URL+"/story")
+	assert.Nil(t, err)
+	assert.True(t, c.FromAMP)
+	assert.Equal(t, ts.URL+"/story/amp", c.AMPURL)
+	assert.Contains(t, c.Description, "Lorem ipsum")
+}
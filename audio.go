@@ -0,0 +1,136 @@
+package readability
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Media is an audio file of a page, such as a podcast episode, for audio previews.
+type Media struct {
+	// URL is the absolute URL of the file.
+	URL string `json:"url"`
+
+	// Type is the MIME type of the file, like "audio/mpeg", declared by the page
+	// or guessed from the file extension. It is empty if unknown.
+	Type string `json:"type,omitempty"`
+
+	// Duration is the duration declared by structured data, as is, in ISO 8601 like "PT45M12S".
+	Duration string `json:"duration,omitempty"`
+}
+
+// audioTypes maps file extensions of audio files to their MIME types.
+var audioTypes = map[string]string{
+	".mp3": "audio/mpeg", ".m4a": "audio/mp4", ".aac": "audio/aac", ".ogg": "audio/ogg",
+	".oga": "audio/ogg", ".opus": "audio/opus", ".wav": "audio/wav", ".flac": "audio/flac",
+	".weba": "audio/webm",
+}
+
+// episodeTypes are the schema.org types of episodes whose audio files are extracted.
+var episodeTypes = []string{"PodcastEpisode", "RadioEpisode", "Episode", "Clip"}
+
+// audio returns the audio files of doc declared by JSON-LD podcast episodes and AudioObjects,
+// og:audio tags and <audio> elements, in that order, without duplicates.
+func audio(doc *goquery.Document, reqURL string) []Media {
+	var media []Media
+	seen := map[string]int{}
+	add := func(m Media) {
+		u, err := absPath(m.URL, reqURL)
+		if err != nil || !isValidURLStr(u) {
+			return
+		}
+		m.URL = u
+		if m.Type == "" {
+			m.Type = audioType(u)
+		}
+		if i, ok := seen[u]; ok {
+			// the same file declared again may have more details
+			media[i].Type = firstNonEmpty(media[i].Type, m.Type)
+			media[i].Duration = firstNonEmpty(media[i].Duration, m.Duration)
+			return
+		}
+		seen[u] = len(media)
+		media = append(media, m)
+	}
+
+	for _, obj := range jsonLDObjects(doc) {
+		if jsonLDType(obj, "AudioObject") {
+			add(jsonLDAudioObject(obj, ""))
+			continue
+		}
+		isEpisode := false
+		for _, t := range episodeTypes {
+			isEpisode = isEpisode || jsonLDType(obj, t)
+		}
+		if !isEpisode {
+			continue
+		}
+		duration := jsonLDString(firstNonNil(obj["duration"], obj["timeRequired"]))
+		for _, m := range jsonLDAudio(firstNonNil(obj["associatedMedia"], obj["audio"]), duration) {
+			add(m)
+		}
+	}
+
+	doc.Find(`meta[property="og:audio"], meta[property="og:audio:url"], meta[property="og:audio:secure_url"]`).Each(func(_ int, s *goquery.Selection) {
+		add(Media{URL: strings.TrimSpace(s.AttrOr("content", "")),
+			Type: metaContent(doc, `meta[property="og:audio:type"]`)})
+	})
+
+	doc.Find("audio").Each(func(_ int, s *goquery.Selection) {
+		if src := strings.TrimSpace(s.AttrOr("src", "")); src != "" {
+			add(Media{URL: src, Type: strings.TrimSpace(s.AttrOr("type", ""))})
+		}
+		s.Find("source[src]").Each(func(_ int, source *goquery.Selection) {
+			add(Media{URL: strings.TrimSpace(source.AttrOr("src", "")), Type: strings.TrimSpace(source.AttrOr("type", ""))})
+		})
+	})
+	return media
+}
+
+// jsonLDAudio returns the audio files of v, the associatedMedia or the audio of an episode,
+// which can be a URL string, an AudioObject (or a MediaObject), or an array of them.
+// duration is used for objects without their own duration.
+func jsonLDAudio(v interface{}, duration string) []Media {
+	switch t := v.(type) {
+	case string:
+		return []Media{{URL: t, Duration: duration}}
+	case []interface{}:
+		var media []Media
+		for _, e := range t {
+			media = append(media, jsonLDAudio(e, duration)...)
+		}
+		return media
+	case map[string]interface{}:
+		if t["@type"] == nil || jsonLDType(t, "AudioObject") || jsonLDType(t, "MediaObject") {
+			return []Media{jsonLDAudioObject(t, duration)}
+		}
+	}
+	return nil
+}
+
+// jsonLDAudioObject returns the audio file of obj, an AudioObject.
+func jsonLDAudioObject(obj map[string]interface{}, duration string) Media {
+	m := Media{
+		URL:      jsonLDURL(firstNonNil(obj["contentUrl"], obj["url"])),
+		Duration: firstNonEmpty(jsonLDString(obj["duration"]), duration),
+	}
+	// encodingFormat is a MIME type or a file extension like "mp3"
+	format := strings.ToLower(jsonLDString(obj["encodingFormat"]))
+	if strings.Contains(format, "/") {
+		m.Type = format
+	} else if format != "" {
+		m.Type = audioTypes["."+strings.TrimPrefix(format, ".")]
+	}
+	return m
+}
+
+// audioType returns the MIME type of the audio file at u guessed from its extension, or "".
+func audioType(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return audioTypes[strings.ToLower(path.Ext(parsed.Path))]
+}
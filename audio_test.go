@@ -0,0 +1,51 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudio(t *testing.T) {
+	for _, tc := range []struct {
+		html  string
+		media []Media
+	}{
+		{`<p>no audio</p>`, nil},
+		{`<script type="application/ld+json">{"@context":"https://schema.org","@type":"PodcastEpisode",
+"name":"Episode 1","timeRequired":"PT45M","associatedMedia":{"@type":"MediaObject","contentUrl":"/media/ep1.mp3"}}</script>`,
+			[]Media{{URL: "https://example.com/media/ep1.mp3", Type: "audio/mpeg", Duration: "PT45M"}}},
+		{`<script type="application/ld+json">{"@graph":[{"@type":"WebPage"},{"@type":"AudioObject",
+"contentUrl":"https://cdn.example.com/ep2","encodingFormat":"audio/mp4","duration":"PT1H2M"}]}</script>`,
+			[]Media{{URL: "https://cdn.example.com/ep2", Type: "audio/mp4", Duration: "PT1H2M"}}},
+		{`<script type="application/ld+json">{"@type":"PodcastEpisode","duration":"PT10M",
+"associatedMedia":[{"@type":"VideoObject","contentUrl":"/v.mp4"},{"@type":"AudioObject","url":"/a.ogg","encodingFormat":"ogg"}]}</script>`,
+			[]Media{{URL: "https://example.com/a.ogg", Type: "audio/ogg", Duration: "PT10M"}}},
+		{`<meta property="og:audio" content="https://example.com/ep3.mp3"><meta property="og:audio:secure_url" content="https://example.com/ep3.mp3">
+<meta property="og:audio:type" content="audio/mpeg">`,
+			[]Media{{URL: "https://example.com/ep3.mp3", Type: "audio/mpeg"}}},
+		{`<audio src="/a.m4a"></audio><audio controls><source src="/b.opus" type="audio/ogg; codecs=opus"><source src="/b.mp3"></audio>
+<audio src="data:audio/wav;base64,AAAA"></audio>`,
+			[]Media{{URL: "https://example.com/a.m4a", Type: "audio/mp4"},
+				{URL: "https://example.com/b.opus", Type: "audio/ogg; codecs=opus"},
+				{URL: "https://example.com/b.mp3", Type: "audio/mpeg"}}},
+		// details of a file declared again are merged
+		{`<script type="application/ld+json">{"@type":"PodcastEpisode","audio":"/ep4","duration":"PT5M"}</script>
+<audio src="/ep4" type="audio/mpeg"></audio>`,
+			[]Media{{URL: "https://example.com/ep4", Type: "audio/mpeg", Duration: "PT5M"}}},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		assert.Equal(t, tc.media, audio(doc, "https://example.com/podcast/1"), tc.html)
+	}
+}
+
+func TestExtractFromDocumentAudio(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Episode 5</title></head><body>
+<article><p>In this episode, we talk about the history of the radio and the podcasts of today.</p>
+<audio src="/ep5.mp3" controls></audio></article></body></html>`))
+	c, err := ExtractFromDocument(doc, "https://example.com/podcast/5", benchOption())
+	assert.Nil(t, err)
+	assert.Equal(t, []Media{{URL: "https://example.com/ep5.mp3", Type: "audio/mpeg"}}, c.Audio)
+}
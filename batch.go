@@ -0,0 +1,159 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// ErrParse is wrapped by errors returned when a response can't be parsed as HTML.
+var ErrParse = errors.New("failed to parse document")
+
+// HTTPError is returned when a page responds with a 4xx or 5xx status code.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected status code %v for %v", e.StatusCode, e.URL)
+}
+
+// ErrorCategory is a category of extraction failures.
+type ErrorCategory string
+
+// Error categories of BatchError.
+const (
+	CategoryTimeout      ErrorCategory = "timeout"
+	CategoryClientError  ErrorCategory = "4xx"
+	CategoryServerError  ErrorCategory = "5xx"
+	CategoryParse        ErrorCategory = "parse"
+	CategoryBotChallenge ErrorCategory = "bot-challenge"
+	CategoryUnsupported  ErrorCategory = "unsupported-content-type"
+	CategoryRedirects    ErrorCategory = "too-many-redirects"
+	CategoryNetwork      ErrorCategory = "network"
+	CategoryOther        ErrorCategory = "other"
+)
+
+// Categorize returns the category of err returned from extraction.
+func Categorize(err error) ErrorCategory {
+	var httpErr *HTTPError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return CategoryTimeout
+	case errors.As(err, &httpErr) && httpErr.StatusCode < 500:
+		return CategoryClientError
+	case errors.As(err, &httpErr):
+		return CategoryServerError
+	case errors.Is(err, ErrBotChallenge):
+		return CategoryBotChallenge
+	case errors.Is(err, ErrUnsupportedContentType):
+		return CategoryUnsupported
+	case errors.Is(err, ErrTooManyRedirects):
+		return CategoryRedirects
+	case errors.Is(err, ErrParse):
+		return CategoryParse
+	case errors.As(err, &netErr):
+		return CategoryNetwork
+	default:
+		return CategoryOther
+	}
+}
+
+// URLError is an extraction failure of a URL in a batch.
+type URLError struct {
+	URL      string
+	Category ErrorCategory
+	Err      error
+}
+
+func (e URLError) Error() string {
+	return fmt.Sprintf("%v: %v", e.URL, e.Err)
+}
+
+func (e URLError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError aggregates extraction failures of a batch.
+type BatchError struct {
+	// Errors contains the failures in the order of requested URLs.
+	Errors []URLError
+
+	// Counts is the number of failures by category.
+	Counts map[ErrorCategory]int
+}
+
+func (e *BatchError) add(reqURL string, err error) {
+	c := Categorize(err)
+	e.Errors = append(e.Errors, URLError{URL: reqURL, Category: c, Err: err})
+	e.Counts[c]++
+}
+
+func (e *BatchError) Error() string {
+	categories := make([]string, 0, len(e.Counts))
+	for c, n := range e.Counts {
+		categories = append(categories, fmt.Sprintf("%v: %v", c, n))
+	}
+	sort.Strings(categories)
+	return fmt.Sprintf("%v extractions failed (%v)", len(e.Errors), strings.Join(categories, ", "))
+}
+
+// URLs returns the failed URLs in the given categories, or all failed URLs if no category is given.
+func (e *BatchError) URLs(categories ...ErrorCategory) []string {
+	urls := []string{}
+	for _, ue := range e.Errors {
+		if len(categories) == 0 || containsCategory(categories, ue.Category) {
+			urls = append(urls, ue.URL)
+		}
+	}
+	return urls
+}
+
+func containsCategory(categories []ErrorCategory, c ErrorCategory) bool {
+	for _, e := range categories {
+		if e == c {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractAll extracts contents of urls concurrently and returns the results in the order of urls.
+// If any extraction fails, the returned error is a *BatchError
+// and the failed results have nil Content and non-nil Err.
+func (e *Extractor) ExtractAll(ctx context.Context, urls []string) ([]Result, error) {
+	results := make([]Result, len(urls))
+	index := make(map[string][]int, len(urls))
+	for i, u := range urls {
+		index[u] = append(index[u], i)
+		results[i].URL = u
+	}
+
+	for res := range e.All(ctx, urls) {
+		i := index[res.URL][0]
+		index[res.URL] = index[res.URL][1:]
+		results[i] = res
+	}
+
+	batchErr := &BatchError{Counts: map[ErrorCategory]int{}}
+	for i, res := range results {
+		if res.Err == nil && res.Content == nil {
+			// not extracted since ctx is done
+			results[i].Err = ctx.Err()
+		}
+		if results[i].Err != nil {
+			batchErr.add(res.URL, results[i].Err)
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return results, batchErr
+	}
+	return results, nil
+}
@@ -0,0 +1,61 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorize(t *testing.T) {
+	assert.Equal(t, CategoryTimeout, Categorize(context.DeadlineExceeded))
+	assert.Equal(t, CategoryClientError, Categorize(&HTTPError{StatusCode: 404}))
+	assert.Equal(t, CategoryServerError, Categorize(fmt.Errorf("wrapped: %w", &HTTPError{StatusCode: 503})))
+	assert.Equal(t, CategoryParse, Categorize(fmt.Errorf("%w: eof", ErrParse)))
+	assert.Equal(t, CategoryBotChallenge, Categorize(&BotChallengeError{Type: ChallengeCloudflare, StatusCode: 503}))
+	assert.Equal(t, CategoryUnsupported, Categorize(&UnsupportedContentTypeError{ContentType: "application/pdf"}))
+	assert.Equal(t, CategoryRedirects, Categorize(&url.Error{Op: "Get", Err: &TooManyRedirectsError{MaxRedirects: 10}}))
+	assert.Equal(t, CategoryOther, Categorize(fmt.Errorf("unknown")))
+}
+
+func TestExtractAll(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, `<html><head><meta property="og:title" content="OK" /></head></html>`)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	e := NewExtractor(NewOption())
+	e.Client = &http.Client{Timeout: 50 * time.Millisecond}
+	urls := []string{ts.URL + "/ok", ts.URL + "/missing", ts.URL + "/error", ts.URL + "/slow", ts.URL + "/ok"}
+	results, err := e.ExtractAll(context.Background(), urls)
+	assert.Equal(t, len(urls), len(results))
+	assert.Equal(t, "OK", results[0].Content.Title)
+	assert.Equal(t, "OK", results[4].Content.Title)
+
+	batchErr, ok := err.(*BatchError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(batchErr.Errors))
+	assert.Equal(t, 1, batchErr.Counts[CategoryClientError])
+	assert.Equal(t, 1, batchErr.Counts[CategoryServerError])
+	assert.Equal(t, 1, batchErr.Counts[CategoryTimeout])
+	assert.Equal(t, []string{ts.URL + "/error", ts.URL + "/slow"}, batchErr.URLs(CategoryServerError, CategoryTimeout))
+	assert.Equal(t, "3 extractions failed (4xx: 1, 5xx: 1, timeout: 1)", batchErr.Error())
+
+	results, err = e.ExtractAll(context.Background(), urls[:1])
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+}
@@ -0,0 +1,94 @@
+package readability
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrBotChallenge is wrapped by errors returned when a page responds with
+// a bot-protection challenge or a waiting room instead of its content.
+var ErrBotChallenge = errors.New("bot challenge")
+
+// ChallengeType is the type of a bot-protection challenge.
+type ChallengeType string
+
+// Challenge types of BotChallengeError.
+const (
+	ChallengeCloudflare ChallengeType = "cloudflare"
+	ChallengeQueueIt    ChallengeType = "queue-it"
+	ChallengeDataDome   ChallengeType = "datadome"
+	ChallengePerimeterX ChallengeType = "perimeterx"
+)
+
+// BotChallengeError is returned when a page responds with a bot-protection challenge.
+// It wraps ErrBotChallenge.
+type BotChallengeError struct {
+	URL  string
+	Type ChallengeType
+
+	// StatusCode is the status code of the challenge response, or 0 if the page was not requested.
+	StatusCode int
+}
+
+func (e *BotChallengeError) Error() string {
+	return fmt.Sprintf("%v challenge for %v", e.Type, e.URL)
+}
+
+func (e *BotChallengeError) Unwrap() error {
+	return ErrBotChallenge
+}
+
+// maxChallengeTextLength is the maximum text length of challenge pages.
+// Longer pages are regular pages embedding bot-protection scripts.
+const maxChallengeTextLength = 2000
+
+var challengeSignatures = []struct {
+	Type     ChallengeType
+	Selector string
+}{
+	{ChallengeCloudflare, `#challenge-form, #challenge-running, #challenge-stage, #cf-challenge-running, #cf-please-wait, script[src*="/cdn-cgi/challenge-platform/h/"]`},
+	{ChallengeQueueIt, `[src*="queue-it.net"], [href*="queue-it.net"], [action*="queue-it.net"], #MainPart_divProgressbar`},
+	{ChallengeDataDome, `[src*="captcha-delivery.com"]`},
+	{ChallengePerimeterX, `#px-captcha`},
+}
+
+var challengeTitles = map[string]ChallengeType{
+	"just a moment...":                 ChallengeCloudflare,
+	"attention required! | cloudflare": ChallengeCloudflare,
+}
+
+// botChallenge returns the type of the challenge if doc is a bot-protection challenge page,
+// otherwise empty string.
+func botChallenge(doc *goquery.Document) ChallengeType {
+	if len(strings.TrimSpace(doc.Find("body").Text())) > maxChallengeTextLength {
+		return ""
+	}
+	title := strings.ToLower(strings.TrimSpace(doc.Find("title").First().Text()))
+	if t, ok := challengeTitles[title]; ok {
+		return t
+	}
+	for _, s := range challengeSignatures {
+		if doc.Find(s.Selector).Length() > 0 {
+			return s.Type
+		}
+	}
+	return ""
+}
+
+// responseBotChallenge returns the type of the challenge if resp is a bot-protection challenge
+// according to its headers or the final URL after redirects, otherwise empty string.
+func responseBotChallenge(resp *http.Response) ChallengeType {
+	switch {
+	case strings.EqualFold(resp.Header.Get("Cf-Mitigated"), "challenge"):
+		return ChallengeCloudflare
+	case resp.Request != nil && strings.HasSuffix(resp.Request.URL.Hostname(), "queue-it.net"):
+		return ChallengeQueueIt
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-Datadome") != "":
+		return ChallengeDataDome
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotChallenge(t *testing.T) {
+	tests := []struct {
+		html string
+		want ChallengeType
+	}{
+		{`<html><head><title>Just a moment...</title></head><body>Checking your browser before accessing example.com.</body></html>`, ChallengeCloudflare},
+		{`<html><body><form id="challenge-form" action="/?__cf_chl_f_tk=x"></form></body></html>`, ChallengeCloudflare},
+		{`<html><body><div id="MainPart_divProgressbar"></div>You are now in line.</body></html>`, ChallengeQueueIt},
+		{`<html><body><script src="https://ct.captcha-delivery.com/c.js"></script></body></html>`, ChallengeDataDome},
+		{`<html><body><div id="px-captcha"></div>Press and hold</body></html>`, ChallengePerimeterX},
+		{`<html><body><p>Hello</p></body></html>`, ""},
+		{`<html><body><p>` + strings.Repeat("Long article text. ", 200) + `</p><script src="https://static.queue-it.net/script/queueclient.min.js"></script></body></html>`, ""},
+	}
+	for _, tt := range tests {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		assert.Equal(t, tt.want, botChallenge(doc), tt.html)
+	}
+}
+
+func TestExtractorExtractBotChallenge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cloudflare":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<html><head><title>Just a moment...</title></head><body>Checking your browser...</body></html>`)
+		case "/mitigated":
+			w.Header().Set("Cf-Mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+		case "/forbidden":
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		case "/ok":
+			fmt.Fprint(w, `<html><body><div id="px-captcha"></div></body></html>`)
+		}
+	}))
+	defer ts.Close()
+
+	e := NewExtractor(NewOption())
+	_, err := e.Extract(context.Background(), ts.URL+"/cloudflare")
+	assert.True(t, errors.Is(err, ErrBotChallenge))
+	assert.Equal(t, &BotChallengeError{URL: ts.URL + "/cloudflare", Type: ChallengeCloudflare, StatusCode: 503}, err)
+
+	_, err = e.Extract(context.Background(), ts.URL+"/mitigated")
+	assert.Equal(t, &BotChallengeError{URL: ts.URL + "/mitigated", Type: ChallengeCloudflare, StatusCode: 403}, err)
+
+	_, err = e.Extract(context.Background(), ts.URL+"/forbidden")
+	assert.Equal(t, &HTTPError{URL: ts.URL + "/forbidden", StatusCode: 403}, err)
+
+	_, err = e.Extract(context.Background(), ts.URL+"/ok")
+	assert.Equal(t, &BotChallengeError{URL: ts.URL + "/ok", Type: ChallengePerimeterX}, err)
+}
+
+func TestExtractStatusCode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cloudflare":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<html><head><title>Just a moment...</title></head><body>Checking your browser...</body></html>`)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<html><head><title>Not Found</title></head><body><p>The page is gone.</p></body></html>`)
+		case "/error":
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	_, err := Extract(ts.URL+"/cloudflare", NewOption())
+	assert.Equal(t, &BotChallengeError{URL: ts.URL + "/cloudflare", Type: ChallengeCloudflare, StatusCode: 503}, err)
+
+	_, err = Extract(ts.URL+"/missing", NewOption())
+	assert.Equal(t, &HTTPError{URL: ts.URL + "/missing", StatusCode: 404}, err)
+
+	_, err = Extract(ts.URL+"/error", NewOption())
+	assert.Equal(t, &HTTPError{URL: ts.URL + "/error", StatusCode: 500}, err)
+}
@@ -0,0 +1,137 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errImageHostSkipped is returned by image probes skipped since their host timed out.
+var errImageHostSkipped = errors.New("image host skipped after timeouts")
+
+// HostBreaker skips image probes to hosts which timed out, so that a flaky CDN doesn't
+// make every probe wait for Option.ImageRequestTimeout.
+//
+// Each extraction has its own breaker skipping a host after its first timeout.
+// A HostBreaker set as Option.ImageHostBreaker is shared by the extractions using the option,
+// so that later extractions skip the host too. It is safe for concurrent use.
+type HostBreaker struct {
+	// Threshold is the number of consecutive timeouts of a host which opens the breaker,
+	// skipping the host. If 0, 1 is used.
+	Threshold int
+
+	// Cooldown is how long the host is skipped after the breaker is opened. Then probes
+	// to the host are sent again, and the next timeout opens the breaker again.
+	// If 0, the host is skipped as long as the breaker is used.
+	Cooldown time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostTimeouts
+}
+
+// hostTimeouts is the state of a host of HostBreaker.
+type hostTimeouts struct {
+	count    int
+	openedAt time.Time
+}
+
+// NewHostBreaker returns a HostBreaker skipping a host for cooldown after threshold consecutive timeouts.
+func NewHostBreaker(threshold int, cooldown time.Duration) *HostBreaker {
+	return &HostBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+func (b *HostBreaker) threshold() int {
+	if b.Threshold <= 0 {
+		return 1
+	}
+	return b.Threshold
+}
+
+// Allow returns true if requests to host are sent, that is, its breaker is not open.
+// A nil HostBreaker allows all hosts.
+func (b *HostBreaker) Allow(host string) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := b.hosts[host]
+	if h == nil || h.count < b.threshold() {
+		return true
+	}
+	if b.Cooldown > 0 && time.Since(h.openedAt) >= b.Cooldown {
+		h.count = b.threshold() - 1
+		return true
+	}
+	return false
+}
+
+// Timeout records a timeout of a request to host.
+func (b *HostBreaker) Timeout(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.hosts == nil {
+		b.hosts = map[string]*hostTimeouts{}
+	}
+	h := b.hosts[host]
+	if h == nil {
+		h = &hostTimeouts{}
+		b.hosts[host] = h
+	}
+	h.count++
+	if h.count == b.threshold() {
+		h.openedAt = time.Now()
+	}
+}
+
+// Success records a request to host which didn't time out, resetting its timeouts.
+func (b *HostBreaker) Success(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.hosts, host)
+}
+
+// imageHost returns the lowercased host of the image URL src, or "" if it has none.
+func imageHost(src string) string {
+	u, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// allowImageHost returns true if probes to host are allowed by the breakers of opt.
+func allowImageHost(host string, opt *Option) bool {
+	return host == "" || opt.imageHosts.Allow(host) && opt.ImageHostBreaker.Allow(host)
+}
+
+// reportImageHost records the result of a probe to host to the breakers of opt.
+func reportImageHost(host string, err error, opt *Option) {
+	if host == "" {
+		return
+	}
+	switch {
+	case isTimeout(err):
+		opt.imageHosts.Timeout(host)
+		opt.ImageHostBreaker.Timeout(host)
+	default:
+		opt.imageHosts.Success(host)
+		opt.ImageHostBreaker.Success(host)
+	}
+}
+
+// isTimeout returns true if err is a timeout of a request.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,96 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostBreaker(t *testing.T) {
+	var nilBreaker *HostBreaker
+	nilBreaker.Timeout("a.example.com")
+	assert.True(t, nilBreaker.Allow("a.example.com"))
+
+	b := NewHostBreaker(2, 0)
+	b.Timeout("a.example.com")
+	assert.True(t, b.Allow("a.example.com"))
+	b.Success("a.example.com")
+	b.Timeout("a.example.com")
+	assert.True(t, b.Allow("a.example.com"), "timeouts are reset by a success")
+	b.Timeout("a.example.com")
+	assert.False(t, b.Allow("a.example.com"))
+	assert.True(t, b.Allow("b.example.com"))
+
+	b = NewHostBreaker(0, 20*time.Millisecond)
+	b.Timeout("a.example.com")
+	assert.False(t, b.Allow("a.example.com"))
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, b.Allow("a.example.com"))
+	b.Timeout("a.example.com")
+	assert.False(t, b.Allow("a.example.com"), "a timeout after the cooldown opens the breaker again")
+}
+
+// blockingImageServer returns a server counting requests whose responses are blocked
+// until the returned release function is called, so that every probe times out.
+func blockingImageServer() (ts *httptest.Server, requests *int32, release func()) {
+	requests = new(int32)
+	unblock := make(chan struct{})
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		<-unblock
+		w.Write(pngBytes(800, 600))
+	}))
+	return ts, requests, func() { close(unblock) }
+}
+
+func TestProbeImageHostBreaker(t *testing.T) {
+	ts, requests, release := blockingImageServer()
+	defer ts.Close()
+	defer release()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 20
+	opt.imageHosts = NewHostBreaker(1, 0)
+	_, _, err := probeImage(ts.URL+"/a.png", opt)
+	assert.True(t, isTimeout(err), "%v", err)
+	assert.False(t, opt.imageHosts.Allow(imageHost(ts.URL)))
+	before := atomic.LoadInt32(requests)
+	_, _, err = probeImage(ts.URL+"/b.png", opt)
+	assert.Equal(t, errImageHostSkipped, err)
+	assert.Equal(t, before, atomic.LoadInt32(requests))
+}
+
+func TestExtractFromDocumentImageHostBreaker(t *testing.T) {
+	ts, requests, release := blockingImageServer()
+	defer ts.Close()
+	defer release()
+	host := imageHost(ts.URL)
+
+	html := `<html><body><article><p>A long enough paragraph of the article, which is written as sentences.</p>
+<img src="/a.png"></article></body></html>`
+	opt := NewOption()
+	opt.ImageRequestTimeout = 20
+	// the extraction waits for the probe to time out by itself
+	opt.ImageProbingTimeout = 60000
+	opt.ImageHostBreaker = NewHostBreaker(1, time.Minute)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	_, err := ExtractFromDocument(doc, ts.URL+"/1", opt)
+	assert.Nil(t, err)
+	assert.False(t, opt.ImageHostBreaker.Allow(host), "the timeout opens the shared breaker")
+
+	// the host is skipped by later extractions sharing the breaker
+	opt.ImageHostBreaker = NewHostBreaker(1, time.Minute)
+	opt.ImageHostBreaker.Timeout(host)
+	before := atomic.LoadInt32(requests)
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err := ExtractFromDocument(doc, ts.URL+"/2", opt)
+	assert.Nil(t, err)
+	assert.Empty(t, c.Images)
+	assert.Equal(t, before, atomic.LoadInt32(requests))
+}
@@ -0,0 +1,113 @@
+package readability
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// sentenceCaseLanguages capitalize only the first word (and proper nouns) of titles.
+var sentenceCaseLanguages = map[string]bool{
+	"fr": true, "es": true, "it": true, "pt": true, "ca": true, "ro": true,
+	"nl": true, "sv": true, "no": true, "nb": true, "nn": true, "da": true, "fi": true,
+	"pl": true, "cs": true, "sk": true, "hu": true, "ru": true, "uk": true, "tr": true, "az": true,
+}
+
+// englishMinorWords are lowercased in English titles unless they are the first or last word.
+var englishMinorWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "but": true, "or": true, "nor": true,
+	"for": true, "so": true, "yet": true, "as": true, "at": true, "by": true, "in": true,
+	"of": true, "off": true, "on": true, "per": true, "to": true, "up": true, "via": true,
+	"vs": true, "from": true, "with": true, "into": true, "over": true,
+}
+
+// acronyms are kept upper-cased when an all-caps title is re-cased.
+var acronyms = map[string]bool{
+	"AI": true, "BBC": true, "CEO": true, "CIA": true, "EU": true, "FBI": true, "NASA": true,
+	"NBA": true, "NFL": true, "NHS": true, "TV": true, "UK": true, "UN": true, "USA": true,
+}
+
+// TitleCase returns s re-cased for display according to the conventions of locale,
+// a BCP 47 language tag like "en-US":
+// English titles capitalize every word except articles, conjunctions and short prepositions,
+// German titles capitalize every word, and most other European languages capitalize only the first word.
+// Turkish and Azerbaijani dotted and dotless i are cased correctly.
+// Words with digits and well-known acronyms are kept as is.
+func TitleCase(s, locale string) string {
+	lang := baseLanguage(locale)
+	special := unicode.SpecialCase(nil)
+	if lang == "tr" || lang == "az" {
+		special = unicode.TurkishCase
+	}
+
+	words := strings.Fields(s)
+	for i, w := range words {
+		first := i == 0 || strings.HasSuffix(words[i-1], ":")
+		last := i == len(words)-1
+		switch {
+		case acronyms[strings.Trim(w, `"'“”‘’()[],.!?:;`)] || strings.IndexFunc(w, unicode.IsDigit) >= 0:
+		case first:
+			words[i] = capitalize(w, special)
+		case sentenceCaseLanguages[lang]:
+			words[i] = strings.ToLowerSpecial(special, w)
+		case lang == "en" && !last && englishMinorWords[strings.ToLower(w)]:
+			words[i] = strings.ToLower(w)
+		default:
+			words[i] = capitalize(w, special)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize returns w with the first letter upper-cased and the rest lower-cased.
+// Each part of hyphenated words is capitalized, like "Self-Driving".
+func capitalize(w string, special unicode.SpecialCase) string {
+	parts := strings.Split(w, "-")
+	for i, p := range parts {
+		j := strings.IndexFunc(p, unicode.IsLetter)
+		if j < 0 {
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(p[j:])
+		parts[i] = p[:j] + string(special.ToUpper(r)) + strings.ToLowerSpecial(special, p[j+n:])
+	}
+	return strings.Join(parts, "-")
+}
+
+// isAllCaps returns true if s has at least 8 cased letters and none of them is lower-cased,
+// like "MAN BITES DOG IN SHOCK ATTACK".
+func isAllCaps(s string) bool {
+	upper := 0
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	return upper >= 8
+}
+
+// displayTitle returns title re-cased with TitleCase if it is written in all capitals.
+// The locale is opt.TitleLocale, the lang attribute of <html>, then English.
+func displayTitle(doc *goquery.Document, title string, opt *Option) string {
+	if !isAllCaps(title) {
+		return title
+	}
+	locale := opt.TitleLocale
+	if locale == "" {
+		locale = doc.Find("html").AttrOr("lang", "en")
+	}
+	return TitleCase(title, locale)
+}
+
+// baseLanguage returns the lower-cased primary language subtag of locale, like "en" for "en-US".
+func baseLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(locale))
+}
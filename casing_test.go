@@ -0,0 +1,47 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitleCase(t *testing.T) {
+	tests := []struct {
+		title  string
+		locale string
+		want   string
+	}{
+		{"MAN BITES DOG IN SHOCK ATTACK", "en", "Man Bites Dog in Shock Attack"},
+		{"THE SELF-DRIVING CAR THEY DON'T WANT YOU TO SEE", "en-GB", "The Self-Driving Car They Don't Want You to See"},
+		{"NASA REVEALS 2024 PLANS: A NEW ERA", "en", "NASA Reveals 2024 Plans: A New Era"},
+		{"LE PRÉSIDENT À PARIS", "fr-FR", "Le président à paris"},
+		{"DIE NEUE REGIERUNG", "de", "Die Neue Regierung"},
+		{"İSTANBUL'DA BÜYÜK YANGIN", "tr", "İstanbul'da büyük yangın"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, TitleCase(tt.title, tt.locale), tt.title)
+	}
+}
+
+func TestDisplayTitle(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html lang="es"><body></body></html>`))
+	opt := NewOption()
+	assert.Equal(t, "Already Fine", displayTitle(doc, "Already Fine", opt))
+	assert.Equal(t, "iPHONE REVIEW", displayTitle(doc, "iPHONE REVIEW", opt))
+	assert.Equal(t, "Noticias de hoy", displayTitle(doc, "NOTICIAS DE HOY", opt))
+	opt.TitleLocale = "en"
+	assert.Equal(t, "Noticias De Hoy", displayTitle(doc, "NOTICIAS DE HOY", opt))
+}
+
+func TestExtractFromDocumentWithTitleCase(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>WORLD WAR OVER</title></head><body></body></html>`))
+	opt := NewOption()
+	opt.TitleCase = true
+	c, err := ExtractFromDocument(doc, "http://example.com/", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "World War Over", c.Title)
+	assert.Equal(t, "WORLD WAR OVER", c.RawTitle)
+}
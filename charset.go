@@ -0,0 +1,196 @@
+package readability
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// CharsetPolicy decides the charset of a page when the charset of the Content-Type header
+// conflicts with the charset declared by <meta> in the page.
+type CharsetPolicy string
+
+// Charset policies.
+const (
+	// CharsetPolicyAuto uses the header charset, unless the page is not valid in it
+	// or the page is valid UTF-8 declared by <meta>. It fixes most legacy pages
+	// whose servers send a wrong default charset.
+	CharsetPolicyAuto CharsetPolicy = "auto"
+
+	// CharsetPolicyHeader always uses the header charset, as specified by HTML.
+	CharsetPolicyHeader CharsetPolicy = "header"
+
+	// CharsetPolicyMeta always uses the <meta> charset.
+	CharsetPolicyMeta CharsetPolicy = "meta"
+)
+
+// CharsetReader returns a reader converting input in charset into UTF-8.
+// It has the same signature as charset.NewReaderLabel of golang.org/x/net/html/charset.
+type CharsetReader func(charset string, input io.Reader) (io.Reader, error)
+
+// CharsetDecision describes which charset was used for decoding a page and why.
+type CharsetDecision struct {
+	// Header is the charset of the Content-Type header, if exists.
+	Header string `json:"header,omitempty"`
+
+	// Meta is the charset declared by <meta> in the page, if exists.
+	Meta string `json:"meta,omitempty"`
+
+	// Charset is the charset used for decoding the page.
+	Charset string `json:"charset"`
+
+	// Reason describes why Charset was used.
+	Reason string `json:"reason"`
+
+	// Decoded is false if Charset is not supported, then the page is read as UTF-8.
+	Decoded bool `json:"decoded"`
+}
+
+// charsetSniffLength is the number of leading bytes searched for a <meta> charset declaration.
+const charsetSniffLength = 1024
+
+var metaCharset = regexp.MustCompile(`(?i)<meta\s[^>]*?charset\s*=\s*["']?\s*([\w.:-]+)`)
+
+// charsetAliases maps charset labels to the canonical names used in CharsetDecision.
+var charsetAliases = map[string]string{
+	"utf8":              "utf-8",
+	"unicode-1-1-utf-8": "utf-8",
+	"latin1":            "iso-8859-1",
+	"l1":                "iso-8859-1",
+	"ascii":             "us-ascii",
+	"cp1252":            "windows-1252",
+	"x-cp1252":          "windows-1252",
+	"ks_c_5601-1987":    "euc-kr",
+	"x-sjis":            "shift_jis",
+	"sjis":              "shift_jis",
+	"ms_kanji":          "shift_jis",
+}
+
+func normalizeCharset(label string) string {
+	label = strings.ToLower(strings.Trim(strings.TrimSpace(label), `"'`))
+	if c, ok := charsetAliases[label]; ok {
+		return c
+	}
+	return label
+}
+
+// headerCharset returns the charset parameter of contentType, or empty string.
+func headerCharset(contentType string) string {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return normalizeCharset(params["charset"])
+}
+
+// declaredCharset returns the charset declared by <meta charset> or
+// <meta http-equiv="Content-Type"> in the leading bytes of b, or empty string.
+func declaredCharset(b []byte) string {
+	if len(b) > charsetSniffLength {
+		b = b[:charsetSniffLength]
+	}
+	m := metaCharset.FindSubmatch(b)
+	if m == nil {
+		return ""
+	}
+	return normalizeCharset(string(m[1]))
+}
+
+// decideCharset returns the charset of page b served with contentType, following policy.
+func decideCharset(b []byte, contentType string, policy CharsetPolicy) *CharsetDecision {
+	d := &CharsetDecision{Header: headerCharset(contentType), Meta: declaredCharset(b)}
+	switch {
+	case bytes.HasPrefix(b, []byte("\xef\xbb\xbf")):
+		d.Charset, d.Reason = "utf-8", "byte order mark"
+	case bytes.HasPrefix(b, []byte("\xff\xfe")):
+		d.Charset, d.Reason = "utf-16le", "byte order mark"
+	case bytes.HasPrefix(b, []byte("\xfe\xff")):
+		d.Charset, d.Reason = "utf-16be", "byte order mark"
+	case d.Header == "" && d.Meta == "":
+		d.Charset, d.Reason = "utf-8", "no charset declared"
+	case d.Header == "":
+		d.Charset, d.Reason = d.Meta, "only meta charset declared"
+	case d.Meta == "":
+		d.Charset, d.Reason = d.Header, "only header charset declared"
+	case d.Header == d.Meta:
+		d.Charset, d.Reason = d.Header, "header and meta charsets agree"
+	case policy == CharsetPolicyHeader:
+		d.Charset, d.Reason = d.Header, "header charset preferred by policy"
+	case policy == CharsetPolicyMeta:
+		d.Charset, d.Reason = d.Meta, "meta charset preferred by policy"
+	case d.Header == "utf-8" && !utf8.Valid(b):
+		d.Charset, d.Reason = d.Meta, "page is not valid in header charset utf-8"
+	case d.Meta == "utf-8" && utf8.Valid(b):
+		d.Charset, d.Reason = d.Meta, "page is valid in meta charset utf-8"
+	default:
+		d.Charset, d.Reason = d.Header, "header charset preferred on conflict"
+	}
+	return d
+}
+
+// decodeCharset returns page b decoded into UTF-8 according to d, updating d.Decoded.
+// ISO-8859-1 and US-ASCII are decoded as windows-1252 like browsers do (see the WHATWG Encoding Standard),
+// since pages labeled so often use the quotes and dashes of windows-1252.
+// Charsets other than them, UTF-8 and UTF-16 are decoded by cr if not nil.
+func decodeCharset(b []byte, d *CharsetDecision, cr CharsetReader) []byte {
+	d.Decoded = true
+	switch d.Charset {
+	case "utf-8":
+		return bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	case "us-ascii", "iso-8859-1", "windows-1252":
+		return decodeWindows1252(b)
+	case "utf-16le", "utf-16be", "utf-16":
+		return decodeUTF16(b, d.Charset == "utf-16be")
+	}
+	if cr != nil {
+		if r, err := cr(d.Charset, bytes.NewReader(b)); err == nil {
+			if decoded, err := ioutil.ReadAll(r); err == nil {
+				return decoded
+			}
+		}
+	}
+	logger.Warnf("decodeCharset: unsupported charset: %v", d.Charset)
+	d.Decoded = false
+	return b
+}
+
+// windows1252 are the characters of windows-1252 for the bytes 0x80 to 0x9F,
+// which are the C1 controls in ISO-8859-1. Undefined bytes are kept as the controls.
+var windows1252 = [32]rune{
+	'\u20ac', '\u0081', '\u201a', '\u0192', '\u201e', '\u2026', '\u2020', '\u2021',
+	'\u02c6', '\u2030', '\u0160', '\u2039', '\u0152', '\u008d', '\u017d', '\u008f',
+	'\u0090', '\u2018', '\u2019', '\u201c', '\u201d', '\u2022', '\u2013', '\u2014',
+	'\u02dc', '\u2122', '\u0161', '\u203a', '\u0153', '\u009d', '\u017e', '\u0178',
+}
+
+func decodeWindows1252(b []byte) []byte {
+	buf := make([]byte, 0, len(b))
+	for _, c := range b {
+		r := rune(c)
+		if c >= 0x80 && c < 0xa0 {
+			r = windows1252[c-0x80]
+		}
+		buf = utf8.AppendRune(buf, r)
+	}
+	return buf
+}
+
+func decodeUTF16(b []byte, bigEndian bool) []byte {
+	if bytes.HasPrefix(b, []byte("\xff\xfe")) || bytes.HasPrefix(b, []byte("\xfe\xff")) {
+		b = b[2:]
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		if bigEndian {
+			u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		} else {
+			u[i] = uint16(b[2*i+1])<<8 | uint16(b[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(u)))
+}
@@ -0,0 +1,95 @@
+package readability
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecideCharset(t *testing.T) {
+	utf8Page := []byte(`<html><head><meta charset="utf-8"><title>한국어</title></head></html>`)
+	eucKRPage := []byte("<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=euc-kr\"><title>\xc7\xd1</title></head></html>")
+	for _, tc := range []struct {
+		page        []byte
+		contentType string
+		policy      CharsetPolicy
+		charset     string
+	}{
+		{utf8Page, "", CharsetPolicyAuto, "utf-8"},
+		{utf8Page, "text/html; charset=ISO-8859-1", CharsetPolicyAuto, "utf-8"},
+		{utf8Page, "text/html; charset=ISO-8859-1", CharsetPolicyHeader, "iso-8859-1"},
+		{eucKRPage, "text/html; charset=UTF-8", CharsetPolicyAuto, "euc-kr"},
+		{eucKRPage, "text/html; charset=ks_c_5601-1987", CharsetPolicyAuto, "euc-kr"},
+		{eucKRPage, "text/html; charset=iso-8859-1", CharsetPolicyAuto, "iso-8859-1"},
+		{eucKRPage, "text/html; charset=iso-8859-1", CharsetPolicyMeta, "euc-kr"},
+		{append([]byte("\xef\xbb\xbf"), eucKRPage...), "text/html; charset=iso-8859-1", CharsetPolicyAuto, "utf-8"},
+	} {
+		d := decideCharset(tc.page, tc.contentType, tc.policy)
+		assert.Equal(t, tc.charset, d.Charset, "%v %v", tc.contentType, tc.policy)
+		assert.NotEmpty(t, d.Reason)
+	}
+}
+
+func TestDecodeCharset(t *testing.T) {
+	d := &CharsetDecision{Charset: "iso-8859-1"}
+	assert.Equal(t, "café", string(decodeCharset([]byte("caf\xe9"), d, nil)))
+	assert.True(t, d.Decoded)
+
+	// ISO-8859-1 and US-ASCII are windows-1252, whose quotes and dashes are common in titles
+	for _, charset := range []string{"iso-8859-1", "us-ascii", "windows-1252"} {
+		d = &CharsetDecision{Charset: charset}
+		assert.Equal(t, "\u201cCaf\u00e9\u201d \u2014 \u20ac5", string(decodeCharset([]byte("\x93Caf\xe9\x94 \x97 \x805"), d, nil)), charset)
+	}
+	assert.Equal(t, "windows-1252", normalizeCharset("CP1252"))
+
+	d = &CharsetDecision{Charset: "utf-16le"}
+	assert.Equal(t, "ab", string(decodeCharset([]byte("\xff\xfea\x00b\x00"), d, nil)))
+
+	d = &CharsetDecision{Charset: "euc-kr"}
+	assert.Equal(t, "\xc7\xd1", string(decodeCharset([]byte("\xc7\xd1"), d, nil)))
+	assert.False(t, d.Decoded)
+
+	cr := func(charset string, input io.Reader) (io.Reader, error) {
+		return strings.NewReader("한"), nil
+	}
+	d = &CharsetDecision{Charset: "euc-kr"}
+	assert.Equal(t, "한", string(decodeCharset([]byte("\xc7\xd1"), d, cr)))
+	assert.True(t, d.Decoded)
+}
+
+func TestExtractorCharset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+		io.WriteString(w, `<html><head><meta charset="utf-8"><title>한국어 뉴스</title></head><body><p>본문</p></body></html>`)
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	c, err := NewExtractor(opt).Extract(context.Background(), ts.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "한국어 뉴스", c.Title)
+	assert.Equal(t, "iso-8859-1", c.Charset.Header)
+	assert.Equal(t, "utf-8", c.Charset.Meta)
+	assert.Equal(t, "utf-8", c.Charset.Charset)
+}
+
+func TestExtractorWindows1252Title(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+		io.WriteString(w, "<html><head><title>\x93Caf\xe9\x94 \x96 the menu</title></head><body><p>Caf\xe9 menu</p></body></html>")
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	c, err := NewExtractor(opt).Extract(context.Background(), ts.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "“Café” – the menu", c.Title)
+	assert.Equal(t, "iso-8859-1", c.Charset.Charset)
+}
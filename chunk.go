@@ -0,0 +1,197 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Chunk is a part of the article split by Content.Chunks.
+type Chunk struct {
+	Text string `json:"text"`
+
+	// Tokens is the approximate number of tokens of Text. See Content.Chunks.
+	Tokens int `json:"tokens"`
+}
+
+// chunkSentenceEnd matches the end of a sentence followed by spaces,
+// including the Arabic question mark and full stop.
+var chunkSentenceEnd = regexp.MustCompile(`[.!?。！？؟۔]["')\]»]*\s+`)
+
+// Chunks splits the article into chunks of at most maxTokens tokens along paragraph boundaries,
+// for embedding pipelines. Paragraphs longer than maxTokens are split by sentences, and sentences
+// longer than maxTokens by words. Paragraphs in a chunk are separated by a blank line.
+//
+// Tokens are approximated by words separated by spaces, counting each Han, Hiragana and Katakana
+// character as a word. Paragraphs are Content.Paragraphs if they are known;
+// otherwise the description is split by sentences.
+// If maxTokens is not positive, the whole article is a single chunk.
+func (c *Content) Chunks(maxTokens int) []Chunk {
+	return c.ChunksWithOverlap(maxTokens, 0)
+}
+
+// ChunksWithOverlap acts same as Chunks, except that each chunk starts with the trailing paragraphs
+// (or sentences) of the previous chunk, up to overlapTokens tokens, so that context is not lost at boundaries.
+func (c *Content) ChunksWithOverlap(maxTokens, overlapTokens int) []Chunk {
+	paras := c.Paragraphs
+	if len(paras) == 0 {
+		paras = descriptionParagraphs(c.Description)
+	}
+	if len(paras) == 0 {
+		return []Chunk{}
+	}
+	if maxTokens <= 0 {
+		text := strings.Join(paras, "\n\n")
+		return []Chunk{{Text: text, Tokens: countTokens(text)}}
+	}
+
+	type unit struct {
+		text   string
+		tokens int
+		sep    string // separator from the previous unit
+	}
+	var units []unit
+	for _, p := range paras {
+		sep := "\n\n"
+		for _, s := range splitByTokens(p, maxTokens) {
+			units = append(units, unit{text: s, tokens: countTokens(s), sep: sep})
+			sep = " "
+		}
+	}
+
+	chunks := []Chunk{}
+	var cur []unit
+	curTokens := 0
+	emit := func() {
+		var b strings.Builder
+		for i, u := range cur {
+			if i > 0 {
+				b.WriteString(u.sep)
+			}
+			b.WriteString(u.text)
+		}
+		chunks = append(chunks, Chunk{Text: b.String(), Tokens: curTokens})
+	}
+	for _, u := range units {
+		if len(cur) > 0 && curTokens+u.tokens > maxTokens {
+			emit()
+			// keep the trailing units within overlapTokens, if they leave room for u
+			start, overlap := len(cur), 0
+			for start > 0 && overlap+cur[start-1].tokens <= overlapTokens {
+				start--
+				overlap += cur[start].tokens
+			}
+			if overlap+u.tokens > maxTokens {
+				start, overlap = len(cur), 0
+			}
+			cur, curTokens = append([]unit{}, cur[start:]...), overlap
+		}
+		cur = append(cur, u)
+		curTokens += u.tokens
+	}
+	emit()
+	return chunks
+}
+
+// descriptionParagraphs returns the paragraphs of desc, which may be HTML.
+func descriptionParagraphs(desc string) []string {
+	if strings.Contains(desc, "<") {
+		if doc, err := goquery.NewDocumentFromReader(strings.NewReader(desc)); err == nil {
+			return paragraphs(doc.Selection)
+		}
+	}
+	var ps []string
+	for _, p := range strings.Split(desc, "\n\n") {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// splitByTokens splits s into parts of at most maxTokens tokens,
+// by sentences if possible, otherwise by words or characters.
+func splitByTokens(s string, maxTokens int) []string {
+	if countTokens(s) <= maxTokens {
+		return []string{s}
+	}
+
+	var parts []string
+	var b strings.Builder
+	n := 0
+	add := func(piece string, tokens int, sep string) {
+		if n > 0 && n+tokens > maxTokens {
+			parts = append(parts, b.String())
+			b.Reset()
+			n = 0
+		}
+		if n > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(piece)
+		n += tokens
+	}
+
+	for _, sentence := range splitSentences(s) {
+		if t := countTokens(sentence); t <= maxTokens {
+			add(sentence, t, " ")
+			continue
+		}
+		for _, word := range strings.Fields(sentence) {
+			if t := countTokens(word); t <= maxTokens {
+				add(word, t, " ")
+				continue
+			}
+			for i, g := range graphemes(word) {
+				sep := ""
+				if i == 0 {
+					sep = " "
+				}
+				add(g, countTokens(g), sep)
+			}
+		}
+	}
+	if n > 0 {
+		parts = append(parts, b.String())
+	}
+	return parts
+}
+
+func splitSentences(s string) []string {
+	var sentences []string
+	start := 0
+	for _, m := range chunkSentenceEnd.FindAllStringIndex(s, -1) {
+		if sentence := strings.TrimSpace(s[start:m[1]]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = m[1]
+	}
+	if sentence := strings.TrimSpace(s[start:]); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// countTokens returns the approximate number of tokens of s.
+func countTokens(s string) int {
+	n := 0
+	for _, word := range strings.Fields(s) {
+		cjk := 0
+		for _, r := range word {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+				cjk++
+			}
+		}
+		if cjk > 0 {
+			n += cjk
+			if cjk < len([]rune(word)) {
+				n++ // for non-CJK characters in the word
+			}
+		} else {
+			n++
+		}
+	}
+	return n
+}
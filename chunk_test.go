@@ -0,0 +1,78 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunks(t *testing.T) {
+	c := &Content{Paragraphs: []string{
+		"one two three four",
+		"five six",
+		"seven eight nine. ten eleven twelve.",
+	}}
+
+	chunks := c.Chunks(6)
+	assert.Equal(t, []Chunk{
+		{Text: "one two three four\n\nfive six", Tokens: 6},
+		{Text: "seven eight nine. ten eleven twelve.", Tokens: 6},
+	}, chunks)
+
+	chunks = c.Chunks(4)
+	assert.Equal(t, []string{"one two three four", "five six", "seven eight nine.", "ten eleven twelve."}, chunkTexts(chunks))
+
+	chunks = c.ChunksWithOverlap(8, 2)
+	assert.Equal(t, []string{"one two three four\n\nfive six", "five six\n\nseven eight nine. ten eleven twelve."}, chunkTexts(chunks))
+
+	// no overlap if it leaves no room for the next paragraph
+	chunks = c.ChunksWithOverlap(7, 2)
+	assert.Equal(t, []string{"one two three four\n\nfive six", "seven eight nine. ten eleven twelve."}, chunkTexts(chunks))
+
+	chunks = c.Chunks(0)
+	assert.Equal(t, 1, len(chunks))
+	assert.Equal(t, 12, chunks[0].Tokens)
+
+	assert.Empty(t, (&Content{}).Chunks(10))
+}
+
+func TestChunksWithoutParagraphs(t *testing.T) {
+	c := &Content{Description: "<p>First paragraph.</p><div>Second <b>paragraph</b>.</div>"}
+	assert.Equal(t, []string{"First paragraph.", "Second paragraph ."}, chunkTexts(c.Chunks(3)))
+
+	c = &Content{Description: "日本語の文章です。次の文章です。"}
+	chunks := c.Chunks(6)
+	assert.Equal(t, "日本語の文章", chunks[0].Text)
+	assert.Equal(t, 6, chunks[0].Tokens)
+}
+
+func TestChunksUnicode(t *testing.T) {
+	c := &Content{Paragraphs: []string{"هل هذا صحيح؟ نعم، هذا صحيح."}}
+	assert.Equal(t, []string{"هل هذا صحيح؟", "نعم، هذا صحيح."}, chunkTexts(c.Chunks(3)))
+
+	// a word is split between characters, but never inside a combining sequence
+	c = &Content{Paragraphs: []string{"か\u3099き\u3099"}}
+	assert.Equal(t, []string{"か\u3099", "き\u3099"}, chunkTexts(c.Chunks(2)))
+}
+
+func TestChunksFromExtraction(t *testing.T) {
+	p := strings.Repeat("word ", 60)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><div class="article"><p>` + p + `</p><p>` + p + `</p><p>` + p + `</p></div></body>`))
+	opt := NewOption()
+	opt.PreferHeroImage = false
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	chunks := c.Chunks(100)
+	assert.Equal(t, 3, len(chunks))
+	assert.Equal(t, 60, chunks[0].Tokens)
+}
+
+func chunkTexts(chunks []Chunk) []string {
+	texts := []string{}
+	for _, c := range chunks {
+		texts = append(texts, c.Text)
+	}
+	return texts
+}
@@ -0,0 +1,103 @@
+package readability
+
+import "strings"
+
+// CleanRuleSet toggles the rules of conditional cleaning (see Option.CleanConditionally),
+// identified by the reason codes of CleanDecision they decide with. Rules mapped to false
+// are not applied, and the other rules are. A nil set applies all rules.
+//
+// ReasonNegativeScore removes nodes whose score plus class weight is negative, and
+// ReasonManyCommas keeps nodes containing more than 10 commas without the other rules.
+// The other rules remove nodes by their contents and are evaluated in the order of
+// DefaultCleanRules.
+type CleanRuleSet map[ReasonCode]bool
+
+// Enabled returns true if the rule of code is applied.
+func (s CleanRuleSet) Enabled(code ReasonCode) bool {
+	enabled, ok := s[code]
+	return !ok || enabled
+}
+
+// cleanRules are the reason codes of all rules of conditional cleaning.
+var cleanRules = []ReasonCode{ReasonNegativeScore, ReasonManyCommas, ReasonTooManyImages,
+	ReasonTooManyListItems, ReasonTooManyInputs, ReasonTooShort, ReasonTooManyLinks, ReasonTooManyEmbeds}
+
+// DefaultCleanRules returns the reason codes of all rules of conditional cleaning,
+// which are applied unless disabled by Option.CleanRules. The slice is new on each call.
+func DefaultCleanRules() []ReasonCode {
+	return append([]ReasonCode(nil), cleanRules...)
+}
+
+func isCleanRule(code ReasonCode) bool {
+	for _, c := range cleanRules {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanStats is a node evaluated by the content rules of conditional cleaning.
+type cleanStats struct {
+	tagName string
+
+	// counts contains the numbers of <p>, <img>, <li>, <a>, <embed> and <input> in the node,
+	// where <li> is discounted by 100 and <img> by the <img>s under <noscript>.
+	counts map[string]int
+
+	textLength  int
+	linkDensity float64
+	weight      float64
+}
+
+// cleanCountedTags are the tags counted in cleanStats.counts.
+var cleanCountedTags = []string{"p", "img", "li", "a", "embed", "input"}
+
+// newCleanStats returns the statistics of the node of tagName and class weight from st.
+func newCleanStats(tagName string, weight float64, st *subtreeStats) *cleanStats {
+	counts := map[string]int{}
+	for _, tag := range cleanCountedTags {
+		counts[tag] = st.counts[tag]
+	}
+	counts["li"] -= 100
+	// For every img under a noscript tag discount one from the count to avoid double counting
+	counts["img"] -= st.noscriptImages
+	return &cleanStats{tagName: tagName, counts: counts, textLength: len(strings.TrimSpace(st.text)),
+		linkDensity: st.linkDensity(), weight: weight}
+}
+
+// contentCleanRules are the rules of conditional cleaning by contents, in the order of evaluation.
+var contentCleanRules = []struct {
+	code   ReasonCode
+	remove func(c *cleanStats, opt *Option) bool
+}{
+	{ReasonTooManyImages, func(c *cleanStats, opt *Option) bool {
+		return c.counts["img"] > c.counts["p"] && c.counts["img"] > 1
+	}},
+	{ReasonTooManyListItems, func(c *cleanStats, opt *Option) bool {
+		return c.counts["li"] > c.counts["p"] && c.tagName != "ul" && c.tagName != "ol"
+	}},
+	{ReasonTooManyInputs, func(c *cleanStats, opt *Option) bool {
+		return c.counts["input"]*3 > c.counts["p"]
+	}},
+	{ReasonTooShort, func(c *cleanStats, opt *Option) bool {
+		return c.textLength < opt.MinTextLength && c.counts["img"] != 1
+	}},
+	{ReasonTooManyLinks, func(c *cleanStats, opt *Option) bool {
+		return c.weight < 25 && c.linkDensity > 0.2 || c.weight >= 25 && c.linkDensity > 0.5
+	}},
+	{ReasonTooManyEmbeds, func(c *cleanStats, opt *Option) bool {
+		return c.counts["embed"] == 1 && c.textLength < 75 || c.counts["embed"] > 1
+	}},
+}
+
+// conditionalCleanCode returns the code of the first content rule enabled by opt.CleanRules
+// removing the node of c, or "" if it is kept.
+func conditionalCleanCode(c *cleanStats, opt *Option) ReasonCode {
+	for _, r := range contentCleanRules {
+		if opt.CleanRules.Enabled(r.code) && r.remove(c, opt) {
+			return r.code
+		}
+	}
+	return ""
+}
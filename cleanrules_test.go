@@ -0,0 +1,76 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanRules(t *testing.T) {
+	long := strings.Repeat("Some long text of the article. ", 4)
+	for _, tc := range []struct {
+		html string
+		code ReasonCode
+	}{
+		{`<div class="sidebar"><p>` + long + `</p></div>`, ReasonNegativeScore},
+		{`<div class="sidebar"><p>` + strings.Repeat("a, ", 11) + `</p></div>`, ReasonNegativeScore},
+		{`<div><p>` + strings.Repeat("a, ", 11) + `</p></div>`, ReasonManyCommas},
+		{`<div><p>` + long + `</p><img src="a.png"><img src="b.png"></div>`, ReasonTooManyImages},
+		{`<div><p>` + long + `</p>` + strings.Repeat("<li>item</li>", 102) + `</div>`, ReasonTooManyListItems},
+		{`<div><p>` + long + `</p><input><input></div>`, ReasonTooManyInputs},
+		{`<div><p>Short</p></div>`, ReasonTooShort},
+		{`<div><p>` + long + `<a href="/a">` + long + `</a></p></div>`, ReasonTooManyLinks},
+		{`<div><p>` + long + `</p><embed src="a.swf"><embed src="b.swf"></div>`, ReasonTooManyEmbeds},
+		{`<div><p>` + long + `</p><img src="a.png"></div>`, ReasonPassed},
+	} {
+		for _, enabled := range []bool{true, false} {
+			opt := NewOption()
+			opt.CleanRules = CleanRuleSet{tc.code: enabled}
+			doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+			exp := &Explanation{}
+			cleanConditionally(doc, &candidates{Map: map[string]candidate{}}, "div", opt, exp)
+			if !assert.Equal(t, 1, len(exp.Cleaning), tc.code) {
+				continue
+			}
+			d := exp.Cleaning[0]
+			if enabled || tc.code == ReasonPassed {
+				assert.Equal(t, tc.code, d.Code, tc.code)
+				assert.Equal(t, tc.code == ReasonManyCommas || tc.code == ReasonPassed, d.Kept, tc.code)
+				assert.Equal(t, d.Kept, doc.Find("div").Length() == 1, tc.code)
+			} else {
+				assert.NotEqual(t, tc.code, d.Code, tc.code)
+			}
+		}
+	}
+}
+
+func TestCleanStatsCounts(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(
+		`<div><p>a</p><p>b</p><ul><li>1</li><li>2</li></ul><img src="a.png"><a href="/">c</a></div>`))
+	c := newCleanStats("div", 0, newSubtreeStats(doc.Find("div").Get(0)))
+	assert.Equal(t, map[string]int{"p": 2, "img": 1, "li": -98, "a": 1, "embed": 0, "input": 0}, c.counts)
+	assert.Equal(t, len("ab12c"), c.textLength)
+}
+
+func TestCleanRuleSet(t *testing.T) {
+	var s CleanRuleSet
+	for _, code := range DefaultCleanRules() {
+		assert.True(t, s.Enabled(code), code)
+	}
+	rules := DefaultCleanRules()
+	rules[0] = ReasonTooShort
+	assert.Equal(t, ReasonNegativeScore, DefaultCleanRules()[0])
+	s = CleanRuleSet{ReasonTooShort: false, ReasonTooManyLinks: true}
+	assert.False(t, s.Enabled(ReasonTooShort))
+	assert.True(t, s.Enabled(ReasonTooManyLinks))
+	assert.True(t, s.Enabled(ReasonTooManyImages))
+
+	opt := NewOption()
+	opt.CleanRules = CleanRuleSet{"TOO_FEW_WORDS": false}
+	err := opt.Validate()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "CleanRules")
+	}
+}
@@ -0,0 +1,111 @@
+package readability
+
+import (
+	"sync"
+)
+
+// StageStatus is the status of a stage of an extraction.
+type StageStatus string
+
+// Statuses of Completeness.
+const (
+	// StatusCompleted means the stage finished in time.
+	StatusCompleted StageStatus = "completed"
+
+	// StatusSkipped means the stage was disabled by Option or not needed.
+	StatusSkipped StageStatus = "skipped"
+
+	// StatusTimedOut means the stage timed out, so its fields may be partial or empty.
+	StatusTimedOut StageStatus = "timed-out"
+)
+
+// Completeness tells which stages of an extraction completed, so that callers can
+// schedule a re-extraction with relaxed timeouts for the missing parts.
+type Completeness struct {
+	// Metadata is the status of reading opengraph tags and structured data.
+	Metadata StageStatus `json:"metadata"`
+
+	// Description is the status of extracting the description by readability rules.
+	// It is skipped if the description comes from metadata.
+	Description StageStatus `json:"description"`
+
+	// Images is the status of probing images. It is skipped if images come from metadata.
+	Images StageStatus `json:"images"`
+}
+
+// Complete returns true if no stage timed out.
+func (c Completeness) Complete() bool {
+	return len(c.TimedOut()) == 0
+}
+
+// TimedOut returns the stages which timed out.
+func (c Completeness) TimedOut() []Stage {
+	stages := []Stage{}
+	for _, s := range []struct {
+		stage  Stage
+		status StageStatus
+	}{
+		{StageMetadata, c.Metadata},
+		{StageDescription, c.Description},
+		{StageImages, c.Images},
+	} {
+		if s.status == StatusTimedOut {
+			stages = append(stages, s.stage)
+		}
+	}
+	return stages
+}
+
+// stageTracker records the stages run and timed out during an extraction.
+type stageTracker struct {
+	mu       sync.Mutex
+	run      map[Stage]bool
+	timedOut map[Stage]bool
+}
+
+func newStageTracker() *stageTracker {
+	return &stageTracker{run: map[Stage]bool{}, timedOut: map[Stage]bool{}}
+}
+
+func (t *stageTracker) markRun(stage Stage) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run[stage] = true
+}
+
+func (t *stageTracker) markTimedOut(stage Stage) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timedOut[stage] = true
+}
+
+func (t *stageTracker) status(stage Stage) StageStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.timedOut[stage]:
+		return StatusTimedOut
+	case t.run[stage]:
+		return StatusCompleted
+	default:
+		return StatusSkipped
+	}
+}
+
+func (t *stageTracker) completeness(opt *Option) *Completeness {
+	c := &Completeness{
+		Metadata:    t.status(StageMetadata),
+		Description: t.status(StageDescription),
+		Images:      t.status(StageImages),
+	}
+	if !opt.LookupOpenGraphTags && !opt.LookupStructuredData {
+		c.Metadata = StatusSkipped
+	}
+	return c
+}
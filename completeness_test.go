@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteness(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write(pngBytes(800, 600))
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.RetryLength = 0
+	opt.ImageProbingTimeout = 50
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	c, err := ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, &Completeness{Metadata: StatusCompleted, Description: StatusCompleted, Images: StatusCompleted}, c.Completeness)
+	assert.True(t, c.Completeness.Complete())
+	assert.Nil(t, opt.stages)
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(strings.Replace(articleHTML, `<div class="other">`, `<img src="/slow.png"><div class="other">`, 1)))
+	c, err = ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusTimedOut, c.Completeness.Images)
+	assert.False(t, c.Completeness.Complete())
+	assert.Equal(t, []Stage{StageImages}, c.Completeness.TimedOut())
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<html><head><meta property="og:description" content="OG"><meta property="og:image" content="/a.png"></head></html>`))
+	opt.LookupStructuredData = false
+	c, err = ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, &Completeness{Metadata: StatusCompleted, Description: StatusSkipped, Images: StatusSkipped}, c.Completeness)
+
+	opt.LookupOpenGraphTags = false
+	c, err = ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusSkipped, c.Completeness.Metadata)
+}
@@ -0,0 +1,128 @@
+package readability
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// readerUserAgent is the User-Agent of requests with the reader profile.
+const readerUserAgent = "Mozilla/5.0 (compatible; goreadability/1.0; +https://github.com/philipjkim/goreadability)"
+
+// consentPattern matches id and class attributes of cookie consent banners and walls
+// of common consent management platforms.
+var consentPattern = regexp.MustCompile(`(?i)cookie|consent|gdpr|onetrust|didomi|qc-cmp|sp_message|truste|cookiebot|usercentrics|\bcmp\b`)
+
+// consentFramePattern matches src attributes of consent management iframes.
+var consentFramePattern = regexp.MustCompile(`(?i)consent|privacy-mgmt|cmp\.`)
+
+// textlessElements are elements whose text is not displayed.
+var textlessElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+}
+
+// consentWall returns true if doc is dominated by a cookie consent banner:
+// the text of consent elements is longer than the rest of the page,
+// or the rest of the page is shorter than minLength.
+func consentWall(doc *goquery.Document, minLength int) bool {
+	found := doc.Find("iframe").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return consentFramePattern.MatchString(s.AttrOr("src", ""))
+	}).Length() > 0
+	consentLen, restLen := 0, 0
+
+	var walk func(n *html.Node, consent bool)
+	walk = func(n *html.Node, consent bool) {
+		switch n.Type {
+		case html.TextNode:
+			l := len(strings.TrimSpace(n.Data))
+			if consent {
+				consentLen += l
+			} else {
+				restLen += l
+			}
+		case html.ElementNode, html.DocumentNode:
+			if textlessElements[n.Data] {
+				return
+			}
+			if !consent && n.Type == html.ElementNode && isConsentElement(n) {
+				consent, found = true, true
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c, consent)
+			}
+		}
+	}
+	for _, n := range doc.Find("body").Nodes {
+		walk(n, false)
+	}
+	return found && (consentLen >= restLen || restLen < minLength)
+}
+
+func isConsentElement(n *html.Node) bool {
+	for _, a := range n.Attr {
+		if (a.Key == "id" || a.Key == "class") && consentPattern.MatchString(a.Val) {
+			return true
+		}
+	}
+	return false
+}
+
+// readerURLs returns the URLs requested with the reader profile for reqURL:
+// reqURL itself and its AMP variants (?amp and ?outputType=amp).
+func readerURLs(reqURL string) []string {
+	urls := []string{reqURL}
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return urls
+	}
+	amp := *u
+	if amp.RawQuery == "" {
+		amp.RawQuery = "amp"
+	} else {
+		amp.RawQuery += "&amp"
+	}
+	outputType := *u
+	q := outputType.Query()
+	q.Set("outputType", "amp")
+	outputType.RawQuery = q.Encode()
+	return append(urls, amp.String(), outputType.String())
+}
+
+// extractAsReader returns contents of reqURL extracted from the first of readerURLs
+// which is not dominated by a cookie consent banner when requested with the reader profile:
+// a reader User-Agent and no cookies. It returns nil if all of them fail.
+func (e *Extractor) extractAsReader(ctx context.Context, reqURL string) *Content {
+	for _, u := range readerURLs(reqURL) {
+		page, err := e.fetchWith(ctx, u, true)
+		if err != nil {
+			logger.Warnf("extractAsReader failed for %v: %v", u, err)
+			continue
+		}
+		if consentWall(page.doc, e.Option.RetryLength) {
+			continue
+		}
+		c, err := extractFromDocument(page.doc, reqURL, e.Option)
+		if err != nil {
+			logger.Warnf("extractAsReader failed for %v: %v", u, err)
+			continue
+		}
+		c.setFetched(page, e.Option)
+		c.ReaderURL = u
+		return c
+	}
+	return nil
+}
+
+// readerRequest sets the reader profile to req and returns a copy of client without cookies.
+func readerRequest(req *http.Request, client *http.Client) *http.Client {
+	req.Header.Set("User-Agent", readerUserAgent)
+	req.Header.Del("Cookie")
+	c := *client
+	c.Jar = nil
+	return &c
+}
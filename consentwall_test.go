@@ -0,0 +1,71 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsentWall(t *testing.T) {
+	banner := `<div id="onetrust-banner"><p>We value your privacy. We and our partners use cookies to store and access information on your device.</p><button>Accept all</button></div>`
+	article := `<article><p>` + strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 10) + `</p></article>`
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{banner, true},
+		{banner + article, false},
+		{`<iframe src="https://cmp.example.com/index.html"></iframe><p>Loading...</p>`, true},
+		{`<p>Short page</p>`, false},
+		{article, false},
+	}
+	for _, tt := range tests {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><body>` + tt.body + `</body></html>`))
+		assert.Equal(t, tt.want, consentWall(doc, 250), tt.body)
+	}
+}
+
+func TestReaderURLs(t *testing.T) {
+	assert.Equal(t, []string{
+		"http://example.com/a",
+		"http://example.com/a?amp",
+		"http://example.com/a?outputType=amp",
+	}, readerURLs("http://example.com/a"))
+	assert.Equal(t, []string{
+		"http://example.com/a?b=1",
+		"http://example.com/a?b=1&amp",
+		"http://example.com/a?b=1&outputType=amp",
+	}, readerURLs("http://example.com/a?b=1"))
+}
+
+func TestExtractorExtractReaderRetry(t *testing.T) {
+	wall := `<html><head><title>Wall</title></head><body><div class="consent-wall"><p>Please accept cookies to continue reading.</p></div></body></html>`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == readerUserAgent && r.URL.Query().Get("outputType") == "amp" {
+			fmt.Fprint(w, articleHTML)
+			return
+		}
+		fmt.Fprint(w, wall)
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	e := NewExtractor(opt)
+	c, err := e.Extract(context.Background(), ts.URL+"/a")
+	assert.Nil(t, err)
+	assert.Equal(t, "Wall", c.Title)
+	assert.Equal(t, "", c.ReaderURL)
+
+	opt.ReaderRetry = true
+	c, err = e.Extract(context.Background(), ts.URL+"/a")
+	assert.Nil(t, err)
+	assert.Equal(t, "Explain", c.Title)
+	assert.Equal(t, ts.URL+"/a?outputType=amp", c.ReaderURL)
+}
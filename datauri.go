@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/philipjkim/fastimage"
+)
+
+// isDataURI returns true if src is a data URI such as "data:image/png;base64,iVBOR...".
+func isDataURI(src string) bool {
+	return len(src) >= 5 && strings.EqualFold(src[:5], "data:")
+}
+
+// dataURIReader returns a reader decoding the payload of the data URI src.
+func dataURIReader(src string) (io.Reader, error) {
+	comma := strings.IndexByte(src, ',')
+	if !isDataURI(src) || comma < 0 {
+		return nil, fmt.Errorf("invalid data URI: %.32v", src)
+	}
+	header, payload := src[5:comma], src[comma+1:]
+
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		return base64.NewDecoder(base64.StdEncoding, strings.NewReader(strings.TrimSpace(payload))), nil
+	}
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(decoded), nil
+}
+
+// dataURIImageSize detects the size of the image inlined in the data URI src without any request.
+// Only the leading bytes of the payload needed for detection are decoded.
+func dataURIImageSize(src string) (*fastimage.ImageSize, error) {
+	r, err := dataURIReader(src)
+	if err != nil {
+		return nil, err
+	}
+	size, err := detectImageSize(r)
+	if err == errUnknownImageSize {
+		return nil, fmt.Errorf("unknown image type: %.32v", src)
+	}
+	return size, err
+}
@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataURIImageSize(t *testing.T) {
+	src := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBytes(640, 480))
+	size, err := dataURIImageSize(src)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(640), size.Width)
+	assert.Equal(t, uint32(480), size.Height)
+
+	_, err = dataURIImageSize("data:image/svg+xml,%3Csvg%3E%3C/svg%3E")
+	assert.NotNil(t, err)
+	_, err = dataURIImageSize("data:image/png;base64")
+	assert.NotNil(t, err)
+}
+
+func TestImagesDecodeDataURI(t *testing.T) {
+	large := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBytes(640, 480))
+	placeholder := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBytes(1, 1))
+	html := `<body><img src="` + placeholder + `"><img src="` + large + `"></body>`
+
+	opt := NewOption()
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.Empty(t, images(doc, "http://example.com", opt, ""))
+
+	opt.DecodeDataURIImages = true
+	imgs := images(doc, "http://example.com", opt, "")
+	assert.Equal(t, 1, len(imgs))
+	assert.Equal(t, large, imgs[0].URL)
+	assert.Equal(t, uint32(640), imgs[0].Size.Width)
+}
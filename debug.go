@@ -0,0 +1,201 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// DebugTrace is a structured trace of the extraction by readability rules, returned by ExtractWithDebug.
+// It has no passes if the description comes from opengraph tags or structured data.
+type DebugTrace struct {
+	// Passes contains a pass per attempt of readability rules. A pass is retried with
+	// relaxed rules if the description is shorter than Option.RetryLength.
+	Passes []DebugPass `json:"passes"`
+
+	mu sync.Mutex
+}
+
+// DebugPass is an attempt of readability rules.
+type DebugPass struct {
+	RemoveUnlikelyCandidates bool `json:"removeUnlikelyCandidates"`
+	WeightClasses            bool `json:"weightClasses"`
+	CleanConditionally       bool `json:"cleanConditionally"`
+
+	// Candidates contains the description candidates ordered by score. The first one is the best.
+	Candidates []DebugCandidate `json:"candidates"`
+
+	// Removals contains the nodes removed by the rules, in the order of removal.
+	Removals []DebugRemoval `json:"removals"`
+}
+
+// DebugCandidate is a description candidate.
+type DebugCandidate struct {
+	// Path is the selector path of the node like "html > body > div#main.content".
+	Path string `json:"path"`
+
+	// RawScore is the score before scaling by link density.
+	RawScore float64 `json:"rawScore"`
+
+	// Score is the final score.
+	Score float64 `json:"score"`
+
+	ClassWeight float64 `json:"classWeight"`
+	LinkDensity float64 `json:"linkDensity"`
+}
+
+// RemovalRule is a rule removing nodes during extraction.
+type RemovalRule string
+
+// Removal rules of DebugRemoval.
+const (
+	RuleScriptStyle         RemovalRule = "SCRIPT_STYLE"
+	RuleResponsiveDuplicate RemovalRule = "RESPONSIVE_DUPLICATE"
+	RuleUnlikelyCandidate   RemovalRule = "UNLIKELY_CANDIDATE"
+	RuleBadHeader           RemovalRule = "BAD_HEADER"
+	RuleEmbeddedContent     RemovalRule = "EMBEDDED_CONTENT"
+	RuleEmptyParagraph      RemovalRule = "EMPTY_PARAGRAPH"
+	RuleCleanConditionally  RemovalRule = "CLEAN_CONDITIONALLY"
+)
+
+// DebugRemoval is a node removed during extraction.
+type DebugRemoval struct {
+	Rule RemovalRule `json:"rule"`
+	Path string      `json:"path"`
+
+	// Code is the reason of the removal by RuleCleanConditionally.
+	Code ReasonCode `json:"code,omitempty"`
+}
+
+// ExtractWithDebug acts same as Extract, except that a trace of the extraction
+// by readability rules is also returned.
+func ExtractWithDebug(reqURL string, opt *Option) (*Content, *DebugTrace, error) {
+	opt, trace := debugOption(opt)
+	c, err := Extract(reqURL, opt)
+	return c, trace, err
+}
+
+// ExtractFromDocumentWithDebug acts same as ExtractFromDocument, except that a trace of
+// the extraction by readability rules is also returned.
+func ExtractFromDocumentWithDebug(doc *goquery.Document, reqURL string, opt *Option) (*Content, *DebugTrace, error) {
+	opt, trace := debugOption(opt)
+	c, err := ExtractFromDocument(doc, reqURL, opt)
+	return c, trace, err
+}
+
+func debugOption(opt *Option) (*Option, *DebugTrace) {
+	trace := &DebugTrace{Passes: []DebugPass{}}
+	opt = copyOption(opt)
+	opt.trace = trace
+	return opt, trace
+}
+
+func (t *DebugTrace) beginPass(opt *Option) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Passes = append(t.Passes, DebugPass{
+		RemoveUnlikelyCandidates: opt.RemoveUnlikelyCandidates,
+		WeightClasses:            opt.WeightClasses,
+		CleanConditionally:       opt.CleanConditionally,
+		Candidates:               []DebugCandidate{},
+		Removals:                 []DebugRemoval{},
+	})
+}
+
+func (t *DebugTrace) setCandidates(c *candidates, opt *Option) {
+	if t == nil || c == nil {
+		return
+	}
+	list := make([]DebugCandidate, len(c.List))
+	for i, cand := range c.List {
+		list[i] = DebugCandidate{
+			Path:        selectorPath(cand.Node.Selection),
+			RawScore:    cand.RawScore,
+			Score:       cand.Score,
+			ClassWeight: classWeight(cand.Node.Selection, opt),
+			LinkDensity: linkDensity(cand.Node.Selection),
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.Passes) > 0 {
+		t.Passes[len(t.Passes)-1].Candidates = list
+	}
+}
+
+// addRemoval records n which is about to be removed by rule.
+func (t *DebugTrace) addRemoval(rule RemovalRule, code ReasonCode, n *html.Node) {
+	if t == nil {
+		return
+	}
+	r := DebugRemoval{Rule: rule, Path: nodePath(n), Code: code}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.Passes) > 0 {
+		p := &t.Passes[len(t.Passes)-1]
+		p.Removals = append(p.Removals, r)
+	}
+}
+
+// selectorPath returns the path of the first node of s from the root like
+// "html > body > div#main.content > p:nth-of-type(2)".
+// :nth-of-type is added to elements without id which have siblings of the same tag.
+func selectorPath(s *goquery.Selection) string {
+	if s.Length() == 0 {
+		return ""
+	}
+	return nodePath(s.Get(0))
+}
+
+// nodePath returns the path of n from the root in the same form as selectorPath.
+func nodePath(n *html.Node) string {
+	var parts []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		parts = append(parts, selectorPathPart(n))
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
+
+func selectorPathPart(n *html.Node) string {
+	part := n.Data
+	var id, class string
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "id":
+			id = strings.TrimSpace(a.Val)
+		case "class":
+			class = strings.Join(strings.Fields(a.Val), ".")
+		}
+	}
+	if id != "" {
+		part += "#" + id
+	}
+	if class != "" {
+		part += "." + class
+	}
+	if id != "" || n.Parent == nil {
+		return part
+	}
+	index, count := 0, 0
+	for c := n.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == n.Data {
+			count++
+			if c == n {
+				index = count
+			}
+		}
+	}
+	if count > 1 {
+		part += fmt.Sprintf(":nth-of-type(%d)", index)
+	}
+	return part
+}
@@ -0,0 +1,41 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFromDocumentWithDebug(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(strings.Replace(articleHTML,
+		`<div class="other">`, `<script>var x;</script><nav><a href="/">Home</a></nav><div class="other">`, 1)))
+	opt := NewOption()
+	opt.RetryLength = 0
+	opt.ImageRequestTimeout = 10
+	c, trace, err := ExtractFromDocumentWithDebug(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Description, "Lorem ipsum")
+	assert.Nil(t, opt.trace)
+
+	assert.Equal(t, 1, len(trace.Passes))
+	p := trace.Passes[0]
+	assert.True(t, p.RemoveUnlikelyCandidates)
+	assert.NotEmpty(t, p.Candidates)
+	best := p.Candidates[0]
+	assert.Equal(t, "html > body > div#content.article", best.Path)
+	assert.Equal(t, 50.0, best.ClassWeight)
+	assert.True(t, best.Score <= best.RawScore)
+
+	assert.Contains(t, p.Removals, DebugRemoval{Rule: RuleScriptStyle, Path: "html > body > script"})
+	assert.Contains(t, p.Removals, DebugRemoval{Rule: RuleUnlikelyCandidate, Path: "html > body > nav"})
+	assert.Contains(t, p.Removals, DebugRemoval{Rule: RuleCleanConditionally, Path: "div#content.article > div.widget", Code: ReasonNegativeScore})
+}
+
+func TestSelectorPath(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><div id="a"><p>1</p><p class="x  y">2</p><span>3</span></div></body>`))
+	assert.Equal(t, "html > body > div#a > p.x.y:nth-of-type(2)", selectorPath(doc.Find("p.x")))
+	assert.Equal(t, "html > body > div#a > span", selectorPath(doc.Find("span")))
+	assert.Equal(t, "", selectorPath(doc.Find("table")))
+}
@@ -0,0 +1,216 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// densityLineWidth is the width of lines text blocks are wrapped in to compute their text density.
+const densityLineWidth = 80
+
+// confidentWordCount is the number of words of an article needed for full confidence.
+const confidentWordCount = 250
+
+// densityBlockTags are elements whose boundaries separate text blocks.
+var densityBlockTags = map[string]bool{
+	"ul": true, "ol": true, "dl": true, "dt": true, "dd": true, "table": true, "td": true, "th": true,
+	"header": true, "footer": true, "nav": true, "aside": true, "main": true, "form": true,
+	"figure": true, "figcaption": true, "address": true, "hr": true,
+}
+
+// densitySkippedTags are elements whose text is never content.
+var densitySkippedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true, "svg": true,
+	"button": true, "select": true, "textarea": true, "head": true,
+}
+
+// sentenceEnding matches paragraphs ending with a sentence.
+var sentenceEnding = regexp.MustCompile(`[.!?。！？؟]["'”’)\]»]*$`)
+
+// densityBlock is a text block of EngineDensity.
+type densityBlock struct {
+	text      string
+	words     int
+	linkWords int
+}
+
+// textDensity returns the number of words per line of b wrapped at densityLineWidth,
+// not counting the last line unless it is the only one.
+func (b *densityBlock) textDensity() float64 {
+	lines, lineLength, lineWords := 0, -1, 0
+	for _, w := range strings.Fields(b.text) {
+		lineLength += len(w) + 1
+		if lineLength > densityLineWidth {
+			lines++
+			lineLength, lineWords = len(w), 0
+		}
+		lineWords++
+	}
+	if lines == 0 {
+		return float64(b.words)
+	}
+	return float64(b.words-lineWords) / float64(lines)
+}
+
+// linkDensity returns the ratio of words of b inside links.
+func (b *densityBlock) linkDensity() float64 {
+	if b.words == 0 {
+		return 0
+	}
+	return float64(b.linkWords) / float64(b.words)
+}
+
+// densityBlocks splits the text of n into blocks at the boundaries of block elements.
+func densityBlocks(n *html.Node) []*densityBlock {
+	var blocks []*densityBlock
+	var text, links strings.Builder
+	flush := func() {
+		if t := strings.Join(strings.Fields(text.String()), " "); t != "" {
+			blocks = append(blocks, &densityBlock{text: t, words: len(strings.Fields(t)),
+				linkWords: len(strings.Fields(links.String()))})
+		}
+		text.Reset()
+		links.Reset()
+	}
+
+	var walk func(n *html.Node, inLink bool)
+	walk = func(n *html.Node, inLink bool) {
+		switch n.Type {
+		case html.TextNode:
+			text.WriteString(n.Data)
+			if inLink {
+				links.WriteString(" " + n.Data + " ")
+			}
+			return
+		case html.ElementNode:
+			if densitySkippedTags[n.Data] {
+				return
+			}
+		case html.DocumentNode:
+		default:
+			return
+		}
+		block := n.Type == html.ElementNode && (paragraphBlocks[n.Data] || densityBlockTags[n.Data])
+		if block {
+			flush()
+		}
+		text.WriteByte(' ')
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inLink || n.Data == "a")
+		}
+		text.WriteByte(' ')
+		if block {
+			flush()
+		}
+	}
+	walk(n, false)
+	flush()
+	return blocks
+}
+
+// isContentBlock classifies cur by its text and link densities and those of its neighbors,
+// by the rules of the density classifier of boilerpipe. prev and next are nil at the ends.
+func isContentBlock(prev, cur, next *densityBlock) bool {
+	td := func(b *densityBlock) float64 {
+		if b == nil {
+			return 0
+		}
+		return b.textDensity()
+	}
+	ld := func(b *densityBlock) float64 {
+		if b == nil {
+			return 0
+		}
+		return b.linkDensity()
+	}
+
+	switch {
+	case ld(cur) > 0.333333:
+		return false
+	case ld(prev) > 0.555556:
+		return td(next) > 11
+	case td(cur) > 9:
+		return td(next) > 0
+	case td(next) > 10:
+		return true
+	}
+	return td(prev) > 4
+}
+
+// densityArticle extracts the article of doc by EngineDensity: the largest run of consecutive
+// content blocks. doc is not modified.
+func densityArticle(doc *goquery.Document, opt *Option) *articleResult {
+	root := doc.Get(0)
+	if body := doc.Find("body"); body.Length() > 0 {
+		root = body.Get(0)
+	}
+	blocks := densityBlocks(root)
+
+	bestStart, bestEnd, bestWords := 0, 0, 0
+	start, words := -1, 0
+	for i, b := range blocks {
+		var prev, next *densityBlock
+		if i > 0 {
+			prev = blocks[i-1]
+		}
+		if i < len(blocks)-1 {
+			next = blocks[i+1]
+		}
+		if !isContentBlock(prev, b, next) {
+			start, words = -1, 0
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		words += b.words
+		if words > bestWords {
+			bestStart, bestEnd, bestWords = start, i+1, words
+		}
+	}
+
+	result := &articleResult{prepared: doc, engine: EngineDensity}
+	if bestWords == 0 {
+		return result
+	}
+	out, _ := goquery.NewDocumentFromReader(strings.NewReader("<div></div>"))
+	div := out.Find("div").Get(0)
+	for _, b := range blocks[bestStart:bestEnd] {
+		p := &html.Node{Type: html.ElementNode, Data: "p"}
+		p.AppendChild(&html.Node{Type: html.TextNode, Data: b.text})
+		div.AppendChild(p)
+		result.paragraphs = append(result.paragraphs, b.text)
+	}
+	if opt.DescriptionAsPlainText {
+		result.description = plainText(out.Selection)
+	} else {
+		result.description = serializeArticle(out)
+	}
+	result.node = out.Get(0)
+	return result
+}
+
+// articleConfidence returns how likely a is the main content of the page, from 0 to 1:
+// the ratio of its text in paragraphs ending with a sentence, scaled down if it is shorter
+// than confidentWordCount words.
+func articleConfidence(a *articleResult) float64 {
+	total, sentences, words := 0, 0, 0
+	for _, p := range a.paragraphs {
+		total += len(p)
+		words += len(strings.Fields(p))
+		if sentenceEnding.MatchString(p) {
+			sentences += len(p)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	confidence := float64(sentences) / float64(total)
+	if words < confidentWordCount {
+		confidence *= float64(words) / confidentWordCount
+	}
+	return confidence
+}
@@ -0,0 +1,97 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+var minimalistPage = `<html><head><title>Notes</title></head><body>
+<div><a href="/">Home</a> <a href="/posts">Posts</a> <a href="/about">About</a></div>
+<div>
+<div>` + strings.Repeat("We moved the build to a single machine and the tests got faster than ever before. ", 4) + `</div>
+<div>` + strings.Repeat("Caching the dependencies saved most of the time, and the rest came from running tests in parallel. ", 4) + `</div>
+<div>` + strings.Repeat("Next month we will look at the deployment, which is still slow for no good reason. ", 4) + `</div>
+</div>
+<div><a href="/prev">Previous post</a> <a href="/next">Next post</a></div>
+<div>Copyright 2020</div>
+</body></html>`
+
+func TestDensityBlock(t *testing.T) {
+	b := &densityBlock{text: "three short words", words: 3}
+	assert.Equal(t, 3.0, b.textDensity())
+	assert.Equal(t, 0.0, b.linkDensity())
+
+	long := strings.Repeat("word ", 40)
+	b = &densityBlock{text: long, words: 40, linkWords: 10}
+	assert.Equal(t, 16.0, b.textDensity())
+	assert.Equal(t, 0.25, b.linkDensity())
+}
+
+func TestIsContentBlock(t *testing.T) {
+	text := &densityBlock{text: strings.Repeat("word ", 40), words: 40}
+	links := &densityBlock{text: "Home Posts About", words: 3, linkWords: 3}
+	short := &densityBlock{text: "Copyright 2020", words: 2}
+	assert.True(t, isContentBlock(nil, text, text))
+	// a dense last block needs a following block
+	assert.False(t, isContentBlock(text, text, nil))
+	assert.False(t, isContentBlock(text, links, text))
+	assert.False(t, isContentBlock(links, short, nil))
+	// a block after links needs a dense following block
+	assert.True(t, isContentBlock(links, text, text))
+	assert.False(t, isContentBlock(links, text, short))
+}
+
+func TestDensityArticle(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(minimalistPage))
+	opt := NewOption()
+	a := densityArticle(doc, opt)
+	assert.Equal(t, EngineDensity, a.engine)
+	if assert.Equal(t, 3, len(a.paragraphs)) {
+		assert.True(t, strings.HasPrefix(a.paragraphs[0], "We moved the build"))
+		assert.True(t, strings.HasPrefix(a.paragraphs[2], "Next month"))
+	}
+	assert.NotContains(t, a.description, "Home")
+	assert.NotContains(t, a.description, "Copyright")
+	// all paragraphs end with sentences, but the article is short
+	words := len(strings.Fields(strings.Join(a.paragraphs, " ")))
+	assert.InDelta(t, float64(words)/confidentWordCount, articleConfidence(a), 1e-9)
+
+	empty, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><a href="/">Home</a></body>`))
+	assert.Equal(t, "", densityArticle(empty, opt).description)
+	assert.Equal(t, 0.0, articleConfidence(densityArticle(empty, opt)))
+}
+
+func TestEngine(t *testing.T) {
+	extract := func(engine EngineName) *Content {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(minimalistPage))
+		opt := benchOption()
+		opt.Engine = engine
+		c, err := ExtractFromDocument(doc, "http://example.com/notes", opt)
+		assert.Nil(t, err)
+		return c
+	}
+
+	c := extract(EngineDensity)
+	assert.Equal(t, EngineDensity, c.Engine)
+	assert.Equal(t, DescriptionSourceReadability, c.DescriptionSource)
+	assert.True(t, strings.HasPrefix(c.Description, "We moved the build"))
+	assert.Equal(t, 3, len(c.Paragraphs))
+
+	assert.Equal(t, EngineReadability, extract("").Engine)
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(minimalistPage))
+	readability := extractArticle(doc, "", benchOption())
+	density := densityArticle(doc, benchOption())
+	want := EngineReadability
+	if articleConfidence(density) > articleConfidence(readability) {
+		want = EngineDensity
+	}
+	assert.Equal(t, want, extract(EngineAuto).Engine)
+
+	opt := NewOption()
+	opt.Engine = "ml"
+	assert.NotNil(t, opt.Validate())
+}
@@ -0,0 +1,84 @@
+package readability
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DescriptionSource is a source of Content.Description.
+type DescriptionSource string
+
+// Sources of descriptions.
+const (
+	// DescriptionSourceOpenGraph is the og:description meta property.
+	DescriptionSourceOpenGraph DescriptionSource = "og"
+
+	// DescriptionSourceMetadata is the description of JSON-LD or microdata article.
+	DescriptionSourceMetadata DescriptionSource = "metadata"
+
+	// DescriptionSourceArticleBody is the articleBody of JSON-LD or microdata article.
+	DescriptionSourceArticleBody DescriptionSource = "articleBody"
+
+	// DescriptionSourceReadability is the article extracted by readability rules.
+	DescriptionSourceReadability DescriptionSource = "readability"
+)
+
+// descriptionSources are all description sources in the order they are tried.
+var descriptionSources = []DescriptionSource{DescriptionSourceOpenGraph, DescriptionSourceMetadata,
+	DescriptionSourceArticleBody, DescriptionSourceReadability}
+
+func isKnownDescriptionSource(src DescriptionSource) bool {
+	for _, s := range descriptionSources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedDescriptionSources returns the description sources excluded for reqURL by
+// opt.ExcludedDescriptionSources: the sources of "*" and of the host of reqURL and its parent domains.
+func excludedDescriptionSources(reqURL string, opt *Option) map[DescriptionSource]bool {
+	if len(opt.ExcludedDescriptionSources) == 0 {
+		return nil
+	}
+	host := ""
+	if u, err := url.Parse(reqURL); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+	excluded := map[DescriptionSource]bool{}
+	for domain, srcs := range opt.ExcludedDescriptionSources {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "*" && host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		for _, src := range srcs {
+			excluded[src] = true
+		}
+	}
+	return excluded
+}
+
+// metadataDescription returns the first description of og and md from sources not excluded,
+// and whether a non-empty description is dropped since its source is excluded.
+func metadataDescription(og *OpenGraph, md *Metadata, excluded map[DescriptionSource]bool) (string, DescriptionSource, bool) {
+	dropped := false
+	for _, d := range []struct {
+		src  DescriptionSource
+		text string
+	}{
+		{DescriptionSourceOpenGraph, og.Description},
+		{DescriptionSourceMetadata, md.Description},
+		{DescriptionSourceArticleBody, md.Body},
+	} {
+		text := strings.TrimSpace(d.text)
+		switch {
+		case text == "":
+		case excluded[d.src]:
+			dropped = true
+		default:
+			return text, d.src, dropped
+		}
+	}
+	return "", "", dropped
+}
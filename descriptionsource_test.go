@@ -0,0 +1,66 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludedDescriptionSources(t *testing.T) {
+	opt := NewOption()
+	assert.Nil(t, excludedDescriptionSources("https://spam.example/a", opt))
+
+	opt.ExcludedDescriptionSources = map[string][]DescriptionSource{
+		"*":            {DescriptionSourceArticleBody},
+		"Spam.Example": {DescriptionSourceOpenGraph, DescriptionSourceMetadata},
+	}
+	assert.Equal(t, map[DescriptionSource]bool{
+		DescriptionSourceArticleBody: true, DescriptionSourceOpenGraph: true, DescriptionSourceMetadata: true,
+	}, excludedDescriptionSources("https://www.spam.example/a", opt))
+	assert.Equal(t, map[DescriptionSource]bool{DescriptionSourceArticleBody: true},
+		excludedDescriptionSources("https://notspam.example/a", opt))
+
+	opt.ExcludedDescriptionSources = map[string][]DescriptionSource{"spam.example": {"meta"}}
+	assert.NotNil(t, opt.Validate())
+}
+
+func TestExtractWithExcludedDescriptionSources(t *testing.T) {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><head><title>Title</title>` +
+		`<meta property="og:title" content="OG title"><meta property="og:description" content="Buy cheap pills">` +
+		`<script type="application/ld+json">{"@type": "NewsArticle", "description": "Cheap pills here"}</script>` +
+		`</head><body><div class="article"><p>Article ` + p + `</p><p>` + p + `</p></div></body></html>`
+
+	extract := func(excluded map[string][]DescriptionSource) *Content {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		opt := benchOption()
+		opt.LookupOpenGraphTags = true
+		opt.LookupStructuredData = true
+		opt.ExcludedDescriptionSources = excluded
+		c, err := ExtractFromDocument(doc, "https://www.spam.example/a", opt)
+		assert.Nil(t, err)
+		return c
+	}
+
+	c := extract(nil)
+	assert.Equal(t, "Buy cheap pills", c.Description)
+	assert.Equal(t, DescriptionSourceOpenGraph, c.DescriptionSource)
+
+	c = extract(map[string][]DescriptionSource{"spam.example": {DescriptionSourceOpenGraph}})
+	assert.Equal(t, "Cheap pills here", c.Description)
+	assert.Equal(t, DescriptionSourceMetadata, c.DescriptionSource)
+
+	c = extract(map[string][]DescriptionSource{"spam.example": {DescriptionSourceOpenGraph, DescriptionSourceMetadata}})
+	assert.True(t, strings.HasPrefix(c.Description, "Article lorem"), c.Description)
+	assert.Equal(t, DescriptionSourceReadability, c.DescriptionSource)
+
+	c = extract(map[string][]DescriptionSource{"other.example": {DescriptionSourceOpenGraph, DescriptionSourceMetadata}})
+	assert.Equal(t, DescriptionSourceOpenGraph, c.DescriptionSource)
+
+	c = extract(map[string][]DescriptionSource{"*": {DescriptionSourceOpenGraph, DescriptionSourceMetadata, DescriptionSourceReadability}})
+	assert.Equal(t, "", c.Description)
+	assert.Equal(t, DescriptionSource(""), c.DescriptionSource)
+	assert.Equal(t, "OG title", c.Title)
+}
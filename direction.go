@@ -0,0 +1,62 @@
+package readability
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// TextDirection is the direction of the text of a page.
+type TextDirection string
+
+// Text directions of Content.TextDirection.
+const (
+	TextDirectionLTR TextDirection = "ltr"
+	TextDirectionRTL TextDirection = "rtl"
+)
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko,
+	unicode.Samaritan, unicode.Mandaic, unicode.Adlam,
+}
+
+// declaredDirection returns the dir attribute ("ltr" or "rtl") of <html> or <body> of doc,
+// or "" if it is missing or "auto". doc must be read before the extraction removes nodes from it.
+func declaredDirection(doc *goquery.Document) TextDirection {
+	d := ""
+	doc.Find("html, body").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		d = strings.ToLower(strings.TrimSpace(s.AttrOr("dir", "")))
+		return d != string(TextDirectionLTR) && d != string(TextDirectionRTL)
+	})
+	switch TextDirection(d) {
+	case TextDirectionLTR, TextDirectionRTL:
+		return TextDirection(d)
+	}
+	return ""
+}
+
+// textDirection returns declared if it is not empty, otherwise the direction of the script
+// of most letters of texts: TextDirectionRTL if more letters are in rtlScripts than not.
+func textDirection(declared TextDirection, texts ...string) TextDirection {
+	if declared != "" {
+		return declared
+	}
+	rtl, ltr := 0, 0
+	for _, s := range texts {
+		for _, r := range s {
+			switch {
+			case !unicode.IsLetter(r):
+			case unicode.In(r, rtlScripts...):
+				rtl++
+			default:
+				ltr++
+			}
+		}
+	}
+	if rtl > ltr {
+		return TextDirectionRTL
+	}
+	return TextDirectionLTR
+}
@@ -0,0 +1,53 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclaredDirection(t *testing.T) {
+	for _, tc := range []struct {
+		html string
+		dir  TextDirection
+	}{
+		{`<html dir="rtl"><body></body></html>`, TextDirectionRTL},
+		{`<html><body dir="RTL"></body></html>`, TextDirectionRTL},
+		{`<html dir="ltr"><body></body></html>`, TextDirectionLTR},
+		{`<html dir="auto"><body></body></html>`, ""},
+		{`<html><body><div dir="rtl"></div></body></html>`, ""},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		assert.Equal(t, tc.dir, declaredDirection(doc), tc.html)
+	}
+}
+
+func TestTextDirection(t *testing.T) {
+	assert.Equal(t, TextDirectionRTL, textDirection("", "مرحبا بالعالم", "Hello"))
+	assert.Equal(t, TextDirectionRTL, textDirection("", "שָׁלוֹם 2024"))
+	assert.Equal(t, TextDirectionLTR, textDirection("", "Hello world", "مرحبا"))
+	assert.Equal(t, TextDirectionLTR, textDirection("", "日本語の文章"))
+	assert.Equal(t, TextDirectionLTR, textDirection(""))
+	assert.Equal(t, TextDirectionLTR, textDirection(TextDirectionLTR, "مرحبا بالعالم"))
+}
+
+func TestExtractTextDirection(t *testing.T) {
+	p := strings.Repeat("هذا نص عربي طويل بما يكفي ليكون وصف الصفحة، ", 10)
+	for _, tc := range []struct {
+		html string
+		dir  TextDirection
+	}{
+		{`<html><head><title>عنوان</title></head><body><div class="article"><p>` + p + `</p></div></body></html>`, TextDirectionRTL},
+		{`<html dir="rtl"><head><title>Title</title></head><body><div class="article"><p>` + strings.Repeat("English text. ", 30) + `</p></div></body></html>`, TextDirectionRTL},
+		{`<html><head><title>Title</title></head><body><div class="article"><p>` + strings.Repeat("English text. ", 30) + `</p></div></body></html>`, TextDirectionLTR},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		opt := benchOption()
+		opt.DescriptionAsPlainText = false
+		c, err := ExtractFromDocument(doc, "http://example.com", opt)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.dir, c.TextDirection)
+	}
+}
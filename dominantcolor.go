@@ -0,0 +1,121 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"time"
+
+	// decoders for image.Decode
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// dominantColorMaxBytes is the maximum size of an image downloaded for its dominant color.
+	dominantColorMaxBytes = 1024 * 1024
+
+	// dominantColorSamples is the approximate number of pixels sampled along each side of an image.
+	dominantColorSamples = 64
+
+	// dominantColorBits is the number of high bits of each channel used for grouping similar colors.
+	dominantColorBits = 4
+)
+
+// setDominantColor sets Image.DominantColor of the lead image of c,
+// which is c.PrimaryImage or the first of c.Images, and of the same image in c.Images.
+func setDominantColor(c *Content, opt *Option) {
+	lead := c.PrimaryImage
+	if lead == nil && len(c.Images) > 0 {
+		lead = &c.Images[0]
+	}
+	if lead == nil {
+		return
+	}
+	if opt.DryRun {
+		if !isDataURI(lead.URL) {
+			opt.plan.add(RequestDominantColor, lead.URL)
+		}
+		return
+	}
+
+	color, err := dominantColor(*lead, opt)
+	if err != nil {
+		logger.Warnf("setDominantColor failed: %v", err)
+		return
+	}
+	if c.PrimaryImage != nil {
+		c.PrimaryImage.DominantColor = color
+	}
+	for i := range c.Images {
+		if c.Images[i].URL == lead.URL {
+			c.Images[i].DominantColor = color
+		}
+	}
+}
+
+// dominantColor downloads img and returns its most common color in "#rrggbb" form.
+func dominantColor(img Image, opt *Option) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(opt.ImageRequestTimeout)*time.Millisecond)
+	defer cancel()
+
+	fetchOpt := copyOption(opt)
+	if fetchOpt.MaxImageBytes == 0 || fetchOpt.MaxImageBytes > dominantColorMaxBytes {
+		fetchOpt.MaxImageBytes = dominantColorMaxBytes
+	}
+	b, err := img.Fetch(ctx, fetchOpt)
+	if err != nil {
+		return "", err
+	}
+	m, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	r, g, bl := dominantRGB(m)
+	return fmt.Sprintf("#%02x%02x%02x", r, g, bl), nil
+}
+
+// dominantRGB groups sampled pixels of m by the high bits of their channels,
+// and returns the average color of the largest group. Transparent pixels are skipped.
+func dominantRGB(m image.Image) (r, g, b uint8) {
+	type group struct {
+		n       int
+		r, g, b uint64
+	}
+	groups := map[uint32]*group{}
+	var best *group
+
+	bounds := m.Bounds()
+	stepX := max(bounds.Dx()/dominantColorSamples, 1)
+	stepY := max(bounds.Dy()/dominantColorSamples, 1)
+	shift := 8 - dominantColorBits
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			cr, cg, cb, ca := m.At(x, y).RGBA()
+			if ca < 0x8000 {
+				continue
+			}
+			pr, pg, pb := uint32(cr>>8), uint32(cg>>8), uint32(cb>>8)
+			key := pr>>shift<<(2*dominantColorBits) | pg>>shift<<dominantColorBits | pb>>shift
+			gr := groups[key]
+			if gr == nil {
+				gr = &group{}
+				groups[key] = gr
+			}
+			gr.n++
+			gr.r += uint64(pr)
+			gr.g += uint64(pg)
+			gr.b += uint64(pb)
+			if best == nil || gr.n > best.n {
+				best = gr
+			}
+		}
+	}
+	if best == nil {
+		return 0, 0, 0
+	}
+	n := uint64(best.n)
+	return uint8(best.r / n), uint8(best.g / n), uint8(best.b / n)
+}
@@ -0,0 +1,53 @@
+package readability
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDominantRGB(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			c := color.RGBA{R: 0xcc, G: 0x20, B: 0x10, A: 0xff}
+			if x >= 150 {
+				c = color.RGBA{B: 0xff, A: 0xff}
+			}
+			m.Set(x, y, c)
+		}
+	}
+	r, g, b := dominantRGB(m)
+	assert.Equal(t, [3]uint8{0xcc, 0x20, 0x10}, [3]uint8{r, g, b})
+}
+
+func TestComputeDominantColor(t *testing.T) {
+	m := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			m.Set(x, y, color.RGBA{R: 0x33, G: 0x66, B: 0x99, A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, m)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	defer ts.Close()
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><meta property="og:image" content="/lead.png"></head></html>`))
+	opt := NewOption()
+	opt.ComputeDominantColor = true
+	c, err := ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "#336699", c.PrimaryImage.DominantColor)
+	assert.Equal(t, "#336699", c.Images[0].DominantColor)
+}
@@ -0,0 +1,73 @@
+package readability
+
+import (
+	"sync"
+)
+
+// RequestPurpose is why an extraction sends a request other than the page fetch.
+type RequestPurpose string
+
+// Purposes of requests.
+const (
+	// RequestImageProbe requests the first bytes of an image to detect its size.
+	RequestImageProbe RequestPurpose = "image-probe"
+
+	// RequestOEmbed looks up the thumbnail of an embedded video. See Option.VideoThumbnails.
+	RequestOEmbed RequestPurpose = "oembed"
+
+	// RequestDominantColor downloads the lead image. See Option.ComputeDominantColor.
+	RequestDominantColor RequestPurpose = "dominant-color"
+
+	// RequestAMP fetches the AMP version of the page. See Option.FollowAMP.
+	RequestAMP RequestPurpose = "amp"
+
+	// RequestReader fetches the page again with the reader profile. See Option.ReaderRetry.
+	RequestReader RequestPurpose = "reader"
+)
+
+// PlannedRequest is a request an extraction with Option.DryRun would have sent.
+type PlannedRequest struct {
+	Purpose RequestPurpose `json:"purpose"`
+	URL     string         `json:"url"`
+}
+
+// requestPlan records the requests planned during an extraction with Option.DryRun.
+// A nil plan records nothing.
+type requestPlan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+func (p *requestPlan) add(purpose RequestPurpose, u string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range p.requests {
+		if r.Purpose == purpose && r.URL == u {
+			return
+		}
+	}
+	p.requests = append(p.requests, PlannedRequest{Purpose: purpose, URL: u})
+}
+
+// list returns the planned requests in the order they were planned, or nil if none.
+func (p *requestPlan) list() []PlannedRequest {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.requests) == 0 {
+		return nil
+	}
+	return append([]PlannedRequest(nil), p.requests...)
+}
+
+// planRequest adds a request planned instead of sent to c, if it is not planned yet.
+func (c *Content) planRequest(purpose RequestPurpose, u string) {
+	p := &requestPlan{requests: c.PlannedRequests}
+	p.add(purpose, u)
+	c.PlannedRequests = p.requests
+}
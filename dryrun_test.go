@@ -0,0 +1,93 @@
+package readability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestPlan(t *testing.T) {
+	var p *requestPlan
+	p.add(RequestAMP, "http://example.com/amp")
+	assert.Nil(t, p.list())
+
+	p = &requestPlan{}
+	assert.Nil(t, p.list())
+	p.add(RequestImageProbe, "http://example.com/a.jpg")
+	p.add(RequestImageProbe, "http://example.com/b.jpg")
+	p.add(RequestImageProbe, "http://example.com/a.jpg")
+	p.add(RequestDominantColor, "http://example.com/a.jpg")
+	assert.Equal(t, []PlannedRequest{
+		{RequestImageProbe, "http://example.com/a.jpg"},
+		{RequestImageProbe, "http://example.com/b.jpg"},
+		{RequestDominantColor, "http://example.com/a.jpg"},
+	}, p.list())
+}
+
+func TestExtractFromDocumentDryRun(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(pngBytes(800, 600))
+	}))
+	defer ts.Close()
+	defer func(endpoint string) { vimeoOEmbedEndpoint = endpoint }(vimeoOEmbedEndpoint)
+	vimeoOEmbedEndpoint = ts.URL + "/oembed"
+
+	html := `<html><head><title>Title</title></head><body><article>
+<p>A long enough paragraph of the article, which is written as sentences to be extracted.</p>
+<img src="/a.png"><img src="/b.png" width="640" height="480"><img src="/c.png">
+<iframe src="https://player.vimeo.com/video/76979871"></iframe>
+</article></body></html>`
+	opt := NewOption()
+	opt.DryRun = true
+	opt.VideoThumbnails = true
+	opt.ComputeDominantColor = true
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err := ExtractFromDocument(doc, ts.URL+"/post", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+
+	// images of known sizes are still used
+	if assert.Len(t, c.Images, 1) {
+		assert.Equal(t, ts.URL+"/b.png", c.Images[0].URL)
+	}
+	assert.Equal(t, []PlannedRequest{
+		{RequestOEmbed, ts.URL + "/oembed?url=https%3A%2F%2Fvimeo.com%2F76979871"},
+		{RequestImageProbe, ts.URL + "/a.png"},
+		{RequestImageProbe, ts.URL + "/c.png"},
+		{RequestDominantColor, ts.URL + "/b.png"},
+	}, c.PlannedRequests)
+
+	opt.DryRun = false
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err = ExtractFromDocument(doc, ts.URL+"/post", opt)
+	assert.Nil(t, err)
+	assert.Nil(t, c.PlannedRequests)
+	assert.NotEqual(t, int32(0), atomic.LoadInt32(&requests))
+}
+
+func TestExtractorDryRunAMP(t *testing.T) {
+	var ampRequests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/amp" {
+			atomic.AddInt32(&ampRequests, 1)
+		}
+		w.Write([]byte(`<html><head><title>Short</title><link rel="amphtml" href="/amp"></head><body><p>Short.</p></body></html>`))
+	}))
+	defer ts.Close()
+
+	opt := benchOption()
+	opt.FollowAMP = true
+	opt.DryRun = true
+	c, err := NewExtractor(opt).Extract(context.Background(), ts.URL+"/post")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&ampRequests))
+	assert.Equal(t, []PlannedRequest{{RequestAMP, ts.URL + "/amp"}}, c.PlannedRequests)
+}
@@ -0,0 +1,165 @@
+package readability
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// EngineName is the name of an algorithm extracting the article of a page:
+// a built-in one, or an Engine registered by RegisterEngine.
+type EngineName string
+
+// Engines of Option.Engine.
+const (
+	// EngineReadability scores nodes by the arc90 readability rules and takes the best one
+	// with its qualified siblings.
+	EngineReadability EngineName = "readability"
+
+	// EngineDensity splits the page into text blocks, classifies them as content or boilerplate
+	// by their text and link densities (like boilerpipe), and takes the largest run of
+	// content blocks. It works better than EngineReadability on minimalist layouts and pages
+	// whose markup is generated by scripts, where classes and ids say little.
+	EngineDensity EngineName = "density"
+
+	// EngineAuto runs both engines and takes the article of higher confidence,
+	// preferring EngineReadability on ties. See Content.Engine.
+	EngineAuto EngineName = "auto"
+
+	// EngineMozilla is the algorithm of the current Mozilla Readability.js (grabArticle):
+	// paragraphs are scored into up to 5 levels of ancestors, the best candidate moves up to
+	// an ancestor shared by other top candidates or scoring higher, and its siblings are merged.
+	// Passes are retried with relaxed rules (see Option.RetryLength) until the article has
+	// mozillaCharThreshold characters, and the longest article of the passes is taken.
+	// It always works on copies of the document, regardless of Option.ModifyDocument.
+	EngineMozilla EngineName = "mozilla"
+)
+
+// Engine is a custom engine extracting the article of a page, such as an ML-based
+// or site-specific extractor, registered by RegisterEngine and selected by Option.Engine.
+// The fetching, the metadata and the images of the page are handled by this package
+// as for the built-in engines.
+type Engine interface {
+	// Extract returns the article of doc, the page at reqURL: its Description (plain text if
+	// opt.DescriptionAsPlainText is set), and optionally its Paragraphs, Outline, Author, Explanation
+	// and Images. Images, if not empty, are used instead of the images of the page.
+	// The other fields are ignored. doc is a copy unless opt.ModifyDocument is set,
+	// and opt is a copy of the options of the extraction, so changes on it have no effect.
+	// If an error is returned, the description is empty.
+	Extract(doc *goquery.Document, reqURL string, opt *Option) (*Content, error)
+}
+
+// EngineFunc is an adapter to allow the use of ordinary functions as an Engine.
+type EngineFunc func(doc *goquery.Document, reqURL string, opt *Option) (*Content, error)
+
+// Extract calls f(doc, reqURL, opt).
+func (f EngineFunc) Extract(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
+	return f(doc, reqURL, opt)
+}
+
+// builtinEngines are the engines of this package, which can't be replaced.
+var builtinEngines = []EngineName{EngineReadability, EngineDensity, EngineAuto, EngineMozilla}
+
+var engines = struct {
+	sync.RWMutex
+	m map[EngineName]Engine
+}{m: map[EngineName]Engine{}}
+
+// RegisterEngine makes e available as Option.Engine name. Registering a name again replaces
+// its engine. An error is returned if name is empty or a built-in engine, or e is nil.
+func RegisterEngine(name EngineName, e Engine) error {
+	if name == "" {
+		return errors.New("engine name is empty")
+	}
+	if isBuiltinEngine(name) {
+		return fmt.Errorf("engine %q is built in", name)
+	}
+	if e == nil {
+		return fmt.Errorf("engine %q is nil", name)
+	}
+	engines.Lock()
+	defer engines.Unlock()
+	engines.m[name] = e
+	return nil
+}
+
+// Engines returns the names of the built-in engines followed by the registered ones in sorted order.
+func Engines() []EngineName {
+	engines.RLock()
+	defer engines.RUnlock()
+	names := append([]EngineName{}, builtinEngines...)
+	var registered []EngineName
+	for name := range engines.m {
+		registered = append(registered, name)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i] < registered[j] })
+	return append(names, registered...)
+}
+
+func isBuiltinEngine(name EngineName) bool {
+	for _, e := range builtinEngines {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// registeredEngine returns the engine registered as name, or nil.
+func registeredEngine(name EngineName) Engine {
+	engines.RLock()
+	defer engines.RUnlock()
+	return engines.m[name]
+}
+
+// customArticle extracts the article of doc by e, the engine registered as opt.Engine.
+func customArticle(e Engine, doc *goquery.Document, reqURL string, opt *Option) *articleResult {
+	work := doc
+	if !opt.ModifyDocument {
+		work = goquery.CloneDocument(doc)
+	}
+	result := &articleResult{prepared: doc, engine: opt.Engine}
+	// the state of the extraction (trace, stages, ...) stays in this package, so that
+	// an engine extracting with opt again, say by ExtractFromDocument, starts afresh
+	public := copyOption(opt)
+	public.trace, public.stages, public.plan, public.imageHosts = nil, nil, nil, nil
+	c, err := e.Extract(work, reqURL, public)
+	if err != nil {
+		logger.Warnf("description: engine %q: %v", opt.Engine, err)
+		return result
+	}
+	if c == nil {
+		return result
+	}
+	result.description, result.paragraphs, result.outline = c.Description, c.Paragraphs, c.Outline
+	result.author, result.images, result.explanation = c.Author, c.Images, c.Explanation
+	return result
+}
+
+// extractArticleWith extracts the article of doc by opt.Engine. Unknown engines fall back to EngineReadability.
+func extractArticleWith(doc *goquery.Document, reqURL string, opt *Option) *articleResult {
+	switch opt.Engine {
+	case "", EngineReadability:
+		return extractArticle(doc, reqURL, opt)
+	case EngineDensity:
+		return densityArticle(doc, opt)
+	case EngineMozilla:
+		return mozillaArticle(doc, reqURL, opt)
+	case EngineAuto:
+		// the density engine runs first since readability rules may modify doc
+		density := densityArticle(doc, opt)
+		article := extractArticle(doc, reqURL, opt)
+		if articleConfidence(density) > articleConfidence(article) {
+			return density
+		}
+		return article
+	default:
+		if e := registeredEngine(opt.Engine); e != nil {
+			return customArticle(e, doc, reqURL, opt)
+		}
+		return extractArticle(doc, reqURL, opt)
+	}
+}
@@ -0,0 +1,79 @@
+package readability
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func unregisterEngine(name EngineName) {
+	engines.Lock()
+	defer engines.Unlock()
+	delete(engines.m, name)
+}
+
+func TestRegisterEngine(t *testing.T) {
+	e := EngineFunc(func(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
+		return &Content{}, nil
+	})
+	assert.NotNil(t, RegisterEngine("", e))
+	assert.NotNil(t, RegisterEngine(EngineMozilla, e))
+	assert.NotNil(t, RegisterEngine("test-nil", nil))
+
+	opt := NewOption()
+	opt.Engine = "test-register"
+	err := opt.Validate()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Engine")
+	}
+
+	assert.Nil(t, RegisterEngine("test-register", e))
+	defer unregisterEngine("test-register")
+	assert.Nil(t, opt.Validate())
+	assert.Equal(t, []EngineName{EngineReadability, EngineDensity, EngineAuto, EngineMozilla, "test-register"}, Engines())
+}
+
+func TestCustomEngine(t *testing.T) {
+	var got *goquery.Document
+	var gotOpt *Option
+	assert.Nil(t, RegisterEngine("test-custom", EngineFunc(func(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
+		got, gotOpt = doc, opt
+		text := strings.TrimSpace(doc.Find("div.other").Text())
+		doc.Find("body").Remove()
+		return &Content{Title: "ignored", Description: text, Paragraphs: []string{text}, Author: "Engine Author"}, nil
+	})))
+	defer unregisterEngine("test-custom")
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	opt := benchOption()
+	opt.Engine = "test-custom"
+	c, err := ExtractFromDocument(doc, "http://example.com/explain", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, EngineName("test-custom"), c.Engine)
+	assert.Equal(t, DescriptionSourceReadability, c.DescriptionSource)
+	assert.Equal(t, "Short note for siblings.", c.Description)
+	assert.Equal(t, []string{"Short note for siblings."}, c.Paragraphs)
+	assert.Equal(t, "Engine Author", c.Author)
+	// the title is extracted by this package, and the engine works on a copy
+	assert.Equal(t, "Explain", c.Title)
+	assert.NotEqual(t, doc, got)
+	assert.Equal(t, 1, doc.Find("body").Length())
+	// the engine gets a copy of opt without the state of the extraction
+	if assert.NotNil(t, gotOpt) {
+		assert.False(t, gotOpt == opt)
+		assert.Equal(t, opt.Engine, gotOpt.Engine)
+		assert.Nil(t, gotOpt.stages)
+		assert.Nil(t, gotOpt.imageHosts)
+	}
+
+	assert.Nil(t, RegisterEngine("test-custom", EngineFunc(func(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
+		return nil, errors.New("model unavailable")
+	})))
+	c, err = ExtractFromDocument(doc, "http://example.com/explain", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "", c.Description)
+	assert.Equal(t, "Explain", c.Title)
+}
@@ -0,0 +1,193 @@
+package readability
+
+import (
+	"context"
+	"sync"
+)
+
+// ContentStore persists contents re-extracted by an Enricher.
+type ContentStore interface {
+	Save(ctx context.Context, reqURL string, c *Content) error
+}
+
+// ContentStoreFunc is an adapter to allow the use of ordinary functions as a ContentStore.
+type ContentStoreFunc func(ctx context.Context, reqURL string, c *Content) error
+
+// Save calls f(ctx, reqURL, c).
+func (f ContentStoreFunc) Save(ctx context.Context, reqURL string, c *Content) error {
+	return f(ctx, reqURL, c)
+}
+
+// Enricher re-extracts the stages of contents which timed out (see Content.Completeness)
+// with relaxed timeouts in the background, so that callers can return fast partial contents first:
+//
+//	enricher := readability.NewEnricher(extractor, store)
+//	go enricher.Run(ctx)
+//	...
+//	c, _ := extractor.Extract(ctx, url)
+//	enricher.Schedule(url, c)
+type Enricher struct {
+	// Extractor requests pages again. Its Option is relaxed by TimeoutFactor.
+	Extractor *Extractor
+
+	// Store saves enriched contents if not nil.
+	Store ContentStore
+
+	// TimeoutFactor multiplies the timeouts of Extractor.Option for re-extraction.
+	TimeoutFactor uint
+
+	// Concurrency is the number of contents enriched at the same time by Run.
+	Concurrency int
+
+	// jobs is the queue of Schedule, created by the first call to queue.
+	jobs     chan enrichJob
+	jobsOnce sync.Once
+}
+
+// enrichQueueSize is the number of contents an Enricher queues.
+const enrichQueueSize = 100
+
+type enrichJob struct {
+	url     string
+	content *Content
+}
+
+// NewEnricher returns an Enricher re-extracting with e and saving to store,
+// with 5 times longer timeouts. An Enricher built as a struct literal works the same,
+// with its zero fields taken as 1.
+func NewEnricher(e *Extractor, store ContentStore) *Enricher {
+	return &Enricher{
+		Extractor:     e,
+		Store:         store,
+		TimeoutFactor: 5,
+		Concurrency:   1,
+	}
+}
+
+// queue returns the queue of the contents scheduled, of enrichQueueSize contents.
+func (en *Enricher) queue() chan enrichJob {
+	en.jobsOnce.Do(func() {
+		en.jobs = make(chan enrichJob, enrichQueueSize)
+	})
+	return en.jobs
+}
+
+// Schedule queues c extracted from reqURL for Run if some of its stages timed out.
+// It returns false if c is complete or the queue of 100 contents is full.
+func (en *Enricher) Schedule(reqURL string, c *Content) bool {
+	if c == nil || c.Completeness == nil || c.Completeness.Complete() {
+		return false
+	}
+	select {
+	case en.queue() <- enrichJob{url: reqURL, content: c}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run enriches the scheduled contents until ctx is done.
+// Failures are logged and the contents are dropped.
+func (en *Enricher) Run(ctx context.Context) {
+	n := en.Concurrency
+	if n < 1 {
+		n = 1
+	}
+	jobs := en.queue()
+	done := make(chan struct{})
+	for i := 0; i < n; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for {
+				select {
+				case job := <-jobs:
+					if _, err := en.Enrich(ctx, job.url, job.content); err != nil {
+						logger.Warnf("Enricher failed for %v: %v", job.url, err)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		<-done
+	}
+}
+
+// Enrich re-extracts reqURL with relaxed timeouts and returns a copy of c whose timed-out stages
+// are replaced with the new results, saving it to Store if not nil. c is not modified.
+// If the metadata stage timed out, the new content is returned as a whole,
+// since metadata decides how the other fields are extracted.
+//
+// The document of c isn't kept, so the page is requested and extracted again, including
+// the description which decides the images. Only the stages that timed out get longer
+// timeouts, and images are not requested again unless the images stage timed out.
+func (en *Enricher) Enrich(ctx context.Context, reqURL string, c *Content) (*Content, error) {
+	e := *en.Extractor
+	e.Option = relaxOption(en.Extractor.Option, en.TimeoutFactor, c.Completeness)
+	fresh, err := e.Extract(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+	enriched := mergeTimedOutStages(c, fresh, en.Extractor.Option)
+	if en.Store != nil {
+		if err := en.Store.Save(ctx, reqURL, enriched); err != nil {
+			return nil, err
+		}
+	}
+	return enriched, nil
+}
+
+// relaxOption returns a copy of opt with the timeouts of the stages timed out in cmp
+// multiplied by factor. Images are not probed unless the images or the metadata stage timed out,
+// since the images of the content are kept then. If cmp is nil, all timeouts are multiplied.
+func relaxOption(opt *Option, factor uint, cmp *Completeness) *Option {
+	if factor < 1 {
+		factor = 1
+	}
+	if cmp == nil {
+		cmp = &Completeness{Metadata: StatusTimedOut, Description: StatusTimedOut, Images: StatusTimedOut}
+	}
+	relaxed := copyOption(opt)
+	if cmp.Metadata == StatusTimedOut {
+		relaxed.MetadataTimeout *= factor
+	}
+	if cmp.Description == StatusTimedOut {
+		relaxed.DescriptionExtractionTimeout *= factor
+	}
+	if cmp.Images == StatusTimedOut || cmp.Metadata == StatusTimedOut {
+		relaxed.ImageRequestTimeout *= factor
+		relaxed.ImageProbingTimeout *= factor
+	} else {
+		relaxed.SkipImageProbing = true
+	}
+	return relaxed
+}
+
+// mergeTimedOutStages returns a copy of c with the fields of its timed-out stages taken from fresh,
+// and its warnings checked again by opt.
+func mergeTimedOutStages(c, fresh *Content, opt *Option) *Content {
+	if c.Completeness == nil || fresh.Completeness == nil || c.Completeness.Metadata == StatusTimedOut {
+		return fresh
+	}
+	merged := *c
+	completeness := *c.Completeness
+	if completeness.Description == StatusTimedOut {
+		// the fields decided by the article, or by the text of its description
+		merged.Title, merged.RawTitle, merged.TitleSource = fresh.Title, fresh.RawTitle, fresh.TitleSource
+		merged.Description, merged.DescriptionSource = fresh.Description, fresh.DescriptionSource
+		merged.Paragraphs, merged.Outline, merged.articleNode = fresh.Paragraphs, fresh.Outline, fresh.articleNode
+		merged.Explanation, merged.Candidates = fresh.Explanation, fresh.Candidates
+		merged.Engine, merged.Fingerprint, merged.Author = fresh.Engine, fresh.Fingerprint, fresh.Author
+		merged.TextDirection, merged.Paywalled = fresh.TextDirection, fresh.Paywalled
+		completeness.Description = fresh.Completeness.Description
+	}
+	if completeness.Images == StatusTimedOut {
+		merged.Images, merged.PrimaryImage = fresh.Images, fresh.PrimaryImage
+		completeness.Images = fresh.Completeness.Images
+	}
+	merged.Completeness = &completeness
+	merged.Warnings = warnings(&merged, opt)
+	return &merged
+}
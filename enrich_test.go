@@ -0,0 +1,150 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+func TestEnricher(t *testing.T) {
+	html := strings.Replace(articleHTML, `<div class="other">`, `<img src="/slow.png"><div class="other">`, 1)
+	// the image is served only after release is closed, so the first extraction times out
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.png" {
+			<-release
+			w.Write(pngBytes(800, 600))
+			return
+		}
+		fmt.Fprint(w, html)
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.RetryLength = 0
+	opt.ImageProbingTimeout = 50
+	e := NewExtractor(opt)
+	c, err := e.Extract(context.Background(), ts.URL+"/article")
+	close(release)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusTimedOut, c.Completeness.Images)
+	assert.Equal(t, 0, len(c.Images))
+
+	saved := make(chan *Content, 1)
+	en := NewEnricher(e, ContentStoreFunc(func(ctx context.Context, reqURL string, c *Content) error {
+		saved <- c
+		return nil
+	}))
+	// a generous budget, since the image is served right away now
+	en.TimeoutFactor = 200
+	assert.True(t, en.Schedule(ts.URL+"/article", c))
+	assert.False(t, en.Schedule(ts.URL+"/article", &Content{Completeness: &Completeness{}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go en.Run(ctx)
+
+	var enriched *Content
+	select {
+	case enriched = <-saved:
+	case <-time.After(30 * time.Second):
+		t.Fatal("the enriched content was not saved")
+	}
+	assert.Equal(t, StatusCompleted, enriched.Completeness.Images)
+	if !assert.Len(t, enriched.Images, 1) {
+		return
+	}
+	assert.Equal(t, ts.URL+"/slow.png", enriched.Images[0].URL)
+	assert.Equal(t, c.Description, enriched.Description)
+	assert.Equal(t, StatusTimedOut, c.Completeness.Images)
+}
+
+func TestRelaxOption(t *testing.T) {
+	opt := NewOption()
+	opt.MetadataTimeout = 10
+	relaxed := relaxOption(opt, 3, nil)
+	assert.Equal(t, opt.ImageRequestTimeout*3, relaxed.ImageRequestTimeout)
+	assert.Equal(t, opt.DescriptionExtractionTimeout*3, relaxed.DescriptionExtractionTimeout)
+	assert.Equal(t, uint(30), relaxed.MetadataTimeout)
+	assert.Equal(t, uint(10), opt.MetadataTimeout)
+	assert.False(t, relaxed.SkipImageProbing)
+
+	// only the timed-out stages are relaxed, and completed images are not requested again
+	relaxed = relaxOption(opt, 3, &Completeness{Metadata: StatusCompleted, Description: StatusTimedOut, Images: StatusCompleted})
+	assert.Equal(t, opt.DescriptionExtractionTimeout*3, relaxed.DescriptionExtractionTimeout)
+	assert.Equal(t, opt.ImageRequestTimeout, relaxed.ImageRequestTimeout)
+	assert.Equal(t, uint(10), relaxed.MetadataTimeout)
+	assert.True(t, relaxed.SkipImageProbing)
+
+	relaxed = relaxOption(opt, 3, &Completeness{Metadata: StatusCompleted, Description: StatusCompleted, Images: StatusTimedOut})
+	assert.Equal(t, opt.DescriptionExtractionTimeout, relaxed.DescriptionExtractionTimeout)
+	assert.Equal(t, opt.ImageProbingTimeout*3, relaxed.ImageProbingTimeout)
+	assert.False(t, relaxed.SkipImageProbing)
+}
+
+func TestMergeTimedOutStages(t *testing.T) {
+	c := &Content{
+		Title:        "Page",
+		Images:       []Image{{URL: "http://example.com/kept.png"}},
+		Completeness: &Completeness{Metadata: StatusCompleted, Description: StatusTimedOut, Images: StatusCompleted},
+		Warnings:     []Warning{{Code: WarningDescriptionEmpty}},
+	}
+	fresh := &Content{
+		Title:             "Heading",
+		TitleSource:       TitleSourceHeading,
+		Description:       "The article of the page, in full sentences.",
+		DescriptionSource: DescriptionSourceReadability,
+		Author:            "Writer",
+		Images:            []Image{{URL: "http://example.com/fresh.png"}},
+		Paragraphs:        []string{"The article of the page, in full sentences."},
+		Outline:           []Heading{{Level: 2, Text: "Section"}},
+		Candidates:        []ArticleCandidate{{Node: "div#main", Score: 42}},
+		Engine:            EngineMozilla,
+		Fingerprint:       &Fingerprint{Selector: "html > body > div#main"},
+		TextDirection:     TextDirectionRTL,
+		Paywalled:         true,
+		Explanation:       &Explanation{},
+		articleNode:       &html.Node{Type: html.DocumentNode},
+		Completeness:      &Completeness{Metadata: StatusCompleted, Description: StatusCompleted, Images: StatusSkipped},
+	}
+
+	merged := mergeTimedOutStages(c, fresh, NewOption())
+	assert.Equal(t, "Heading", merged.Title)
+	assert.Equal(t, TitleSourceHeading, merged.TitleSource)
+	assert.Equal(t, fresh.Description, merged.Description)
+	assert.Equal(t, DescriptionSourceReadability, merged.DescriptionSource)
+	assert.Equal(t, "Writer", merged.Author)
+	assert.Equal(t, fresh.Paragraphs, merged.Paragraphs)
+	assert.Equal(t, fresh.Outline, merged.Outline)
+	assert.Equal(t, fresh.Candidates, merged.Candidates)
+	assert.Equal(t, EngineMozilla, merged.Engine)
+	assert.Equal(t, fresh.Fingerprint, merged.Fingerprint)
+	assert.Equal(t, TextDirectionRTL, merged.TextDirection)
+	assert.True(t, merged.Paywalled)
+	assert.Equal(t, fresh.Explanation, merged.Explanation)
+	assert.Equal(t, fresh.articleNode, merged.ArticleNode())
+	assert.Equal(t, StatusCompleted, merged.Completeness.Description)
+	// the completed images are kept, and the warnings are checked again
+	assert.Equal(t, c.Images, merged.Images)
+	assert.Equal(t, StatusCompleted, merged.Completeness.Images)
+	assert.Empty(t, merged.Warnings)
+	assert.Equal(t, StatusTimedOut, c.Completeness.Description)
+	assert.Equal(t, "Page", c.Title)
+}
+
+func TestEnricherZeroValue(t *testing.T) {
+	en := &Enricher{Extractor: NewExtractor(NewOption())}
+	partial := &Content{Completeness: &Completeness{Images: StatusTimedOut}}
+	for i := 0; i < enrichQueueSize; i++ {
+		assert.True(t, en.Schedule("http://example.com/", partial))
+	}
+	assert.False(t, en.Schedule("http://example.com/", partial))
+	assert.Equal(t, enrichQueueSize, len(en.queue()))
+}
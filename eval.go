@@ -0,0 +1,167 @@
+package readability
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Sample is a labeled page of an evaluation corpus.
+type Sample struct {
+	// URL is the request URL of the page.
+	URL string `json:"url"`
+
+	// HTML is the raw HTML of the page.
+	HTML string `json:"html"`
+
+	// Title is the expected (gold) title of the page.
+	Title string `json:"title"`
+
+	// Body is the expected (gold) article text of the page.
+	Body string `json:"body"`
+}
+
+// SampleScore contains the evaluation result of a single sample.
+type SampleScore struct {
+	URL        string
+	TitleMatch bool
+	Precision  float64
+	Recall     float64
+	F1         float64
+	Err        error
+}
+
+// Report contains the evaluation result of a corpus.
+// TitleAccuracy, Precision, Recall and F1 are averaged over the samples without errors.
+type Report struct {
+	Samples       []SampleScore
+	Errors        int
+	TitleAccuracy float64
+	Precision     float64
+	Recall        float64
+	F1            float64
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("samples: %v, errors: %v, title accuracy: %.3f, precision: %.3f, recall: %.3f, f1: %.3f",
+		len(r.Samples), r.Errors, r.TitleAccuracy, r.Precision, r.Recall, r.F1)
+}
+
+// ReadSamples reads a corpus in JSON Lines format, one Sample per line.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	samples := []Sample{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			return nil, fmt.Errorf("ReadSamples failed at line %v: %v", n, err)
+		}
+		samples = append(samples, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// Evaluate extracts samples with opt by ExtractFromDocument, the pipeline of Extract,
+// and compares Content.Title and Content.Description with the gold values. Opengraph tags,
+// structured data, title cleaning and Option.Engine are applied as for any extraction.
+//
+// Precision and recall are computed over lowercased word tokens of the
+// plain-text description, so opt.DescriptionAsPlainText is always enabled.
+// Images are not evaluated, and are never requested (see Option.SkipImageProbing).
+func Evaluate(samples []Sample, opt *Option) *Report {
+	o := copyOption(opt)
+	o.DescriptionAsPlainText = true
+	o.SkipImageProbing = true
+
+	report := &Report{Samples: make([]SampleScore, 0, len(samples))}
+	ok := 0
+	for _, s := range samples {
+		score := evaluateSample(s, o)
+		report.Samples = append(report.Samples, score)
+		if score.Err != nil {
+			report.Errors++
+			continue
+		}
+		ok++
+		if score.TitleMatch {
+			report.TitleAccuracy++
+		}
+		report.Precision += score.Precision
+		report.Recall += score.Recall
+		report.F1 += score.F1
+	}
+	if ok > 0 {
+		report.TitleAccuracy /= float64(ok)
+		report.Precision /= float64(ok)
+		report.Recall /= float64(ok)
+		report.F1 /= float64(ok)
+	}
+	return report
+}
+
+func evaluateSample(s Sample, opt *Option) SampleScore {
+	score := SampleScore{URL: s.URL}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s.HTML))
+	if err != nil {
+		score.Err = err
+		return score
+	}
+
+	c, err := ExtractFromDocument(doc, s.URL, opt)
+	if err != nil {
+		score.Err = err
+		return score
+	}
+
+	score.TitleMatch = normalizeSpaces(c.Title) == normalizeSpaces(s.Title)
+	score.Precision, score.Recall, score.F1 = tokenOverlap(c.Description, s.Body)
+	return score
+}
+
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// tokenOverlap returns precision, recall and F1 of extracted tokens against gold tokens,
+// counting each token at most as many times as it appears in gold.
+func tokenOverlap(extracted, gold string) (precision, recall, f1 float64) {
+	et := strings.Fields(strings.ToLower(extracted))
+	gt := strings.Fields(strings.ToLower(gold))
+	if len(et) == 0 || len(gt) == 0 {
+		if len(et) == len(gt) {
+			return 1, 1, 1
+		}
+		return 0, 0, 0
+	}
+
+	goldCounts := map[string]int{}
+	for _, t := range gt {
+		goldCounts[t]++
+	}
+	overlap := 0
+	for _, t := range et {
+		if goldCounts[t] > 0 {
+			goldCounts[t]--
+			overlap++
+		}
+	}
+
+	precision = float64(overlap) / float64(len(et))
+	recall = float64(overlap) / float64(len(gt))
+	if precision+recall > 0 {
+		f1 = 2 * precision * recall / (precision + recall)
+	}
+	return precision, recall, f1
+}
@@ -0,0 +1,54 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var corpus = `{"url": "http://example.com/a", "html": "<html><head><title>Explain</title></head><body><div class=\"article\"><p>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.</p><p>Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat.</p></div></body></html>", "title": "Explain", "body": "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat."}
+
+{"url": "http://example.com/b", "html": "<html><head><title>Other</title></head><body></body></html>", "title": "Wrong", "body": "nothing here"}
+`
+
+func TestReadSamples(t *testing.T) {
+	samples, err := ReadSamples(strings.NewReader(corpus))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(samples))
+	assert.Equal(t, "http://example.com/b", samples[1].URL)
+
+	_, err = ReadSamples(strings.NewReader("{invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	samples, _ := ReadSamples(strings.NewReader(corpus))
+	opt := NewOption()
+	opt.RetryLength = 0
+	report := Evaluate(samples, opt)
+	assert.Equal(t, 2, len(report.Samples))
+	assert.Equal(t, 0, report.Errors)
+	assert.True(t, report.Samples[0].TitleMatch)
+	assert.Equal(t, 1.0, report.Samples[0].Precision)
+	assert.Equal(t, 1.0, report.Samples[0].Recall)
+	assert.False(t, report.Samples[1].TitleMatch)
+	assert.Equal(t, 0.0, report.Samples[1].F1)
+	assert.Equal(t, 0.5, report.TitleAccuracy)
+}
+
+func TestTokenOverlap(t *testing.T) {
+	p, r, f := tokenOverlap("a b c d", "a b e e e e e e")
+	assert.Equal(t, 0.5, p)
+	assert.Equal(t, 0.25, r)
+	assert.InDelta(t, 1.0/3.0, f, 0.0001)
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	samples, _ := ReadSamples(strings.NewReader(corpus))
+	opt := NewOption()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Evaluate(samples, opt)
+	}
+}
@@ -0,0 +1,90 @@
+package readability
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	// exifHeaderLength is the maximum number of leading bytes of a JPEG image searched for the EXIF orientation.
+	// APP1 is placed right after SOI (or APP0) and the orientation is in its first IFD.
+	exifHeaderLength = 16 * 1024
+
+	// exifOrientationTag is the TIFF tag of the orientation in the first IFD.
+	exifOrientationTag = 0x0112
+)
+
+func isJPEG(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0xff && b[1] == 0xd8
+}
+
+// exifOrientation returns the EXIF orientation (1 to 8) of the JPEG image starting with b,
+// or 0 if it is not found in b.
+func exifOrientation(b []byte) int {
+	if !isJPEG(b) {
+		return 0
+	}
+	for i := 2; i+4 <= len(b); {
+		if b[i] != 0xff {
+			return 0
+		}
+		marker := b[i+1]
+		// start of scan, or start of frame which is placed after APPn
+		if marker == 0xda || (marker >= 0xc0 && marker <= 0xcf && marker != 0xc4 && marker != 0xc8 && marker != 0xcc) {
+			return 0
+		}
+		length := int(binary.BigEndian.Uint16(b[i+2 : i+4]))
+		end := i + 2 + length
+		if end > len(b) {
+			end = len(b)
+		}
+		if marker == 0xe1 && i+4 <= end {
+			if o := tiffOrientation(b[i+4 : end]); o != 0 {
+				return o
+			}
+		}
+		i += 2 + length
+	}
+	return 0
+}
+
+// tiffOrientation returns the orientation in the first IFD of an "Exif\0\0" APP1 payload.
+func tiffOrientation(b []byte) int {
+	if !bytes.HasPrefix(b, []byte("Exif\x00\x00")) || len(b) < 14 {
+		return 0
+	}
+	tiff := b[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	n := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < n; i++ {
+		e := ifd + 2 + i*12
+		if e+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[e:e+2]) == exifOrientationTag {
+			if o := int(order.Uint16(tiff[e+8 : e+10])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// isRotatedOrientation returns true if the EXIF orientation o rotates the image by 90 or 270 degrees,
+// so that the displayed width and height are swapped.
+func isRotatedOrientation(o int) bool {
+	return o >= 5 && o <= 8
+}
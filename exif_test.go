@@ -0,0 +1,58 @@
+package readability
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jpegBytes returns a JPEG header of w x h with an EXIF orientation if orientation is not 0.
+func jpegBytes(w, h uint16, orientation uint16, order binary.ByteOrder) []byte {
+	b := []byte{0xff, 0xd8}
+	if orientation != 0 {
+		tiff := make([]byte, 8+2+12+4)
+		if order == binary.LittleEndian {
+			copy(tiff, "II*\x00")
+		} else {
+			copy(tiff, "MM\x00*")
+		}
+		order.PutUint32(tiff[4:], 8)
+		order.PutUint16(tiff[8:], 1)
+		order.PutUint16(tiff[10:], exifOrientationTag)
+		order.PutUint16(tiff[12:], 3) // SHORT
+		order.PutUint32(tiff[14:], 1)
+		order.PutUint16(tiff[18:], orientation)
+		app1 := append([]byte("Exif\x00\x00"), tiff...)
+		b = append(b, 0xff, 0xe1, 0, 0)
+		binary.BigEndian.PutUint16(b[len(b)-2:], uint16(len(app1)+2))
+		b = append(b, app1...)
+	}
+	sof := []byte{0xff, 0xc0, 0, 17, 8, 0, 0, 0, 0, 3, 1, 0x22, 0, 2, 0x11, 1, 3, 0x11, 1}
+	binary.BigEndian.PutUint16(sof[5:], h)
+	binary.BigEndian.PutUint16(sof[7:], w)
+	b = append(b, sof...)
+	return append(b, make([]byte, 64)...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	assert.Equal(t, 6, exifOrientation(jpegBytes(640, 480, 6, binary.BigEndian)))
+	assert.Equal(t, 8, exifOrientation(jpegBytes(640, 480, 8, binary.LittleEndian)))
+	assert.Equal(t, 0, exifOrientation(jpegBytes(640, 480, 0, binary.BigEndian)))
+	assert.Equal(t, 0, exifOrientation(pngBytes(640, 480)))
+}
+
+func TestDetectImageRotated(t *testing.T) {
+	info, err := detectImage(bytes.NewReader(jpegBytes(640, 480, 6, binary.BigEndian)))
+	assert.Nil(t, err)
+	assert.True(t, info.rotated)
+	assert.Equal(t, uint32(480), info.size.Width)
+	assert.Equal(t, uint32(640), info.size.Height)
+
+	info, err = detectImage(bytes.NewReader(jpegBytes(640, 480, 3, binary.LittleEndian)))
+	assert.Nil(t, err)
+	assert.False(t, info.rotated)
+	assert.Equal(t, uint32(640), info.size.Width)
+	assert.Equal(t, uint32(480), info.size.Height)
+}
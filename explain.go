@@ -0,0 +1,131 @@
+package readability
+
+// Explanation describes why the sections of an extracted article were kept or removed.
+type Explanation struct {
+	// SiblingScoreThreshold is the minimum score for a sibling of the best candidate
+	// to be included in the article.
+	SiblingScoreThreshold float64 `json:"siblingScoreThreshold"`
+
+	// Siblings contains the decisions for the best candidate and its siblings, in document order.
+	Siblings []SiblingDecision `json:"siblings"`
+
+	// Cleaning contains the decisions made by conditional cleaning, in document order.
+	Cleaning []CleanDecision `json:"cleaning"`
+}
+
+// ReasonCode is a stable, machine-readable code of a decision in Explanation.
+// Codes never change across versions, while the human-readable texts may.
+type ReasonCode string
+
+// Reason codes of SiblingDecision.
+const (
+	ReasonBestCandidate     ReasonCode = "BEST_CANDIDATE"
+	ReasonSiblingScore      ReasonCode = "SIBLING_SCORE"
+	ReasonLongParagraph     ReasonCode = "LONG_PARAGRAPH"
+	ReasonShortSentence     ReasonCode = "SHORT_SENTENCE"
+	ReasonSiblingNotQualify ReasonCode = "SIBLING_NOT_QUALIFIED"
+)
+
+// Reason codes of CleanDecision.
+const (
+	ReasonNegativeScore    ReasonCode = "NEGATIVE_SCORE"
+	ReasonManyCommas       ReasonCode = "MANY_COMMAS"
+	ReasonTooManyImages    ReasonCode = "TOO_MANY_IMAGES"
+	ReasonTooManyListItems ReasonCode = "TOO_MANY_LIST_ITEMS"
+	ReasonTooManyInputs    ReasonCode = "TOO_MANY_INPUTS"
+	ReasonTooShort         ReasonCode = "TOO_SHORT"
+	ReasonTooManyLinks     ReasonCode = "TOO_MANY_LINKS"
+	ReasonTooManyEmbeds    ReasonCode = "TOO_MANY_EMBEDS"
+	ReasonPassed           ReasonCode = "PASSED"
+)
+
+var reasonTexts = map[ReasonCode]string{
+	ReasonBestCandidate:     "best candidate",
+	ReasonSiblingScore:      "sibling score is not less than threshold",
+	ReasonLongParagraph:     "<p> longer than 80 with link density less than 0.25",
+	ReasonShortSentence:     "<p> shorter than 80 without links, ending with a sentence",
+	ReasonSiblingNotQualify: "not the best candidate, low sibling score and not a qualified <p>",
+	ReasonNegativeScore:     "negative score with class weight",
+	ReasonManyCommas:        "contains more than 10 commas",
+	ReasonTooManyImages:     "too many images",
+	ReasonTooManyListItems:  "more <li>s than <p>s",
+	ReasonTooManyInputs:     "<p>s less than 3 * <inputs>s",
+	ReasonTooShort:          "too short content length without a single image",
+	ReasonTooManyLinks:      "too many links for its weight",
+	ReasonTooManyEmbeds:     "<embed>s with too short content length, or too many <embed>s",
+	ReasonPassed:            "passed all conditional rules",
+}
+
+// Text returns the human-readable description of c.
+func (c ReasonCode) Text() string {
+	return reasonTexts[c]
+}
+
+// SiblingDecision describes whether a sibling of the best candidate was included in the article.
+type SiblingDecision struct {
+	Node   string     `json:"node"`
+	Score  float64    `json:"score"`
+	Kept   bool       `json:"kept"`
+	Code   ReasonCode `json:"code"`
+	Reason string     `json:"reason"`
+}
+
+// CleanDecision describes whether a node of the article survived conditional cleaning.
+// Counts, TextLength and LinkDensity are zero values if the node was decided
+// before the conditional rules were evaluated.
+type CleanDecision struct {
+	Node        string         `json:"node"`
+	Score       float64        `json:"score"`
+	Weight      float64        `json:"weight"`
+	Counts      map[string]int `json:"counts,omitempty"`
+	TextLength  int            `json:"textLength"`
+	LinkDensity float64        `json:"linkDensity"`
+	Kept        bool           `json:"kept"`
+	Code        ReasonCode     `json:"code"`
+	Reason      string         `json:"reason"`
+}
+
+// Removed returns the cleaning decisions which removed a node.
+func (e *Explanation) Removed() []CleanDecision {
+	if e == nil {
+		return nil
+	}
+	removed := []CleanDecision{}
+	for _, d := range e.Cleaning {
+		if !d.Kept {
+			removed = append(removed, d)
+		}
+	}
+	return removed
+}
+
+func (e *Explanation) setSiblingScoreThreshold(threshold float64) {
+	if e == nil {
+		return
+	}
+	e.SiblingScoreThreshold = threshold
+}
+
+func (e *Explanation) addSibling(s *mySelection, score float64, kept bool, code ReasonCode) {
+	if e == nil {
+		return
+	}
+	if !kept {
+		code = ReasonSiblingNotQualify
+	}
+	e.Siblings = append(e.Siblings, SiblingDecision{
+		Node:   s.String(),
+		Score:  score,
+		Kept:   kept,
+		Code:   code,
+		Reason: code.Text(),
+	})
+}
+
+func (e *Explanation) addCleaning(d CleanDecision) {
+	if e == nil {
+		return
+	}
+	d.Reason = d.Code.Text()
+	e.Cleaning = append(e.Cleaning, d)
+}
@@ -0,0 +1,47 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+var articleHTML = `<html><head><title>Explain</title></head><body>
+<div id="content" class="article">
+<p>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.</p>
+<p>Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat, duis aute irure.</p>
+<p>Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est laborum, sed ut perspiciatis.</p>
+<div class="widget"><a href="/a">Link one</a> <a href="/b">Link two</a></div>
+</div>
+<div class="other"><p>Short note for siblings.</p></div>
+</body></html>`
+
+func TestExplanation(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	opt := NewOption()
+	opt.Explain = true
+	opt.RetryLength = 0
+	_, exp := description(doc, opt)
+	assert.NotNil(t, exp)
+	assert.True(t, exp.SiblingScoreThreshold >= 10.0)
+	assert.NotEmpty(t, exp.Siblings)
+	assert.Equal(t, "best candidate", exp.Siblings[0].Reason)
+	assert.Equal(t, ReasonBestCandidate, exp.Siblings[0].Code)
+	assert.True(t, exp.Siblings[0].Kept)
+	assert.NotEmpty(t, exp.Cleaning)
+	assert.NotEmpty(t, exp.Removed())
+	for _, d := range exp.Cleaning {
+		assert.NotEmpty(t, d.Code)
+		assert.Equal(t, d.Code.Text(), d.Reason)
+	}
+}
+
+func TestExplanationDisabled(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	opt := NewOption()
+	_, exp := description(doc, opt)
+	assert.Nil(t, exp)
+	assert.Nil(t, exp.Removed())
+}
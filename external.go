@@ -0,0 +1,269 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExternalExtractor extracts contents with an external service, such as an in-house ML extractor,
+// whose output is compared and merged with the local result by Compare.
+type ExternalExtractor interface {
+	// ExtractExternal returns the contents of the page at reqURL whose HTML is html.
+	ExtractExternal(ctx context.Context, reqURL, html string) (*Content, error)
+}
+
+// ExternalExtractorFunc is an adapter to allow the use of ordinary functions as an ExternalExtractor.
+type ExternalExtractorFunc func(ctx context.Context, reqURL, html string) (*Content, error)
+
+// ExtractExternal calls f(ctx, reqURL, html).
+func (f ExternalExtractorFunc) ExtractExternal(ctx context.Context, reqURL, html string) (*Content, error) {
+	return f(ctx, reqURL, html)
+}
+
+// HTTPExternalExtractor is an ExternalExtractor calling a JSON API:
+// it posts {"url": string, "html": string} to Endpoint, and decodes the response as Content
+// in the JSON schema of this package (see README). Responses other than 2xx are returned as *HTTPError.
+type HTTPExternalExtractor struct {
+	Endpoint string
+
+	// Client is used for the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Header is added to the requests, such as Authorization.
+	Header http.Header
+}
+
+// ExtractExternal requests e.Endpoint to extract the page.
+func (e *HTTPExternalExtractor) ExtractExternal(ctx context.Context, reqURL, html string) (*Content, error) {
+	body, err := json.Marshal(struct {
+		URL  string `json:"url"`
+		HTML string `json:"html"`
+	}{reqURL, html})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range e.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &HTTPError{URL: e.Endpoint, StatusCode: resp.StatusCode}
+	}
+	c := &Content{}
+	if err := json.NewDecoder(resp.Body).Decode(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Field is a field of Content compared by Compare or transformed by a Transformer.
+type Field string
+
+// Fields compared by Compare.
+const (
+	FieldTitle         Field = "title"
+	FieldDescription   Field = "description"
+	FieldAuthor        Field = "author"
+	FieldImages        Field = "images"
+	FieldPublishedTime Field = "publishedTime"
+)
+
+// Fields transformed by Transformers in addition to FieldTitle and FieldDescription.
+const (
+	FieldParagraphs Field = "paragraphs"
+	FieldOutline    Field = "outline"
+)
+
+var comparedFields = []Field{FieldTitle, FieldDescription, FieldAuthor, FieldImages, FieldPublishedTime}
+
+// Winner is the extractor whose value of a field is used for the merged result.
+type Winner string
+
+// Winners of fields.
+const (
+	WinnerLocal    Winner = "local"
+	WinnerExternal Winner = "external"
+)
+
+// FieldPolicy selects the winner of field of the local and the external results.
+type FieldPolicy func(field Field, local, external *Content) Winner
+
+// MergePolicy is the FieldPolicy of each field. Fields not in MergePolicy use PreferLocal.
+type MergePolicy map[Field]FieldPolicy
+
+// PreferLocal is a FieldPolicy selecting the local value unless it is empty.
+func PreferLocal(field Field, local, external *Content) Winner {
+	if fieldLength(local, field) == 0 && fieldLength(external, field) > 0 {
+		return WinnerExternal
+	}
+	return WinnerLocal
+}
+
+// PreferExternal is a FieldPolicy selecting the external value unless it is empty.
+func PreferExternal(field Field, local, external *Content) Winner {
+	if fieldLength(external, field) == 0 && fieldLength(local, field) > 0 {
+		return WinnerLocal
+	}
+	return WinnerExternal
+}
+
+// PreferLonger is a FieldPolicy selecting the longer value, or the one with more images.
+// The local value wins ties.
+func PreferLonger(field Field, local, external *Content) Winner {
+	if fieldLength(external, field) > fieldLength(local, field) {
+		return WinnerExternal
+	}
+	return WinnerLocal
+}
+
+// FieldComparison is the comparison of a field by Compare.
+type FieldComparison struct {
+	Winner Winner `json:"winner"`
+
+	// Similarity is how much the local and the external values agree, from 0 to 1:
+	// the F1 score of their words, or of the URLs for FieldImages.
+	Similarity float64 `json:"similarity"`
+}
+
+// Comparison is the result of Compare.
+type Comparison struct {
+	Local    *Content `json:"local"`
+	External *Content `json:"external,omitempty"`
+
+	// ExternalErr is the error of the ExternalExtractor. If it is not nil, Merged is Local.
+	ExternalErr error `json:"-"`
+
+	// Merged is a copy of Local with the fields won by the external result replaced.
+	Merged *Content `json:"merged"`
+
+	// Fields contains the comparison of each field. It is empty if ExternalErr is not nil.
+	Fields map[Field]FieldComparison `json:"fields"`
+}
+
+// Compare extracts doc with opt and with ext concurrently, then compares their results
+// and merges them selecting the winner of each field by policy.
+// An error is returned only if the local extraction fails; failures of ext are
+// recorded as Comparison.ExternalErr.
+//
+// ext receives the HTML of doc rendered before the local extraction.
+func Compare(ctx context.Context, doc *goquery.Document, reqURL string, opt *Option,
+	ext ExternalExtractor, policy MergePolicy) (*Comparison, error) {
+	html, err := doc.Html()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type externalResult struct {
+		c   *Content
+		err error
+	}
+	ch := make(chan externalResult, 1)
+	go func() {
+		c, err := ext.ExtractExternal(ctx, reqURL, html)
+		ch <- externalResult{c, err}
+	}()
+
+	local, err := ExtractFromDocument(doc, reqURL, opt)
+	if err != nil {
+		return nil, err
+	}
+	r := <-ch
+	cmp := &Comparison{Local: local, External: r.c, ExternalErr: r.err, Merged: local,
+		Fields: map[Field]FieldComparison{}}
+	if r.err != nil || r.c == nil {
+		cmp.External = nil
+		return cmp, nil
+	}
+
+	merged := *local
+	for _, f := range comparedFields {
+		p := policy[f]
+		if p == nil {
+			p = PreferLocal
+		}
+		w := p(f, local, r.c)
+		if w == WinnerExternal {
+			setField(&merged, r.c, f)
+		}
+		cmp.Fields[f] = FieldComparison{Winner: w, Similarity: fieldSimilarity(local, r.c, f)}
+	}
+	cmp.Merged = &merged
+	return cmp, nil
+}
+
+// fieldLength returns the length (runes) of field of c, or the number of images for FieldImages.
+func fieldLength(c *Content, field Field) int {
+	if field == FieldImages {
+		return len(c.Images)
+	}
+	return utf8.RuneCountInString(fieldText(c, field))
+}
+
+// fieldText returns the value of field of c, or the space-separated URLs for FieldImages.
+func fieldText(c *Content, field Field) string {
+	switch field {
+	case FieldTitle:
+		return c.Title
+	case FieldDescription:
+		return c.Description
+	case FieldAuthor:
+		return c.Author
+	case FieldPublishedTime:
+		return c.PublishedTime
+	case FieldImages:
+		var buf bytes.Buffer
+		for _, img := range c.Images {
+			buf.WriteString(img.URL)
+			buf.WriteByte(' ')
+		}
+		return buf.String()
+	}
+	return ""
+}
+
+// setField sets field of c to the value of src.
+func setField(c, src *Content, field Field) {
+	switch field {
+	case FieldTitle:
+		c.Title, c.TitleSource = src.Title, src.TitleSource
+	case FieldDescription:
+		// the paragraphs of the local article don't match the external description
+		c.Description, c.Paragraphs, c.articleNode = src.Description, src.Paragraphs, src.articleNode
+		c.DescriptionSource = src.DescriptionSource
+	case FieldAuthor:
+		c.Author = src.Author
+	case FieldPublishedTime:
+		c.PublishedTime = src.PublishedTime
+	case FieldImages:
+		c.Images = src.Images
+	}
+}
+
+// fieldSimilarity returns the F1 score of the words (or image URLs) of field of a and b.
+func fieldSimilarity(a, b *Content, field Field) float64 {
+	_, _, f1 := tokenOverlap(fieldText(a, field), fieldText(b, field))
+	return f1
+}
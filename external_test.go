@@ -0,0 +1,78 @@
+package readability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func compareDocument() *goquery.Document {
+	p := "<p>" + strings.Repeat("The council approved the new budget after a long debate on Tuesday. ", 5) + "</p>"
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Budget approved</title></head><body><div class="article">` + p + p + `</div></body></html>`))
+	return doc
+}
+
+func TestCompare(t *testing.T) {
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	ext := ExternalExtractorFunc(func(ctx context.Context, reqURL, html string) (*Content, error) {
+		assert.Equal(t, "https://example.com/budget", reqURL)
+		assert.Contains(t, html, "<title>Budget approved</title>")
+		return &Content{Title: "Council approves budget", Description: "The council approved the new budget.", Author: "Jane Doe"}, nil
+	})
+
+	cmp, err := Compare(context.Background(), compareDocument(), "https://example.com/budget", opt, ext,
+		MergePolicy{FieldTitle: PreferExternal, FieldDescription: PreferLonger})
+	assert.Nil(t, err)
+	assert.Nil(t, cmp.ExternalErr)
+	assert.Equal(t, "Council approves budget", cmp.Merged.Title)
+	assert.Equal(t, cmp.Local.Description, cmp.Merged.Description)
+	assert.Equal(t, "Jane Doe", cmp.Merged.Author) // the local author is empty
+	assert.Equal(t, "Budget approved", cmp.Local.Title)
+	assert.Equal(t, WinnerExternal, cmp.Fields[FieldTitle].Winner)
+	assert.Equal(t, WinnerLocal, cmp.Fields[FieldDescription].Winner)
+	assert.Equal(t, WinnerExternal, cmp.Fields[FieldAuthor].Winner)
+	assert.True(t, cmp.Fields[FieldDescription].Similarity > 0 && cmp.Fields[FieldDescription].Similarity < 1)
+	assert.Equal(t, 1.0, cmp.Fields[FieldPublishedTime].Similarity)
+
+	failing := ExternalExtractorFunc(func(ctx context.Context, reqURL, html string) (*Content, error) {
+		return nil, errors.New("unavailable")
+	})
+	cmp, err = Compare(context.Background(), compareDocument(), "https://example.com/budget", opt, failing, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, cmp.ExternalErr)
+	assert.Equal(t, cmp.Local, cmp.Merged)
+	assert.Empty(t, cmp.Fields)
+}
+
+func TestHTTPExternalExtractor(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct{ URL, HTML string }
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"title": "From ` + req.URL + `", "description": "Body", "images": [{"url": "https://example.com/a.jpg", "width": 800, "height": 600}]}`))
+	}))
+	defer ts.Close()
+
+	e := &HTTPExternalExtractor{Endpoint: ts.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	c, err := e.ExtractExternal(context.Background(), "https://example.com/a", "<html></html>")
+	assert.Nil(t, err)
+	assert.Equal(t, "From https://example.com/a", c.Title)
+	assert.Equal(t, uint32(800), c.Images[0].Size.Width)
+
+	e.Header = nil
+	_, err = e.ExtractExternal(context.Background(), "https://example.com/a", "<html></html>")
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+}
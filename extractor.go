@@ -0,0 +1,203 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Extractor extracts contents of web pages with the same Option and http.Client.
+// It is safe for concurrent use if Option and Client are not modified.
+type Extractor struct {
+	// Option is used for every extraction.
+	Option *Option
+
+	// Client is used for requesting pages.
+	Client *http.Client
+
+	// Concurrency is the maximum number of pages extracted at the same time by All and AllFrom.
+	Concurrency int
+
+	// HealthCheckCanary is a flag whether HealthCheck also extracts an embedded page
+	// and checks the result.
+	HealthCheckCanary bool
+}
+
+// NewExtractor returns an Extractor using opt and http.DefaultClient.
+// If opt is nil, the default option is used.
+func NewExtractor(opt *Option) *Extractor {
+	if opt == nil {
+		opt = NewOption()
+	}
+	return &Extractor{
+		Option:      opt,
+		Client:      http.DefaultClient,
+		Concurrency: 4,
+	}
+}
+
+// Result contains the extraction result for a URL.
+type Result struct {
+	URL     string
+	Content *Content
+	Err     error
+}
+
+// Extract requests to reqURL with ctx then returns contents extracted from the response.
+func (e *Extractor) Extract(ctx context.Context, reqURL string) (*Content, error) {
+	if err := e.Option.Validate(); err != nil {
+		return nil, err
+	}
+	page, err := e.fetch(ctx, reqURL)
+	if err != nil {
+		if e.Option.MirrorResolver != nil && !e.Option.DryRun && isBlocked(err) {
+			return e.extractFromMirrors(ctx, reqURL, err)
+		}
+		return nil, err
+	}
+	fetch := func(u string) (*fetchedPage, error) {
+		return e.fetch(ctx, u)
+	}
+	readerRetry := e.Option.ReaderRetry && consentWall(page.doc, e.Option.RetryLength)
+	if readerRetry && !e.Option.DryRun {
+		if c := e.extractAsReader(ctx, reqURL); c != nil {
+			return extractFromAMP(c, e.Option, fetch), nil
+		}
+	}
+	c, err := extractFromDocument(page.doc, reqURL, e.Option)
+	if err != nil {
+		return nil, err
+	}
+	if readerRetry && e.Option.DryRun {
+		// the first of readerURLs is requested at least
+		c.planRequest(RequestReader, readerURLs(reqURL)[0])
+	}
+	c.setFetched(page, e.Option)
+	c.setRedirects(page)
+	return extractFromAMP(c, e.Option, fetch), nil
+}
+
+// All extracts contents of urls concurrently, yielding results as they complete:
+//
+//	for res := range extractor.All(ctx, urls) {
+//		...
+//	}
+//
+// Results are not ordered. Breaking out of the loop cancels the remaining extractions.
+func (e *Extractor) All(ctx context.Context, urls []string) iter.Seq[Result] {
+	return e.AllFrom(ctx, slices.Values(urls))
+}
+
+// AllFrom acts same as All, except that urls are read from a sequence
+// so that huge batches don't need to be loaded in memory at once.
+func (e *Extractor) AllFrom(ctx context.Context, urls iter.Seq[string]) iter.Seq[Result] {
+	return func(yield func(Result) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		jobs := make(chan string)
+		results := make(chan Result)
+
+		go func() {
+			defer close(jobs)
+			for u := range urls {
+				select {
+				case jobs <- u:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		n := e.Concurrency
+		if n < 1 {
+			n = 1
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for u := range jobs {
+					c, err := e.Extract(ctx, u)
+					select {
+					case results <- Result{URL: u, Content: c, Err: err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for r := range results {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+func (e *Extractor) fetch(ctx context.Context, reqURL string) (*fetchedPage, error) {
+	return e.fetchWith(ctx, reqURL, false)
+}
+
+// fetchWith acts same as fetch, except that the page is requested with the reader profile
+// if reader is true. See Option.ReaderRetry.
+func (e *Extractor) fetchWith(ctx context.Context, reqURL string, reader bool) (*fetchedPage, error) {
+	defer observeStage(e.Option, StageFetch, time.Now())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if reader {
+		client = readerRequest(req, client)
+	}
+	client, redirects := pageClient(client, e.Option)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, redirectError(err)
+	}
+	defer resp.Body.Close()
+	if t := responseBotChallenge(resp); t != "" {
+		return nil, &BotChallengeError{URL: reqURL, Type: t, StatusCode: resp.StatusCode}
+	}
+	if resp.StatusCode >= 400 {
+		return nil, statusError(resp, reqURL, e.Option)
+	}
+	page, err := parsePage(resp, e.Option)
+	if errors.Is(err, ErrUnsupportedContentType) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	page.redirects = *redirects
+	return page, nil
+}
+
+// statusError returns the error of resp, the response of reqURL with a 4xx or 5xx status code:
+// a *BotChallengeError for a challenge page, otherwise an *HTTPError.
+func statusError(resp *http.Response, reqURL string, opt *Option) error {
+	if blockedStatusCodes[resp.StatusCode] {
+		// blocked pages are parsed only to tell challenges from plain errors
+		if page, err := parsePage(resp, opt); err == nil {
+			if t := botChallenge(page.doc); t != "" {
+				return &BotChallengeError{URL: reqURL, Type: t, StatusCode: resp.StatusCode}
+			}
+		}
+	}
+	return &HTTPError{URL: reqURL, StatusCode: resp.StatusCode}
+}
@@ -0,0 +1,71 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			fmt.Fprint(w, articleHTML)
+		case "/og":
+			fmt.Fprint(w, `<html><head><meta property="og:title" content="OG Title" /></head></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestExtractorExtract(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	e := NewExtractor(opt)
+	c, err := e.Extract(context.Background(), ts.URL+"/article")
+	assert.Nil(t, err)
+	assert.Equal(t, "Explain", c.Title)
+	assert.Contains(t, c.Description, "Lorem ipsum")
+
+	_, err = e.Extract(context.Background(), ts.URL+"/missing")
+	assert.NotNil(t, err)
+}
+
+func TestExtractorAll(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	e := NewExtractor(opt)
+	urls := []string{ts.URL + "/article", ts.URL + "/og", ts.URL + "/missing"}
+
+	titles := map[string]string{}
+	errs := 0
+	for res := range e.All(context.Background(), urls) {
+		if res.Err != nil {
+			errs++
+			continue
+		}
+		titles[res.URL] = res.Content.Title
+	}
+	assert.Equal(t, 1, errs)
+	assert.Equal(t, "Explain", titles[ts.URL+"/article"])
+	assert.Equal(t, "OG Title", titles[ts.URL+"/og"])
+
+	// breaking out of the loop stops the iteration
+	n := 0
+	for range e.All(context.Background(), urls) {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,215 @@
+package readability
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrUnsupportedContentType is wrapped by errors returned when a page responds with content
+// which is not a document, plain text or an image, such as PDF or JSON.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// UnsupportedContentTypeError is returned when a page responds with content which can't be
+// extracted. It wraps ErrUnsupportedContentType.
+type UnsupportedContentTypeError struct {
+	URL string
+
+	// ContentType is the media type of the response, from its Content-Type header
+	// or sniffed from its content, like "application/pdf".
+	ContentType string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported content type %v for %v", e.ContentType, e.URL)
+}
+
+func (e *UnsupportedContentTypeError) Unwrap() error {
+	return ErrUnsupportedContentType
+}
+
+// ErrTooManyRedirects is wrapped by errors returned when a page redirects more times than
+// Option.MaxRedirects.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// TooManyRedirectsError is returned when a page redirects more times than Option.MaxRedirects.
+// It wraps ErrTooManyRedirects.
+type TooManyRedirectsError struct {
+	URL string
+
+	// Location is the redirect which was not followed, and MaxRedirects is the limit exceeded.
+	Location     string
+	MaxRedirects int
+}
+
+func (e *TooManyRedirectsError) Error() string {
+	return fmt.Sprintf("stopped after %v redirects from %v: %v", e.MaxRedirects, e.URL, e.Location)
+}
+
+func (e *TooManyRedirectsError) Unwrap() error {
+	return ErrTooManyRedirects
+}
+
+// documentMediaTypes are the media types parsed as documents, in addition to plain text.
+var documentMediaTypes = map[string]bool{
+	"text/html": true, "application/xhtml+xml": true, "application/xml": true, "text/xml": true,
+}
+
+// fetchedPage is a page requested and parsed by Extract or Extractor.
+type fetchedPage struct {
+	doc     *goquery.Document
+	charset *CharsetDecision
+
+	// truncated is true if the response was larger than Option.MaxHTMLSize.
+	truncated bool
+
+	// url is the final URL of the page, and redirects are the URLs redirected from.
+	url       string
+	redirects []string
+}
+
+// pageClient returns a copy of client following up to opt.MaxRedirects redirects,
+// and the URLs redirected from, which are recorded while the client follows redirects.
+// A further redirect fails the request with *TooManyRedirectsError, which
+// redirectError returns unwrapped from the error of the client.
+func pageClient(client *http.Client, opt *Option) (*http.Client, *[]string) {
+	redirects := &[]string{}
+	c := *client
+	max := opt.MaxRedirects
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return &TooManyRedirectsError{URL: via[0].URL.String(), Location: req.URL.String(), MaxRedirects: max}
+		}
+		*redirects = (*redirects)[:0]
+		for _, r := range via {
+			*redirects = append(*redirects, r.URL.String())
+		}
+		return nil
+	}
+	return &c, redirects
+}
+
+// redirectError returns *TooManyRedirectsError if err of a request by pageClient wraps it,
+// so that callers don't take it for *url.Error of a network failure, and err otherwise.
+func redirectError(err error) error {
+	var re *TooManyRedirectsError
+	if errors.As(err, &re) {
+		return re
+	}
+	return err
+}
+
+// parsePage reads the body of resp up to opt.MaxHTMLSize bytes, if it is not 0, and parses it
+// by its Content-Type, or by its content if the type is missing or generic:
+// documents and plain text are parsed by parseDocument, and images are parsed as
+// a document showing the image, like browsers do, so that they are extracted as image-only contents.
+// Other types are returned as *UnsupportedContentTypeError.
+func parsePage(resp *http.Response, opt *Option) (*fetchedPage, error) {
+	var body io.Reader = resp.Body
+	limited := &htmlLimitReader{r: resp.Body, remaining: opt.MaxHTMLSize}
+	if opt.MaxHTMLSize > 0 {
+		body = limited
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		reqURL = resp.Request.URL.String()
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mt, _, _ := mime.ParseMediaType(contentType)
+	if mt == "" || mt == "application/octet-stream" {
+		// servers omitting the type: texts are parsed as before, as documents
+		sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(b))
+		if strings.HasPrefix(sniffed, "text/") {
+			sniffed = "text/html"
+		}
+		mt = sniffed
+	}
+	switch {
+	case strings.HasPrefix(mt, "image/"):
+		b, contentType = []byte(imageDocument(reqURL, b)), "text/html"
+	case !documentMediaTypes[mt] && !isPlainText(mt):
+		return nil, &UnsupportedContentTypeError{URL: reqURL, ContentType: mt}
+	}
+
+	doc, cs, err := parseDocument(bytes.NewReader(b), contentType, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchedPage{doc: doc, charset: cs, truncated: limited.truncated, url: reqURL}, nil
+}
+
+// imageDocument returns an HTML document showing the image b at reqURL, titled with its file name.
+func imageDocument(reqURL string, b []byte) string {
+	name := reqURL
+	if u, err := url.Parse(reqURL); err == nil {
+		name = u.Host
+		if base := path.Base(u.Path); base != "/" && base != "." {
+			name = base
+		}
+	}
+	size := ""
+	if info, err := detectImage(bytes.NewReader(b)); err == nil && info.size != nil {
+		size = fmt.Sprintf(` width="%d" height="%d"`, info.size.Width, info.size.Height)
+	}
+	return fmt.Sprintf(`<html><head><title>%s</title></head><body><img src="%s"%s></body></html>`,
+		html.EscapeString(name), html.EscapeString(reqURL), size)
+}
+
+// setFetched records how page was fetched to c: its charset, and a warning if it is truncated.
+func (c *Content) setFetched(page *fetchedPage, opt *Option) {
+	c.Charset = page.charset
+	if page.truncated {
+		c.Warnings = append(c.Warnings, Warning{Code: WarningHTMLTruncated,
+			Message: fmt.Sprintf("page is larger than %v bytes and truncated", opt.MaxHTMLSize)})
+	}
+}
+
+// setRedirects records the redirects of page, the page requested for c, to c, and sets
+// c.NormalizedURL to its final URL.
+func (c *Content) setRedirects(page *fetchedPage) {
+	if len(page.redirects) > 0 {
+		c.Redirects = page.redirects
+	}
+	if u := normalizeURL(page.url); u != "" {
+		c.NormalizedURL = u
+	}
+}
+
+// htmlLimitReader reads up to remaining bytes of r, and records whether r has more.
+type htmlLimitReader struct {
+	r         io.Reader
+	remaining int64
+	truncated bool
+}
+
+func (l *htmlLimitReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		if !l.truncated {
+			var b [1]byte
+			n, _ := io.ReadFull(l.r, b[:])
+			l.truncated = n > 0
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
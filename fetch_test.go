@@ -0,0 +1,196 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLLimitReader(t *testing.T) {
+	for _, tc := range []struct {
+		s         string
+		max       int64
+		want      string
+		truncated bool
+	}{
+		{"0123456789", 20, "0123456789", false},
+		{"0123456789", 10, "0123456789", false},
+		{"0123456789", 9, "012345678", true},
+		{"0123456789", 1, "0", true},
+	} {
+		l := &htmlLimitReader{r: strings.NewReader(tc.s), remaining: tc.max}
+		b, err := ioutil.ReadAll(l)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.want, string(b))
+		assert.Equal(t, tc.truncated, l.truncated, "%v of %q", tc.max, tc.s)
+	}
+}
+
+func TestExtractMaxHTMLSize(t *testing.T) {
+	head := `<html><head><title>Title</title></head><body><article>
+<p>A long enough paragraph of the article, which is written as sentences to be extracted.</p>`
+	page := head + strings.Repeat("<p>More paragraphs of the long article.</p>", 1000) + `</article></body></html>`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	opt := benchOption()
+	c, err := NewExtractor(opt).Extract(context.Background(), ts.URL)
+	assert.Nil(t, err)
+	assert.NotContains(t, warningCodes(c.Warnings), WarningHTMLTruncated)
+
+	opt.MaxHTMLSize = int64(len(head) + 100)
+	for _, extract := range []func() (*Content, error){
+		func() (*Content, error) { return NewExtractor(opt).Extract(context.Background(), ts.URL) },
+		func() (*Content, error) { return Extract(ts.URL, opt) },
+	} {
+		c, err := extract()
+		assert.Nil(t, err)
+		assert.Contains(t, warningCodes(c.Warnings), WarningHTMLTruncated)
+		assert.Contains(t, c.Description, "A long enough paragraph")
+		assert.True(t, strings.Count(c.Description, "More paragraphs") <= 3)
+	}
+
+	opt.MaxHTMLSize = -1
+	_, err = Extract(ts.URL, opt)
+	assert.NotNil(t, err)
+}
+
+func TestExtractContentTypes(t *testing.T) {
+	article := `<html><head><title>Title</title></head><body><article>
+<p>A long enough paragraph of the article, which is written as sentences to be extracted.</p></article></body></html>`
+	png := pngBytes(800, 600)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/doc.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.4\n"))
+		case "/api":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"title": "Title"}`))
+		case "/download":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("%PDF-1.4\n"))
+		case "/photos/cat.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		case "/photo":
+			// no Content-Type, sniffed as PNG
+			w.Header()["Content-Type"] = nil
+			w.Write(png)
+		case "/untyped":
+			w.Header()["Content-Type"] = nil
+			w.Write([]byte(article))
+		case "/notes.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("A long enough paragraph of the notes, which is written as sentences to be extracted."))
+		}
+	}))
+	defer ts.Close()
+
+	opt := benchOption()
+	for _, p := range []string{"/doc.pdf", "/api", "/download"} {
+		for _, extract := range []func() (*Content, error){
+			func() (*Content, error) { return NewExtractor(opt).Extract(context.Background(), ts.URL+p) },
+			func() (*Content, error) { return Extract(ts.URL+p, opt) },
+		} {
+			_, err := extract()
+			assert.True(t, errors.Is(err, ErrUnsupportedContentType), p)
+			var ue *UnsupportedContentTypeError
+			if assert.True(t, errors.As(err, &ue), p) {
+				assert.Equal(t, ts.URL+p, ue.URL)
+			}
+			assert.Equal(t, CategoryUnsupported, Categorize(err))
+		}
+	}
+
+	for _, p := range []string{"/photos/cat.png", "/photo"} {
+		c, err := NewExtractor(opt).Extract(context.Background(), ts.URL+p)
+		assert.Nil(t, err, p)
+		if assert.Len(t, c.Images, 1, p) {
+			assert.Equal(t, ts.URL+p, c.Images[0].URL)
+			assert.Equal(t, uint32(800), c.Images[0].Size.Width)
+		}
+		if assert.NotNil(t, c.PrimaryImage, p) {
+			assert.Equal(t, ts.URL+p, c.PrimaryImage.URL)
+		}
+	}
+	c, err := Extract(ts.URL+"/photos/cat.png", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "cat.png", c.Title)
+
+	c, err = Extract(ts.URL+"/untyped", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", c.Title)
+
+	c, err = Extract(ts.URL+"/notes.txt", opt)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Description, "A long enough paragraph of the notes")
+}
+
+func TestExtractRedirects(t *testing.T) {
+	page := `<html><head><title>Title</title></head><body><article>
+<p>A long enough paragraph of the article, which is written as sentences to be extracted.</p></article></body></html>`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/short":
+			http.Redirect(w, r, "/tracked?id=1", http.StatusMovedPermanently)
+		case "/tracked":
+			http.Redirect(w, r, "/news/1?utm_source=feed&id=1#top", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.Write([]byte(page))
+		}
+	}))
+	defer ts.Close()
+
+	opt := benchOption()
+	for _, extract := range []func(string) (*Content, error){
+		func(u string) (*Content, error) { return NewExtractor(opt).Extract(context.Background(), u) },
+		func(u string) (*Content, error) { return Extract(u, opt) },
+	} {
+		c, err := extract(ts.URL + "/short")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{ts.URL + "/short", ts.URL + "/tracked?id=1"}, c.Redirects)
+		assert.Equal(t, ts.URL+"/news/1?id=1", c.NormalizedURL)
+
+		c, err = extract(ts.URL + "/news/2?utm_campaign=x")
+		assert.Nil(t, err)
+		assert.Nil(t, c.Redirects)
+		assert.Equal(t, ts.URL+"/news/2", c.NormalizedURL)
+
+		_, err = extract(ts.URL + "/loop")
+		var re *TooManyRedirectsError
+		if assert.True(t, errors.As(err, &re)) {
+			assert.Equal(t, ts.URL+"/loop", re.URL)
+		}
+	}
+
+	opt.MaxRedirects = 1
+	_, err := Extract(ts.URL+"/short", opt)
+	var re *TooManyRedirectsError
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ts.URL+"/short", re.URL)
+		assert.Equal(t, 1, re.MaxRedirects)
+	}
+	assert.Equal(t, CategoryRedirects, Categorize(err))
+	_, err = Extract(ts.URL+"/tracked", opt)
+	assert.Nil(t, err)
+
+	opt.MaxRedirects = 0
+	_, err = NewExtractor(opt).Extract(context.Background(), ts.URL+"/tracked")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ts.URL+"/tracked", re.URL)
+		assert.Equal(t, 0, re.MaxRedirects)
+	}
+	_, err = NewExtractor(opt).Extract(context.Background(), ts.URL+"/news/2")
+	assert.Nil(t, err)
+}
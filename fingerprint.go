@@ -0,0 +1,79 @@
+package readability
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Fingerprint identifies the article of a page, so that ReextractFromDocument can tell
+// whether the article has changed without scoring the page again.
+type Fingerprint struct {
+	// Selector is the path of the element of the article chosen by readability rules,
+	// like "html > body > div#main.content". See ExtractWithDebug for the form.
+	Selector string `json:"selector"`
+
+	// Hash is the hex-encoded SHA-256 of the text of the parent of the element,
+	// which contains the siblings joining the article too.
+	Hash string `json:"hash"`
+}
+
+// fingerprint returns the Fingerprint of n, an element of the original document.
+func fingerprint(n *html.Node) *Fingerprint {
+	return &Fingerprint{Selector: nodePath(n), Hash: fingerprintHash(n)}
+}
+
+// fingerprintHash returns Fingerprint.Hash of n.
+func fingerprintHash(n *html.Node) string {
+	if n.Parent != nil && n.Parent.Type == html.ElementNode {
+		n = n.Parent
+	}
+	tb := getTextBuilder()
+	defer putTextBuilder(tb)
+	tb.writeNode(n)
+	sum := sha256.Sum256(tb.buf)
+	return hex.EncodeToString(sum[:])
+}
+
+// cloneDocumentWithOrigins returns a copy of doc like goquery.CloneDocument,
+// and the map from the elements of the copy to their originals in doc.
+func cloneDocumentWithOrigins(doc *goquery.Document) (*goquery.Document, map[*html.Node]*html.Node) {
+	origins := map[*html.Node]*html.Node{}
+	var clone func(n *html.Node) *html.Node
+	clone = func(n *html.Node) *html.Node {
+		c := &html.Node{Type: n.Type, DataAtom: n.DataAtom, Data: n.Data, Namespace: n.Namespace,
+			Attr: make([]html.Attribute, len(n.Attr))}
+		copy(c.Attr, n.Attr)
+		if n.Type == html.ElementNode {
+			origins[c] = n
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			c.AppendChild(clone(child))
+		}
+		return c
+	}
+	return goquery.NewDocumentFromNode(clone(doc.Get(0))), origins
+}
+
+// ReextractFromDocument re-extracts doc, a newer version of the page of prev, which is
+// a Content previously returned with Option.Fingerprint set.
+// If the element of prev.Fingerprint still matches with the same hash, the article hasn't
+// changed and a copy of prev is returned with unchanged true, skipping the extraction.
+// Otherwise doc is extracted by ExtractFromDocument with Option.Fingerprint set.
+func ReextractFromDocument(doc *goquery.Document, reqURL string, prev *Content, opt *Option) (c *Content, unchanged bool, err error) {
+	if err := opt.Validate(); err != nil {
+		return nil, false, err
+	}
+	if prev != nil && prev.Fingerprint != nil {
+		if s := doc.Find(prev.Fingerprint.Selector); s.Length() == 1 && fingerprintHash(s.Get(0)) == prev.Fingerprint.Hash {
+			c := *prev
+			return &c, true, nil
+		}
+	}
+	opt = copyOption(opt)
+	opt.Fingerprint = true
+	c, err = extractFromDocument(doc, reqURL, opt)
+	return c, false, err
+}
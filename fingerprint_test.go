@@ -0,0 +1,60 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func fingerprintPage(article, sidebar string) string {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	return `<html><head><title>Title</title><script>var t = "` + sidebar + `";</script></head><body>` +
+		`<div class="sidebar">` + sidebar + `</div><div id="main"><div class="article"><p>` + article + ` ` + p + `</p><p>` + p + `</p></div></div></body></html>`
+}
+
+func TestFingerprint(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(fingerprintPage("First", "Ad 1")))
+	opt := benchOption()
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Nil(t, c.Fingerprint)
+
+	opt.Fingerprint = true
+	c, err = ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	if assert.NotNil(t, c.Fingerprint) {
+		assert.Equal(t, "html > body > div#main > div.article", c.Fingerprint.Selector)
+		assert.Equal(t, 64, len(c.Fingerprint.Hash))
+		assert.Equal(t, 1, doc.Find(c.Fingerprint.Selector).Length())
+	}
+}
+
+func TestReextractFromDocument(t *testing.T) {
+	opt := benchOption()
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(fingerprintPage("First", "Ad 1")))
+	prev, unchanged, err := ReextractFromDocument(doc, "http://example.com", nil, opt)
+	assert.Nil(t, err)
+	assert.False(t, unchanged)
+	assert.NotNil(t, prev.Fingerprint)
+	assert.False(t, opt.Fingerprint)
+
+	// changes outside the article don't matter
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(fingerprintPage("First", "Ad 2")))
+	c, unchanged, err := ReextractFromDocument(doc, "http://example.com", prev, opt)
+	assert.Nil(t, err)
+	assert.True(t, unchanged)
+	assert.Equal(t, prev, c)
+	assert.False(t, prev == c)
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(fingerprintPage("Updated", "Ad 2")))
+	c, unchanged, err = ReextractFromDocument(doc, "http://example.com", prev, opt)
+	assert.Nil(t, err)
+	assert.False(t, unchanged)
+	assert.True(t, strings.HasPrefix(c.Description, "Updated"), c.Description)
+	assert.NotEqual(t, prev.Fingerprint.Hash, c.Fingerprint.Hash)
+
+	_, _, err = ReextractFromDocument(doc, "http://example.com", prev, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,71 @@
+package readability
+
+import "bytes"
+
+// gifHeaderLength is the maximum number of leading bytes of a GIF image searched for frames.
+// The looping extension, which marks animations, is placed before the first frame.
+const gifHeaderLength = 16 * 1024
+
+func isGIF(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("GIF87a")) || bytes.HasPrefix(b, []byte("GIF89a"))
+}
+
+// isAnimatedGIF returns true if the GIF image starting with b has the NETSCAPE2.0 (or ANIMEXTS1.0)
+// looping extension or more than one frame in b.
+func isAnimatedGIF(b []byte) bool {
+	looping, frames := gifFrames(b)
+	return looping || frames > 1
+}
+
+// gifFrames returns whether the GIF image starting with b has a looping extension,
+// and the number of frames (image descriptors) found in b.
+// The counting stops at the end of b, so frames may be less than the actual number.
+func gifFrames(b []byte) (looping bool, frames int) {
+	// header and logical screen descriptor
+	if !isGIF(b) || len(b) < 13 {
+		return false, 0
+	}
+	i := 13
+	if b[10]&0x80 != 0 {
+		i += 3 << (b[10]&0x07 + 1)
+	}
+	for i < len(b) {
+		switch b[i] {
+		case 0x21: // extension
+			if i+2 > len(b) {
+				return looping, frames
+			}
+			if b[i+1] == 0xff && i+14 <= len(b) {
+				app := string(b[i+3 : i+14])
+				looping = looping || app == "NETSCAPE2.0" || app == "ANIMEXTS1.0"
+			}
+			i = skipGIFSubBlocks(b, i+2)
+		case 0x2c: // image descriptor
+			frames++
+			if frames > 1 {
+				return looping, frames
+			}
+			if i+10 > len(b) {
+				return looping, frames
+			}
+			flags := b[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << (flags&0x07 + 1)
+			}
+			// LZW minimum code size, then image data
+			i = skipGIFSubBlocks(b, i+1)
+		default: // trailer or broken data
+			return looping, frames
+		}
+	}
+	return looping, frames
+}
+
+// skipGIFSubBlocks returns the index after the sub-blocks starting at i and their terminator.
+func skipGIFSubBlocks(b []byte, i int) int {
+	for i < len(b) && b[i] != 0 {
+		i += int(b[i]) + 1
+	}
+	return i + 1
+}
@@ -0,0 +1,53 @@
+package readability
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gifBytes returns a GIF image of w x h (up to 255) with the given number of 1-byte frames.
+func gifBytes(w, h byte, frames int, looping bool) []byte {
+	b := []byte("GIF89a")
+	b = append(b, w, 0, h, 0, 0x80, 0, 0) // global color table of 2 colors
+	b = append(b, 0, 0, 0, 0xff, 0xff, 0xff)
+	if looping {
+		b = append(b, 0x21, 0xff, 11)
+		b = append(b, "NETSCAPE2.0"...)
+		b = append(b, 3, 1, 0, 0, 0)
+	}
+	for i := 0; i < frames; i++ {
+		b = append(b, 0x21, 0xf9, 4, 0, 10, 0, 0, 0) // graphic control extension
+		b = append(b, 0x2c, 0, 0, 0, 0, w, 0, h, 0, 0)
+		b = append(b, 2, 2, 0x44, 0x01, 0)
+	}
+	return append(b, 0x3b)
+}
+
+func TestIsAnimatedGIF(t *testing.T) {
+	assert.False(t, isAnimatedGIF(gifBytes(10, 10, 1, false)))
+	assert.True(t, isAnimatedGIF(gifBytes(10, 10, 2, false)))
+	assert.True(t, isAnimatedGIF(gifBytes(10, 10, 1, true)))
+	assert.False(t, isAnimatedGIF(pngBytes(10, 10)))
+
+	looping, frames := gifFrames(gifBytes(10, 10, 3, false))
+	assert.False(t, looping)
+	assert.Equal(t, 2, frames)
+}
+
+func TestDetectImageAnimated(t *testing.T) {
+	info, err := detectImage(bytes.NewReader(gifBytes(120, 90, 3, true)))
+	assert.Nil(t, err)
+	assert.True(t, info.animated)
+	assert.Equal(t, uint32(120), info.size.Width)
+	assert.Equal(t, uint32(90), info.size.Height)
+
+	info, err = detectImage(bytes.NewReader(gifBytes(120, 90, 1, false)))
+	assert.Nil(t, err)
+	assert.False(t, info.animated)
+
+	info, err = detectImage(bytes.NewReader(webpBytes("VP8X", []byte{0x02, 0, 0, 0, 0x7f, 0x07, 0x00, 0x37, 0x04, 0x00})))
+	assert.Nil(t, err)
+	assert.True(t, info.animated)
+}
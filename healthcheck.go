@@ -0,0 +1,181 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Checks of Extractor.HealthCheck.
+const (
+	// HealthCheckOption validates Extractor.Option.
+	HealthCheckOption = "option"
+
+	// HealthCheckDNS resolves the hosts of the proxies and the mirrors used by the Extractor.
+	HealthCheckDNS = "dns"
+
+	// HealthCheckCanary extracts an embedded page. See Extractor.HealthCheckCanary.
+	HealthCheckCanary = "canary"
+)
+
+// HealthCheckError is a failure of a check of Extractor.HealthCheck.
+type HealthCheckError struct {
+	// Check is the failed check, such as HealthCheckDNS.
+	Check string
+	Err   error
+}
+
+func (e *HealthCheckError) Error() string {
+	return fmt.Sprintf("health check %v failed: %v", e.Check, e.Err)
+}
+
+func (e *HealthCheckError) Unwrap() error {
+	return e.Err
+}
+
+// healthCheckURL is the page URL passed to proxy functions and mirror resolvers
+// to find the hosts they use.
+const healthCheckURL = "https://example.com/"
+
+// lookupHost resolves host names. Tests replace it and restore defaultLookupHost.
+var (
+	defaultLookupHost = net.DefaultResolver.LookupHost
+	lookupHost        = defaultLookupHost
+)
+
+// canaryPage is extracted by the canary check, whose title and description must be canaryTitle and
+// contain canarySentence.
+const (
+	canaryTitle    = "Canary Article"
+	canarySentence = "The canary paragraph is long enough to be the description of this page."
+	canaryPage     = `<html><head><title>` + canaryTitle + `</title></head><body>
+<nav class="menu"><a href="/">Home</a> <a href="/about">About</a></nav>
+<div id="content" class="article">
+<p>` + canarySentence + ` It is kept by the readability rules, while the navigation and the comments are removed.</p>
+<p>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.</p>
+<p>Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat.</p>
+</div>
+<div class="comments"><p>First comment.</p></div>
+</body></html>`
+)
+
+// HealthCheck checks that the Extractor is ready to extract pages, for readiness probes
+// of services embedding it: Option is validated, the rules are warmed up,
+// the hosts of the proxies of Client and Option.ImageClient and of the mirrors of
+// Option.MirrorResolver are resolved, and if HealthCheckCanary is set, an embedded page is extracted.
+//
+// Every check is run, and the failures are returned as *HealthCheckError joined by errors.Join.
+func (e *Extractor) HealthCheck(ctx context.Context) error {
+	if err := e.Option.Validate(); err != nil {
+		return &HealthCheckError{Check: HealthCheckOption, Err: err}
+	}
+	warmUpPatterns()
+
+	var errs []error
+	for _, host := range e.healthCheckHosts() {
+		if _, err := lookupHost(ctx, host); err != nil {
+			errs = append(errs, &HealthCheckError{Check: HealthCheckDNS, Err: err})
+		}
+	}
+	if e.HealthCheckCanary {
+		if err := e.canary(); err != nil {
+			errs = append(errs, &HealthCheckError{Check: HealthCheckCanary, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// healthCheckHosts returns the deduplicated host names of the proxies and the mirrors, except IP addresses.
+func (e *Extractor) healthCheckHosts() []string {
+	var urls []string
+	for _, c := range []*http.Client{e.Client, imageClient(e.Option)} {
+		if u := proxyURL(c); u != nil {
+			urls = append(urls, u.String())
+		}
+	}
+	if e.Option.MirrorResolver != nil {
+		for _, m := range e.Option.MirrorResolver.Mirrors(healthCheckURL) {
+			urls = append(urls, m.URL)
+		}
+	}
+
+	var hosts []string
+	seen := map[string]bool{}
+	for _, s := range urls {
+		u, err := url.Parse(s)
+		if err != nil {
+			continue
+		}
+		host := u.Hostname()
+		if host == "" || seen[host] || net.ParseIP(host) != nil {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// proxyURL returns the proxy used by c for healthCheckURL, or nil if it is not used or unknown.
+func proxyURL(c *http.Client) *url.URL {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	rt := c.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok || t.Proxy == nil {
+		return nil
+	}
+	req, _ := http.NewRequest(http.MethodGet, healthCheckURL, nil)
+	u, err := t.Proxy(req)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// warmUpPatterns runs every rule once, so that the first extraction doesn't pay for
+// the lazily allocated matchers.
+func warmUpPatterns() {
+	const sample = "article content comment sidebar <br><br> <font> \n http://www.youtube.com. "
+	for _, re := range patterns.all() {
+		re.MatchString(sample)
+	}
+	for _, pr := range profiles {
+		for _, re := range pr.patterns.all() {
+			re.MatchString(sample)
+		}
+	}
+}
+
+// canary extracts canaryPage with Option and checks the result.
+// Images are not requested.
+func (e *Extractor) canary() error {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(canaryPage))
+	if err != nil {
+		return err
+	}
+	opt := copyOption(e.Option)
+	opt.ShareableImagesOnly = true
+	opt.Metrics = nil
+	c, err := extractFromDocument(doc, healthCheckURL, opt)
+	if err != nil {
+		return err
+	}
+	if c.Title != canaryTitle {
+		return fmt.Errorf("unexpected title %q", c.Title)
+	}
+	if !strings.Contains(c.Description, canarySentence) {
+		return fmt.Errorf("unexpected description %q", c.Description)
+	}
+	return nil
+}
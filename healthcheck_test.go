@@ -0,0 +1,54 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	resolved := []string{}
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		resolved = append(resolved, host)
+		if host == "cache.invalid" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"192.0.2.1"}, nil
+	}
+	defer func() { lookupHost = defaultLookupHost }()
+
+	e := NewExtractor(nil)
+	e.HealthCheckCanary = true
+	e.Client = &http.Client{Transport: &http.Transport{
+		Proxy: http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"}),
+	}}
+	e.Option.ImageClient = &http.Client{Transport: &http.Transport{}}
+	assert.Nil(t, e.HealthCheck(context.Background()))
+	assert.Equal(t, []string{"proxy.example.com"}, resolved)
+
+	resolved = nil
+	e.Option.MirrorResolver = PrefixMirror("cache", "https://cache.invalid/")
+	err := e.HealthCheck(context.Background())
+	var hce *HealthCheckError
+	assert.True(t, errors.As(err, &hce))
+	assert.Equal(t, HealthCheckDNS, hce.Check)
+	assert.Equal(t, []string{"proxy.example.com", "cache.invalid"}, resolved)
+
+	e.Option.DescriptionExtractionTimeout = 0
+	err = e.HealthCheck(context.Background())
+	assert.True(t, errors.As(err, &hce))
+	assert.Equal(t, HealthCheckOption, hce.Check)
+}
+
+func TestHealthCheckCanary(t *testing.T) {
+	e := NewExtractor(nil)
+	assert.Nil(t, e.canary())
+
+	e.Option.MinTextLength = 10000
+	e.Option.RetryLength = 0
+	assert.NotNil(t, e.canary())
+}
@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// heroSiblingWindow is the number of preceding and following siblings searched for a hero image.
+	heroSiblingWindow = 2
+
+	// heroAncestorDepth is the number of ancestors of <h1> whose siblings are searched for a hero image.
+	heroAncestorDepth = 3
+)
+
+// heroImageURL returns the absolute URL of the image right before or after the first <h1>,
+// searching siblings of <h1> and its ancestors, which is common in magazine layouts
+// where the hero image is placed outside the article body.
+func heroImageURL(doc *goquery.Document, reqURL string) string {
+	node := doc.Find("h1").First()
+	for depth := 0; depth <= heroAncestorDepth && node.Length() > 0; depth++ {
+		if goquery.NodeName(node) == "body" {
+			break
+		}
+		for _, sibs := range []*goquery.Selection{node.PrevAll(), node.NextAll()} {
+			for i := 0; i < heroSiblingWindow && i < sibs.Length(); i++ {
+				if src := firstImgSrc(sibs.Eq(i)); src != "" {
+					if u, err := absPath(src, reqURL); err == nil {
+						return u
+					}
+				}
+			}
+		}
+		node = node.Parent()
+	}
+	return ""
+}
+
+func firstImgSrc(s *goquery.Selection) string {
+	img := s
+	if goquery.NodeName(s) != "img" {
+		img = s.Find("img").First()
+	}
+	if img.Length() == 0 {
+		return ""
+	}
+	src, _ := imgSrc(img)
+	return src
+}
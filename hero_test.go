@@ -0,0 +1,55 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeroImageURL(t *testing.T) {
+	for _, tc := range []struct {
+		html string
+		hero string
+	}{
+		{`<body><div class="header"><figure><img src="/hero.jpg"></figure><h1>Title</h1></div></body>`, "http://example.com/hero.jpg"},
+		{`<body><header><div><h1>Title</h1><p>By someone</p></div></header><div class="lead"><img data-original="/lazy.jpg"></div></body>`, "http://example.com/lazy.jpg"},
+		{`<body><h1>Title</h1><p>a</p><p>b</p><p><img src="/far.jpg"></p></body>`, ""},
+		{`<body><img src="/a.jpg"><p>no title</p></body>`, ""},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		assert.Equal(t, tc.hero, heroImageURL(doc, "http://example.com/news"), tc.html)
+	}
+}
+
+func TestImagesPreferHero(t *testing.T) {
+	small, large := pngBytes(300, 200), pngBytes(800, 600)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hero.png" {
+			w.Write(large)
+			return
+		}
+		w.Write(small)
+	}))
+	defer ts.Close()
+
+	html := `<body><div><img src="/hero.png"><h1>Title</h1></div>
+<article><img src="/1.png"><img src="/2.png"><img src="/3.png"></article></body>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	opt.MaxImageCount = 2
+	hero := heroImageURL(doc, ts.URL)
+	imgs := images(doc, ts.URL, opt, hero)
+	assert.Equal(t, 2, len(imgs))
+	assert.Equal(t, ts.URL+"/hero.png", imgs[0].URL)
+
+	// the hero image is probed even if it is beyond CheckImageLoopCount
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<body><article><img src="/1.png"><img src="/2.png"></article></body>`))
+	opt.CheckImageLoopCount = 1
+	imgs = images(doc, ts.URL, opt, ts.URL+"/hero.png")
+	assert.Equal(t, 2, len(imgs))
+	assert.Equal(t, ts.URL+"/hero.png", imgs[0].URL)
+}
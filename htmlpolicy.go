@@ -0,0 +1,261 @@
+package readability
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// URLRewrite is how HTMLPolicy rewrites URLs of links and images.
+type URLRewrite string
+
+// URL rewrites of HTMLPolicy.URLs.
+const (
+	// URLRewriteAbsolute resolves URLs against the page URL, or its <base href> if exists,
+	// in the same way as the URLs of Content.Images. Fragments like "#note" are kept as is.
+	URLRewriteAbsolute URLRewrite = ""
+
+	// URLRewriteRelative resolves URLs like URLRewriteAbsolute, then makes URLs of the same host
+	// root-relative, like "/path?query", for pages served on the same host.
+	URLRewriteRelative URLRewrite = "relative"
+
+	// URLRewriteNone keeps URLs as is.
+	URLRewriteNone URLRewrite = "none"
+)
+
+// HTMLPolicy is the whitelist of the HTML description, so that it can be embedded into web pages.
+// If Option.HTMLPolicy is set and Option.DescriptionAsPlainText is not, the description keeps
+// the elements and attributes allowed by the policy, instead of only <div> and <p> without attributes.
+//
+// Elements not allowed are replaced with their content, except elements whose content is not text
+// (like <iframe> and <svg>) which are removed with it. <script>, <style>, <object> and <embed>
+// are always removed, and so are event handler attributes (like onclick) and URLs with schemes
+// other than http, https, mailto and tel (data is allowed only for images).
+type HTMLPolicy struct {
+	// AllowedTags contains the lowercased names of the elements kept.
+	AllowedTags []string `json:"allowedTags"`
+
+	// AllowedAttributes maps element names to the lowercased names of their attributes kept.
+	// The attributes of "*" are kept on all elements.
+	AllowedAttributes map[string][]string `json:"allowedAttributes"`
+
+	// URLs is how URLs of href, src and srcset attributes are rewritten.
+	URLs URLRewrite `json:"urls"`
+
+	// LinksInNewTab adds target="_blank" and rel="noopener noreferrer" to links.
+	LinksInNewTab bool `json:"linksInNewTab"`
+}
+
+// NewHTMLPolicy returns a policy keeping the elements of text formatting, lists, tables,
+// links and images with their essential attributes.
+func NewHTMLPolicy() *HTMLPolicy {
+	return &HTMLPolicy{
+		AllowedTags: []string{
+			"div", "p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "pre", "code",
+			"em", "strong", "b", "i", "u", "s", "sub", "sup", "small", "mark", "q", "cite", "abbr",
+			"ul", "ol", "li", "dl", "dt", "dd", "a", "img", "figure", "figcaption",
+			"table", "caption", "thead", "tbody", "tfoot", "tr", "th", "td",
+		},
+		AllowedAttributes: map[string][]string{
+			"*":   {"lang", "dir"},
+			"a":   {"href", "title"},
+			"img": {"src", "srcset", "alt", "title", "width", "height"},
+			"ol":  {"start"},
+			"th":  {"colspan", "rowspan"},
+			"td":  {"colspan", "rowspan"},
+		},
+	}
+}
+
+var (
+	// unsafeTags are always removed with their content.
+	unsafeTags = map[string]bool{
+		"script": true, "style": true, "template": true, "object": true, "embed": true, "applet": true,
+		"frame": true, "frameset": true, "base": true, "link": true, "meta": true, "head": true, "title": true,
+	}
+
+	// opaqueTags are removed with their content unless allowed, since it is not text.
+	opaqueTags = map[string]bool{
+		"iframe": true, "form": true, "input": true, "button": true, "select": true, "textarea": true,
+		"svg": true, "math": true, "noscript": true, "audio": true, "video": true, "canvas": true,
+		"picture": true, "source": true, "track": true, "map": true,
+	}
+
+	// urlAttributes are attributes whose values are URLs.
+	urlAttributes = map[string]bool{
+		"href": true, "src": true, "cite": true, "action": true, "formaction": true, "poster": true,
+		"background": true, "longdesc": true, "xlink:href": true, "data": true,
+	}
+
+	// safeSchemes are the schemes of URLs kept.
+	safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+)
+
+// htmlWhitelist is HTMLPolicy in maps.
+type htmlWhitelist struct {
+	policy     *HTMLPolicy
+	tags       map[string]bool
+	attributes map[string]map[string]bool
+
+	// base is the URL relative URLs are resolved against, and baseHost is its host.
+	base, baseHost string
+}
+
+func newHTMLWhitelist(p *HTMLPolicy, reqURL string) *htmlWhitelist {
+	w := &htmlWhitelist{policy: p, tags: map[string]bool{}, attributes: map[string]map[string]bool{}}
+	for _, t := range p.AllowedTags {
+		w.tags[strings.ToLower(t)] = true
+	}
+	for t, attrs := range p.AllowedAttributes {
+		m := map[string]bool{}
+		for _, a := range attrs {
+			m[strings.ToLower(a)] = true
+		}
+		w.attributes[strings.ToLower(t)] = m
+	}
+	if isValidURLStr(reqURL) {
+		w.base = reqURL
+		if u, err := url.Parse(reqURL); err == nil {
+			w.baseHost = u.Host
+		}
+	}
+	return w
+}
+
+// sanitizeHTML applies p to the children of n, the root of the article.
+func sanitizeHTML(n *html.Node, p *HTMLPolicy, reqURL string) {
+	w := newHTMLWhitelist(p, reqURL)
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		w.sanitize(c)
+		c = next
+	}
+}
+
+// sanitize applies w to n and its descendants.
+func (w *htmlWhitelist) sanitize(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		return
+	case html.ElementNode:
+	default:
+		// comments and doctypes
+		n.Parent.RemoveChild(n)
+		return
+	}
+
+	tag := n.Data
+	if unsafeTags[tag] || opaqueTags[tag] && !w.tags[tag] {
+		n.Parent.RemoveChild(n)
+		return
+	}
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		w.sanitize(c)
+		c = next
+	}
+	if !w.tags[tag] {
+		w.unwrap(n)
+		return
+	}
+
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a, ok := w.attribute(tag, a); ok {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+	if tag == "a" && w.policy.LinksInNewTab {
+		n.Attr = append(n.Attr, html.Attribute{Key: "target", Val: "_blank"},
+			html.Attribute{Key: "rel", Val: "noopener noreferrer"})
+	}
+}
+
+// unwrap replaces n with its children, separated by spaces from the siblings
+// if n is an element like <li> or <br>.
+func (w *htmlWhitelist) unwrap(n *html.Node) {
+	parent := n.Parent
+	if spaceyTags[n.Data] {
+		parent.InsertBefore(&html.Node{Type: html.TextNode, Data: " "}, n)
+	}
+	for c := n.FirstChild; c != nil; c = n.FirstChild {
+		n.RemoveChild(c)
+		parent.InsertBefore(c, n)
+	}
+	if spaceyTags[n.Data] {
+		parent.InsertBefore(&html.Node{Type: html.TextNode, Data: " "}, n)
+	}
+	parent.RemoveChild(n)
+}
+
+// attribute returns a of the element tag with its URLs rewritten, and whether it is kept.
+func (w *htmlWhitelist) attribute(tag string, a html.Attribute) (html.Attribute, bool) {
+	key := strings.ToLower(a.Key)
+	if a.Namespace != "" {
+		key = a.Namespace + ":" + key
+	}
+	if strings.HasPrefix(key, "on") || !w.attributes[tag][key] && !w.attributes["*"][key] {
+		return a, false
+	}
+	switch {
+	case key == "srcset":
+		var cands []string
+		for _, c := range strings.Split(a.Val, ",") {
+			f := strings.Fields(c)
+			if len(f) == 0 {
+				continue
+			}
+			u, ok := w.url(f[0], false)
+			if !ok {
+				return a, false
+			}
+			cands = append(cands, strings.Join(append([]string{u}, f[1:]...), " "))
+		}
+		a.Val = strings.Join(cands, ", ")
+	case urlAttributes[key]:
+		u, ok := w.url(a.Val, tag == "img" && key == "src")
+		if !ok {
+			return a, false
+		}
+		a.Val = u
+	}
+	return a, true
+}
+
+// url returns s rewritten by w.policy.URLs, and whether it has a safe scheme.
+// Data URIs are safe only if image is true and they are images.
+func (w *htmlWhitelist) url(s string, image bool) (string, bool) {
+	s = strings.TrimSpace(s)
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	switch {
+	case scheme == "data":
+		return s, image && strings.HasPrefix(strings.ToLower(u.Opaque), "image/")
+	case scheme != "" && !safeSchemes[scheme]:
+		return "", false
+	case w.base == "" || w.policy.URLs == URLRewriteNone || s == "" || strings.HasPrefix(s, "#"):
+		return s, true
+	}
+
+	abs, err := absPath(s, w.base)
+	if err != nil {
+		return s, true
+	}
+	if w.policy.URLs != URLRewriteRelative {
+		return abs, true
+	}
+	u, err = url.Parse(abs)
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" || !strings.EqualFold(u.Host, w.baseHost) {
+		return abs, true
+	}
+	u.Scheme, u.Host, u.User = "", "", nil
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String(), true
+}
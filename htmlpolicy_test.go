@@ -0,0 +1,66 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	for _, tc := range []struct {
+		policy     *HTMLPolicy
+		html, want string
+	}{
+		{NewHTMLPolicy(),
+			`<p onclick="x()" class="c">Hi <b>bold</b> <span style="color:red">span</span></p>`,
+			`<p>Hi <b>bold</b> span</p>`},
+		{NewHTMLPolicy(),
+			`<p><script>alert(1)</script><style>p{}</style><iframe src="https://example.com"></iframe><!-- c -->Text</p>`,
+			`<p>Text</p>`},
+		{NewHTMLPolicy(),
+			`<a href="javascript:alert(1)">a</a><a href=" JaVaScRiPt:alert(1)">b</a><a href="/path?q=1#f" title="t">c</a><a href="mailto:a@example.com">d</a><a href="#note">e</a><a href="//cdn.example.com/f">f</a>`,
+			`<a>a</a><a>b</a><a href="https://example.com/path?q=1#f" title="t">c</a><a href="mailto:a@example.com">d</a><a href="#note">e</a><a href="https://cdn.example.com/f">f</a>`},
+		{NewHTMLPolicy(),
+			`<img src="data:image/png;base64,AAAA" onerror="x()"><img src="data:text/html;base64,AAAA"><img src="a.jpg" srcset="a.jpg 1x, /b.jpg 2x" alt="A">`,
+			`<img src="data:image/png;base64,AAAA"/><img/><img src="https://example.com/news/a.jpg" srcset="https://example.com/news/a.jpg 1x, https://example.com/b.jpg 2x" alt="A"/>`},
+		{NewHTMLPolicy(),
+			`<ul><li>one</li><li>two</li></ul><custom>text</custom>`,
+			`<ul><li>one</li><li>two</li></ul>text`},
+		{&HTMLPolicy{AllowedTags: []string{"p"}},
+			`<p>one<ul><li>two</li><li>three</li></ul></p>`,
+			`<p>one</p>  two  three  <p></p>`},
+		{&HTMLPolicy{AllowedTags: []string{"a"}, AllowedAttributes: map[string][]string{"a": {"href"}}, URLs: URLRewriteRelative, LinksInNewTab: true},
+			`<a href="https://example.com/a">a</a><a href="b">b</a><a href="https://other.com/c">c</a>`,
+			`<a href="/a" target="_blank" rel="noopener noreferrer">a</a><a href="/news/b" target="_blank" rel="noopener noreferrer">b</a><a href="https://other.com/c" target="_blank" rel="noopener noreferrer">c</a>`},
+		{&HTMLPolicy{AllowedTags: []string{"a"}, AllowedAttributes: map[string][]string{"a": {"href", "onclick"}}, URLs: URLRewriteNone},
+			`<a href="b" onclick="x()">b</a>`,
+			`<a href="b">b</a>`},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		sanitizeHTML(doc.Get(0), tc.policy, "https://example.com/news/1")
+		out, _ := doc.Html()
+		assert.Equal(t, tc.want, out, tc.html)
+	}
+}
+
+func TestExtractWithHTMLPolicy(t *testing.T) {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><body><div class="article"><p>First <a href="/about" onmouseover="x()">link</a> ` + p +
+		`<img src="/a.jpg" onerror="x()"></p><p>Second ` + p + `</p></div></body></html>`
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	opt := benchOption()
+	opt.DescriptionAsPlainText = false
+	opt.HTMLPolicy = NewHTMLPolicy()
+	c, err := ExtractFromDocument(doc, "https://example.com/news/1", opt)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Description, `<p>First <a href="https://example.com/about">link</a> lorem`)
+	assert.Contains(t, c.Description, `<img src="https://example.com/a.jpg"/>`)
+	assert.NotContains(t, c.Description, "onerror")
+
+	opt.HTMLPolicy = &HTMLPolicy{URLs: "unknown"}
+	_, err = ExtractFromDocument(doc, "https://example.com/news/1", opt)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,282 @@
+package readability
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/philipjkim/fastimage"
+	"golang.org/x/net/html"
+)
+
+// ImageSource is a source where an image is found.
+type ImageSource string
+
+// Sources of images.
+const (
+	// ImageSourceOpenGraph is the og:image meta property.
+	ImageSourceOpenGraph ImageSource = "og"
+
+	// ImageSourceTwitter is the twitter:image meta property.
+	ImageSourceTwitter ImageSource = "twitter"
+
+	// ImageSourceImageSrc is <link rel="image_src">.
+	ImageSourceImageSrc ImageSource = "image_src"
+
+	// ImageSourceMetadata is the image of JSON-LD or microdata article.
+	ImageSourceMetadata ImageSource = "metadata"
+
+	// ImageSourceArticle is the src attribute of an <img> in the document.
+	ImageSourceArticle ImageSource = "article"
+
+	// ImageSourceLazyAttr is the data-original attribute of an <img> without src, set by lazy-loading scripts.
+	ImageSourceLazyAttr ImageSource = "lazy-attr"
+
+	// ImageSourceSrcset is the largest candidate of the srcset attribute of an <img> without src.
+	ImageSourceSrcset ImageSource = "srcset"
+
+	// ImageSourceNoscript is an <img> inside <noscript>, unless it is found by a lazy-loading attribute
+	// or its srcset.
+	ImageSourceNoscript ImageSource = "noscript"
+
+	// ImageSourcePoster is the poster attribute of a <video>.
+	ImageSourcePoster ImageSource = "poster"
+
+	// ImageSourceVideoEmbed is the thumbnail of a YouTube or Vimeo video embedded in the document.
+	ImageSourceVideoEmbed ImageSource = "video-embed"
+
+	// ImageSourceFavicon is the icon of the site (<link rel="apple-touch-icon">, <link rel="icon"> or /favicon.ico).
+	ImageSourceFavicon ImageSource = "favicon"
+)
+
+// ImageOrder is an order of Content.Images.
+type ImageOrder string
+
+// Orders of images.
+const (
+	// ImageOrderDocument orders images by their position in the document.
+	ImageOrderDocument ImageOrder = "document"
+
+	// ImageOrderArea orders images by their area (width x height), largest first.
+	// Ties are ordered by their position in the document.
+	// Every image request is waited for (up to Option.ImageRequestTimeout) to compare sizes.
+	ImageOrderArea ImageOrder = "area"
+
+	// ImageOrderScore orders images by their score, highest first: a weighted sum of their area
+	// relative to the largest image, their position in the document and the quality of their alt text.
+	// Ties are ordered by their position in the document.
+	// Every image request is waited for (up to Option.ImageProbingTimeout) to compare images.
+	ImageOrderScore ImageOrder = "score"
+)
+
+// Weights of the image score of ImageOrderScore. They sum to 1.
+const (
+	imageAreaWeight     = 0.5
+	imagePositionWeight = 0.3
+	imageAltWeight      = 0.2
+)
+
+// genericAltPattern matches alt texts which don't describe images.
+var genericAltPattern = regexp.MustCompile(`(?i)^(image|img|photo|picture|pic|logo|icon|avatar|banner|thumbnail|spacer|untitled)?[\s_-]*\d*$`)
+
+// altQuality returns how well alt describes an image, from 0 to 1: 0 for empty, generic texts
+// and file names, 0.5 for a word or two, and 1 for descriptive texts.
+func altQuality(alt string) float64 {
+	alt = strings.TrimSpace(alt)
+	switch {
+	case genericAltPattern.MatchString(alt), imageTypes[strings.ToLower(path.Ext(alt))] != "":
+		return 0
+	case len(strings.Fields(alt)) < 3:
+		return 0.5
+	}
+	return 1
+}
+
+// imageScore returns the score of an image of ImageOrderScore. index is its position in
+// the document among n candidates, and maxArea is the area of the largest of them.
+func imageScore(img Image, alt string, index, n int, maxArea uint64) float64 {
+	score := imageAltWeight * altQuality(alt)
+	if maxArea > 0 {
+		score += imageAreaWeight * float64(img.area()) / float64(maxArea)
+	}
+	if n > 0 {
+		score += imagePositionWeight * (1 - float64(index)/float64(n))
+	}
+	return score
+}
+
+// imgSrc returns the image URL of an <img> or <video> element and where it is found.
+// For an <img>, src is used unless it is empty, then data-original, then the largest srcset candidate.
+func imgSrc(s *goquery.Selection) (string, ImageSource) {
+	if goquery.NodeName(s) == "video" {
+		return s.AttrOr("poster", ""), ImageSourcePoster
+	}
+
+	src, hasSrc := s.Attr("src")
+	if strings.TrimSpace(src) != "" {
+		return src, ImageSourceArticle
+	}
+	if v, ok := s.Attr("data-original"); ok && !hasSrc {
+		return v, ImageSourceLazyAttr
+	}
+	for _, attr := range []string{"srcset", "data-srcset"} {
+		if v := largestSrcsetCandidate(s.AttrOr(attr, "")); v != "" {
+			return v, ImageSourceSrcset
+		}
+	}
+	return src, ImageSourceArticle
+}
+
+// largestSrcsetCandidate returns the URL of the candidate with the largest
+// width (w) or pixel density (x) descriptor in srcset.
+func largestSrcsetCandidate(srcset string) string {
+	best, bestSize := "", -1.0
+	for _, c := range strings.Split(srcset, ",") {
+		fields := strings.Fields(c)
+		if len(fields) == 0 {
+			continue
+		}
+		size := 1.0
+		if len(fields) > 1 {
+			d := fields[1]
+			if v, err := strconv.ParseFloat(d[:len(d)-1], 64); err == nil &&
+				(strings.HasSuffix(d, "w") || strings.HasSuffix(d, "x")) {
+				size = v
+			}
+		}
+		if size > bestSize {
+			best, bestSize = fields[0], size
+		}
+	}
+	return best
+}
+
+// figureCaption returns the text of the <figcaption> of the closest <figure> containing s, or "".
+func figureCaption(s *goquery.Selection) string {
+	figure := s.Closest("figure")
+	if figure.Length() == 0 {
+		return ""
+	}
+	return plainText(figure.ChildrenFiltered("figcaption").First())
+}
+
+// figureImage keeps only the src and alt attributes of s, an <img> of a <figure> kept in
+// the description, with the image URL found by imgSrc resolved against reqURL.
+// s is removed if it has no image URL.
+func figureImage(s *goquery.Selection, reqURL string) {
+	n := s.Get(0)
+	rawSrc, _ := imgSrc(s)
+	src, err := absPath(rawSrc, reqURL)
+	if err != nil || strings.TrimSpace(rawSrc) == "" || !isValidURLStr(src) {
+		s.Remove()
+		return
+	}
+	alt := s.AttrOr("alt", "")
+	n.Attr = []html.Attribute{{Key: "src", Val: src}}
+	if alt != "" {
+		n.Attr = append(n.Attr, html.Attribute{Key: "alt", Val: alt})
+	}
+}
+
+// eachNoscriptImage calls f for each <img> inside s, a <noscript>, until f returns false.
+// Lazy-loading pages put the real images there for clients without scripts. The content of
+// <noscript> is raw text when the document is parsed with scripting enabled, so it is parsed as HTML.
+func eachNoscriptImage(s *goquery.Selection, f func(*goquery.Selection) bool) {
+	if s.Children().Length() > 0 {
+		// parsed with scripting disabled, so the <img>s are elements of the document
+		return
+	}
+	text := s.Text()
+	if !strings.Contains(strings.ToLower(text), "<img") {
+		return
+	}
+	frag, err := goquery.NewDocumentFromReader(strings.NewReader(text))
+	if err != nil {
+		return
+	}
+	frag.Find("img").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		return f(img)
+	})
+}
+
+// srcsetSize returns the size of the candidate src of the srcset of s, which is width and height
+// (from the attributes) scaled by the width (w) or pixel density (x) descriptor of the candidate.
+// width and height are returned as is if they are unknown or src has no descriptor.
+func srcsetSize(s *goquery.Selection, src string, width, height int) (int, int) {
+	if width <= 0 || height <= 0 {
+		return width, height
+	}
+	for _, attr := range []string{"srcset", "data-srcset"} {
+		for _, c := range strings.Split(s.AttrOr(attr, ""), ",") {
+			fields := strings.Fields(c)
+			if len(fields) < 2 || fields[0] != src {
+				continue
+			}
+			d := fields[1]
+			v, err := strconv.ParseFloat(d[:len(d)-1], 64)
+			switch {
+			case err != nil || v <= 0:
+			case strings.HasSuffix(d, "w"):
+				return int(v), int(float64(height) * v / float64(width))
+			case strings.HasSuffix(d, "x"):
+				return int(float64(width) * v), int(float64(height) * v)
+			}
+			return width, height
+		}
+	}
+	return width, height
+}
+
+// openGraphImageSize returns the size of og:image declared by og:image:width and og:image:height.
+// The size is 0x0 if they are not declared.
+func openGraphImageSize(doc *goquery.Document) *fastimage.ImageSize {
+	w, _ := strconv.ParseUint(metaContent(doc, `meta[property="og:image:width"], meta[name="og:image:width"]`), 10, 32)
+	h, _ := strconv.ParseUint(metaContent(doc, `meta[property="og:image:height"], meta[name="og:image:height"]`), 10, 32)
+	return &fastimage.ImageSize{Width: uint32(w), Height: uint32(h)}
+}
+
+// shareableImageSelectors are elements for images the publisher designated for sharing,
+// with the attribute of the image URL and the source, in order of preference.
+var shareableImageSelectors = []struct {
+	sel, attr string
+	src       ImageSource
+}{
+	{`meta[property="og:image"], meta[name="og:image"]`, "content", ImageSourceOpenGraph},
+	{`meta[name="twitter:image"], meta[property="twitter:image"], meta[name="twitter:image:src"]`, "content", ImageSourceTwitter},
+	{`link[rel~="image_src"]`, "href", ImageSourceImageSrc},
+}
+
+// shareableImages returns up to max deduplicated images designated for sharing
+// by og:image, twitter:image and <link rel="image_src">, in that order.
+// Images in the document body are never returned.
+func shareableImages(doc *goquery.Document, reqURL string, max int) []Image {
+	imgs := []Image{}
+	seen := map[string]bool{}
+	for _, s := range shareableImageSelectors {
+		doc.Find(s.sel).EachWithBreak(func(i int, sel *goquery.Selection) bool {
+			if len(imgs) >= max {
+				return false
+			}
+			u, err := absPath(sel.AttrOr(s.attr, ""), reqURL)
+			if err != nil || seen[u] {
+				return true
+			}
+			seen[u] = true
+			imgs = append(imgs, Image{URL: u, Size: &fastimage.ImageSize{Width: 0, Height: 0}, Source: s.src})
+			return true
+		})
+	}
+	return imgs
+}
+
+// setShareableImages sets imgs as c.Images and the first of them as c.PrimaryImage.
+func (c *Content) setShareableImages(imgs []Image) {
+	c.Images = imgs
+	c.PrimaryImage = nil
+	if len(imgs) > 0 {
+		img := imgs[0]
+		c.PrimaryImage = &img
+	}
+}
@@ -0,0 +1,334 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImgSrc(t *testing.T) {
+	for _, tc := range []struct {
+		html   string
+		src    string
+		source ImageSource
+	}{
+		{`<img src="/a.jpg" data-original="/b.jpg">`, "/a.jpg", ImageSourceArticle},
+		{`<img data-original="/c.jpg">`, "/c.jpg", ImageSourceLazyAttr},
+		{`<img data-src="/d.jpg">`, "", ImageSourceArticle},
+		{`<img src="data:image/png;base64,iVBOR" srcset="/e.jpg 2x">`, "data:image/png;base64,iVBOR", ImageSourceArticle},
+		{`<img srcset="/s.jpg 320w, /l.jpg 1024w, /m.jpg 640w">`, "/l.jpg", ImageSourceSrcset},
+		{`<img src="" data-srcset="/1x.jpg, /2x.jpg 2x">`, "/2x.jpg", ImageSourceSrcset},
+		{`<video poster="/poster.jpg"></video>`, "/poster.jpg", ImageSourcePoster},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		src, source := imgSrc(doc.Find("img, video").First())
+		assert.Equal(t, tc.src, src, tc.html)
+		assert.Equal(t, tc.source, source, tc.html)
+	}
+}
+
+func TestImagesSrcsetAndPoster(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngBytes(800, 600))
+	}))
+	defer ts.Close()
+
+	html := `<body><img srcset="/small.png 320w, /large.png 1024w"><video poster="/poster.png"></video></body>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, ts.URL, NewOption(), "")
+	if assert.Equal(t, 2, len(imgs)) {
+		assert.Equal(t, Image{URL: ts.URL + "/large.png", Size: imgs[0].Size, Source: ImageSourceSrcset}, imgs[0])
+		assert.Equal(t, ImageSourcePoster, imgs[1].Source)
+		assert.Equal(t, ts.URL+"/poster.png", imgs[1].URL)
+	}
+}
+
+func TestShareableImagesOnly(t *testing.T) {
+	html := `<html><head>
+<meta property="og:image" content="/og.jpg">
+<meta name="twitter:image" content="http://example.com/og.jpg">
+<meta name="twitter:image:src" content="/tw.jpg">
+<link rel="image_src" href="/share.jpg">
+</head><body><article><img src="/photo.jpg" width="640" height="480"><p>Body</p></article></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	opt.ShareableImagesOnly = true
+	c, err := ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(c.Images))
+	assert.Equal(t, "http://example.com/og.jpg", c.Images[0].URL)
+	assert.Equal(t, ImageSourceOpenGraph, c.Images[0].Source)
+	assert.Equal(t, "http://example.com/tw.jpg", c.Images[1].URL)
+	assert.Equal(t, ImageSourceTwitter, c.Images[1].Source)
+	assert.Equal(t, "http://example.com/share.jpg", c.Images[2].URL)
+	assert.Equal(t, ImageSourceImageSrc, c.Images[2].Source)
+	assert.Equal(t, "http://example.com/og.jpg", c.PrimaryImage.URL)
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<body><article><img src="/photo.jpg" width="640" height="480"></article></body>`))
+	c, _ = ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Empty(t, c.Images)
+	assert.Nil(t, c.PrimaryImage)
+}
+
+func TestImagesOrder(t *testing.T) {
+	small, large := pngBytes(300, 200), pngBytes(800, 600)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1.png":
+			// the first image in the document finishes last
+			time.Sleep(100 * time.Millisecond)
+			w.Write(small)
+		case "/2.png":
+			w.Write(large)
+		default:
+			w.Write(small)
+		}
+	}))
+	defer ts.Close()
+
+	html := `<body><img src="/1.png"><img src="/2.png"><img src="/3.png"><img src="/4.png"></body>`
+	opt := NewOption()
+	opt.MaxImageCount = 2
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, ts.URL, opt, "")
+	assert.Equal(t, 2, len(imgs))
+	assert.Equal(t, ts.URL+"/1.png", imgs[0].URL)
+	assert.Equal(t, ts.URL+"/2.png", imgs[1].URL)
+
+	opt.SortImagesBy = ImageOrderArea
+	imgs = images(doc, ts.URL, opt, "")
+	assert.Equal(t, 2, len(imgs))
+	assert.Equal(t, ts.URL+"/2.png", imgs[0].URL)
+	assert.Equal(t, ts.URL+"/1.png", imgs[1].URL)
+}
+
+func TestSortImagesByScore(t *testing.T) {
+	html := `<body><img src="/1.png" width="400" height="300" alt="">
+<img src="/2.png" width="400" height="300" alt="logo">
+<img src="/3.png" width="800" height="600" alt="A red fox jumping over a fence"></body>`
+	opt := NewOption()
+	opt.MaxImageCount = 2
+	opt.SortImagesBy = ImageOrderScore
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, "http://example.com", opt, "")
+	if assert.Equal(t, 2, len(imgs)) {
+		assert.Equal(t, "http://example.com/3.png", imgs[0].URL)
+		assert.Equal(t, "http://example.com/1.png", imgs[1].URL)
+	}
+}
+
+func TestAltQuality(t *testing.T) {
+	for alt, want := range map[string]float64{
+		"":                                0,
+		"image":                           0,
+		"Photo 3":                         0,
+		"IMG_1234.jpg":                    0,
+		"Seoul skyline":                   0.5,
+		"The Seoul skyline at night":      1,
+		"  A red fox jumping over fence ": 1,
+	} {
+		assert.Equal(t, want, altQuality(alt), alt)
+	}
+}
+
+func TestNoscriptImages(t *testing.T) {
+	html := `<body>
+<img class="lazy" src="data:image/gif;base64,R0lGODlhAQABAAAAACw="><noscript><img src="/real.jpg" width="800" height="600" alt="Real"></noscript>
+<img data-original="/lazy.jpg" width="640" height="480"><noscript><img src="/lazy.jpg" width="640" height="480"></noscript>
+<noscript><p>Enable JavaScript</p></noscript>
+</body>`
+	opt := NewOption()
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, "http://example.com", opt, "")
+	if assert.Equal(t, 2, len(imgs)) {
+		assert.Equal(t, "http://example.com/real.jpg", imgs[0].URL)
+		assert.Equal(t, ImageSourceNoscript, imgs[0].Source)
+		assert.Equal(t, "http://example.com/lazy.jpg", imgs[1].URL)
+		assert.Equal(t, ImageSourceLazyAttr, imgs[1].Source)
+	}
+}
+
+func TestImageCaption(t *testing.T) {
+	html := `<body>
+<figure><img src="/a.jpg" width="800" height="600"><figcaption> The  <b>harbor</b> at dawn. </figcaption></figure>
+<figure><noscript><img src="/b.jpg" width="800" height="600"></noscript><figcaption>Lazy</figcaption></figure>
+<img src="/c.jpg" width="800" height="600">
+</body>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, "http://example.com", NewOption(), "")
+	if assert.Equal(t, 3, len(imgs)) {
+		assert.Equal(t, "The harbor at dawn.", imgs[0].Caption)
+		assert.Equal(t, "Lazy", imgs[1].Caption)
+		assert.Equal(t, "", imgs[2].Caption)
+	}
+}
+
+func TestImageProbingTimeout(t *testing.T) {
+	large := pngBytes(800, 600)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.png" {
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.Write(large)
+	}))
+	defer ts.Close()
+
+	html := `<body><img src="/fast.png"><img src="/slow.png"></body>`
+	opt := NewOption()
+	opt.ImageProbingTimeout = 100
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	start := time.Now()
+	imgs := images(doc, ts.URL, opt, "")
+	assert.True(t, time.Since(start) < 300*time.Millisecond)
+	assert.Equal(t, 1, len(imgs))
+	assert.Equal(t, ts.URL+"/fast.png", imgs[0].URL)
+}
+
+func TestImageFetchConcurrency(t *testing.T) {
+	large := pngBytes(800, 600)
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write(large)
+	}))
+	defer ts.Close()
+
+	html := `<body><img src="/1.png"><img src="/2.png"><img src="/3.png"><img src="/4.png"><img src="/5.png"><img src="/6.png"></body>`
+	opt := NewOption()
+	opt.MaxImageCount = 10
+	opt.ImageFetchConcurrency = 2
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, ts.URL, opt, "")
+	assert.Equal(t, 6, len(imgs))
+	assert.Equal(t, ts.URL+"/1.png", imgs[0].URL)
+	assert.True(t, atomic.LoadInt32(&maxInFlight) <= 2, "%v requests at once", maxInFlight)
+}
+
+func TestOnlyImageFormatsDetected(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "gif" {
+			w.Write(gifBytes(200, 200, 1, false))
+			return
+		}
+		w.Write(pngBytes(800, 600))
+	}))
+	defer ts.Close()
+
+	html := `<body><img src="/image?id=gif"><img src="/image?id=png"><img src="/photo.webp" width="800" height="600"></body>`
+	opt := NewOption()
+	opt.MinImageWidth, opt.MinImageHeight = 100, 100
+	opt.OnlyImageFormats = []string{"image/png", ".jpg"}
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, ts.URL, opt, "")
+	if assert.Equal(t, 1, len(imgs)) {
+		assert.Equal(t, ts.URL+"/image?id=png", imgs[0].URL)
+	}
+}
+
+func TestMaxImagesToParse(t *testing.T) {
+	large := pngBytes(800, 600)
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(large)
+	}))
+	defer ts.Close()
+
+	// images with both width and height are not requested, so they don't count for CheckImageLoopCount
+	html := `<body><img src="/1.png"><img src="/2.png"><img src="/3.png" width="400" height="300"><img src="/4.png" width="400" height="300"><img src="/5.png"></body>`
+	opt := NewOption()
+	opt.MaxImageCount = 10
+	opt.CheckImageLoopCount = 1
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, ts.URL, opt, "")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, 3, len(imgs))
+	assert.Equal(t, ts.URL+"/4.png", imgs[2].URL)
+
+	opt.MaxImagesToParse = 3
+	imgs = images(doc, ts.URL, opt, "")
+	assert.Equal(t, 2, len(imgs))
+	assert.Equal(t, ts.URL+"/3.png", imgs[1].URL)
+}
+
+func TestSkipImageProbing(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(pngBytes(800, 600))
+	}))
+	defer ts.Close()
+
+	html := `<head><meta property="og:image" content="/og.png"><meta property="og:image:width" content="1200"><meta property="og:image:height" content="630"></head>
+<body><img src="/unknown.png"><img src="/small.png" width="100" height="50">
+<img src="/retina.png" srcset="/retina.png 2x" width="150" height="100">
+<img src="/sized.png" width="400" height="300"></body>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	opt.SkipImageProbing = true
+	imgs := images(doc, ts.URL, opt, ts.URL+"/hero.png")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, len(imgs))
+	assert.Equal(t, ts.URL+"/retina.png", imgs[0].URL)
+	assert.Equal(t, uint32(300), imgs[0].Size.Width)
+	assert.Equal(t, uint32(200), imgs[0].Size.Height)
+	assert.Equal(t, ts.URL+"/sized.png", imgs[1].URL)
+
+	c, err := ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1200), c.Images[0].Size.Width)
+	assert.Equal(t, uint32(630), c.Images[0].Size.Height)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+}
+
+func TestSrcsetSize(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<img srcset="a.jpg 640w, b.jpg 1280w, c.jpg">`))
+	s := doc.Find("img")
+	w, h := srcsetSize(s, "b.jpg", 320, 180)
+	assert.Equal(t, []int{1280, 720}, []int{w, h})
+	w, h = srcsetSize(s, "c.jpg", 320, 180)
+	assert.Equal(t, []int{320, 180}, []int{w, h})
+	w, h = srcsetSize(s, "b.jpg", 0, 0)
+	assert.Equal(t, []int{0, 0}, []int{w, h})
+}
+
+func TestPreferArticleImages(t *testing.T) {
+	large := pngBytes(800, 600)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(large)
+	}))
+	defer ts.Close()
+
+	body := strings.Repeat("This is a long paragraph of the article, which is long enough to be the best candidate. ", 5)
+	html := `<body><div class="logo"><img src="/logo.png"></div>
+<div class="article"><p>` + body + `</p><img src="/photo.png"><p>` + body + `</p></div></body>`
+	opt := NewOption()
+	opt.PreferHeroImage = false
+	opt.PreferArticleImages = true
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err := ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(c.Images))
+	assert.Equal(t, ts.URL+"/photo.png", c.Images[0].URL)
+
+	// falls back to the whole document if the article has no image
+	html = `<body><div class="logo"><img src="/logo.png"></div><div class="article"><p>` + body + `</p></div></body>`
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, _ = ExtractFromDocument(doc, ts.URL, opt)
+	assert.Equal(t, 1, len(c.Images))
+	assert.Equal(t, ts.URL+"/logo.png", c.Images[0].URL)
+}
@@ -0,0 +1,60 @@
+package readability
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// imageResizeParams are query parameters used by CDNs and image servers for resizing,
+// which are ignored when comparing image URLs.
+var imageResizeParams = map[string]bool{
+	"w": true, "h": true, "width": true, "height": true, "resize": true, "fit": true,
+	"crop": true, "quality": true, "q": true, "dpr": true, "format": true, "fm": true,
+	"auto": true, "s": true, "size": true, "ssl": true, "strip": true, "zoom": true, "scale": true,
+}
+
+var (
+	// imageSizeSuffix matches size suffixes of file names, such as "-300x200", "_640w" and "@2x".
+	imageSizeSuffix = regexp.MustCompile(`(?i)([-_](\d+x\d+|\d+w|w\d+|h\d+|scaled)|@\d+(\.\d+)?x)+$`)
+
+	// imageTransformSegment matches path segments of resizing transforms,
+	// such as "w_640,h_480,c_fill" (Cloudinary) and "640x480".
+	imageTransformSegment = regexp.MustCompile(`^((w|h|c|q|f|g|ar|dpr)_[^/,]+,?)+$|^\d+x\d+$`)
+)
+
+// imageKey returns a key of the image URL src which is the same for responsive variants
+// of an image, by removing resizing query parameters, transform path segments,
+// size suffixes and the extension of the file name.
+func imageKey(src string) string {
+	if isDataURI(src) {
+		return src
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		return src
+	}
+
+	segs := []string{}
+	for _, seg := range strings.Split(u.Path, "/") {
+		if seg != "" && !imageTransformSegment.MatchString(seg) {
+			segs = append(segs, seg)
+		}
+	}
+	if n := len(segs); n > 0 {
+		name := strings.TrimSuffix(segs[n-1], path.Ext(segs[n-1]))
+		segs[n-1] = imageSizeSuffix.ReplaceAllString(name, "")
+	}
+
+	q := u.Query()
+	keys := []string{}
+	for k := range q {
+		if !imageResizeParams[strings.ToLower(k)] {
+			keys = append(keys, k+"="+strings.Join(q[k], ","))
+		}
+	}
+	sort.Strings(keys)
+	return strings.ToLower(u.Host) + "/" + strings.ToLower(strings.Join(segs, "/")) + "?" + strings.Join(keys, "&")
+}
@@ -0,0 +1,63 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageKey(t *testing.T) {
+	for _, variants := range [][]string{
+		{
+			"https://cdn.example.com/photos/hero.jpg?w=640&q=80",
+			"https://cdn.example.com/photos/hero.jpg?width=1280&auto=format",
+			"https://CDN.example.com/photos/hero-1024x768.webp",
+			"https://cdn.example.com/photos/hero@2x.jpg",
+		},
+		{
+			"https://res.example.com/image/upload/w_640,h_480,c_fill/v1/story.png",
+			"https://res.example.com/image/upload/v1/story.png",
+		},
+		{
+			"https://example.com/uploads/2019/02/jacket-scaled.jpg?id=1",
+			"https://example.com/uploads/2019/02/jacket_800w.jpg?id=1",
+		},
+	} {
+		for _, v := range variants[1:] {
+			assert.Equal(t, imageKey(variants[0]), imageKey(v), v)
+		}
+	}
+
+	assert.NotEqual(t, imageKey("https://example.com/a.jpg?id=1"), imageKey("https://example.com/a.jpg?id=2"))
+	assert.NotEqual(t, imageKey("https://example.com/a/hero.jpg"), imageKey("https://example.com/b/hero.jpg"))
+}
+
+func TestImagesDedupe(t *testing.T) {
+	large := pngBytes(800, 600)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(large)
+	}))
+	defer ts.Close()
+
+	html := `<body><img src="/hero.png?w=800"><img src="/hero-1600x1200.png"><img src="/mirror/copy.png"><img src="/other.png"></body>`
+	opt := NewOption()
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	imgs := images(doc, ts.URL, opt, "")
+	assert.Equal(t, 3, len(imgs))
+	assert.Equal(t, ts.URL+"/hero.png?w=800", imgs[0].URL)
+	assert.Equal(t, ts.URL+"/mirror/copy.png", imgs[1].URL)
+
+	// every image has the same content
+	opt.DedupeImagesBySignature = true
+	imgs = images(doc, ts.URL, opt, "")
+	assert.Equal(t, 1, len(imgs))
+
+	opt.DedupeImages = false
+	imgs = images(doc, ts.URL, opt, "")
+	assert.Equal(t, 3, len(imgs))
+	assert.Equal(t, ts.URL+"/hero-1600x1200.png", imgs[1].URL)
+}
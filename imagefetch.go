@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrImageTooLarge is returned by Image.Fetch when an image is larger than Option.MaxImageBytes.
+var ErrImageTooLarge = errors.New("image is too large")
+
+// Fetch downloads the image with the client and the TLS and redirect settings of opt,
+// which are used for probing image sizes. Data URIs are decoded without any request.
+// Images larger than opt.MaxImageBytes are not downloaded and ErrImageTooLarge is returned.
+// If opt is nil, the default option is used.
+//
+// The request is canceled when ctx is done. opt.ImageRequestTimeout is not applied,
+// since downloading whole images usually takes longer than probing sizes.
+func (i Image) Fetch(ctx context.Context, opt *Option) ([]byte, error) {
+	if opt == nil {
+		opt = NewOption()
+	}
+	if isDataURI(i.URL) {
+		r, err := dataURIReader(i.URL)
+		if err != nil {
+			return nil, err
+		}
+		return readAllLimited(r, opt.MaxImageBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := imageClient(opt).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, &HTTPError{URL: i.URL, StatusCode: resp.StatusCode}
+	}
+	if opt.MaxImageBytes > 0 && resp.ContentLength > opt.MaxImageBytes {
+		return nil, ErrImageTooLarge
+	}
+	return readAllLimited(resp.Body, opt.MaxImageBytes)
+}
+
+// readAllLimited reads all of r, returning ErrImageTooLarge if r is longer than max bytes.
+// If max is not positive, r is read without limit.
+func readAllLimited(r io.Reader, max int64) ([]byte, error) {
+	if max <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > max {
+		return nil, ErrImageTooLarge
+	}
+	return b, nil
+}
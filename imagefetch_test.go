@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageFetch(t *testing.T) {
+	img := pngBytes(320, 240)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/a.png" {
+			w.Write(img)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	b, err := Image{URL: ts.URL + "/a.png"}.Fetch(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, img, b)
+
+	opt := NewOption()
+	opt.MaxImageBytes = int64(len(img) - 1)
+	_, err = Image{URL: ts.URL + "/a.png"}.Fetch(context.Background(), opt)
+	assert.Equal(t, ErrImageTooLarge, err)
+
+	_, err = Image{URL: ts.URL + "/missing.png"}.Fetch(context.Background(), nil)
+	assert.NotNil(t, err)
+
+	b, err = Image{URL: "data:image/png;base64," + base64.StdEncoding.EncodeToString(img)}.Fetch(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, img, b)
+}
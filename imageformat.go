@@ -0,0 +1,288 @@
+package readability
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/philipjkim/fastimage"
+)
+
+const (
+	// webpHeaderLength is the number of leading bytes needed for the size of a WebP image.
+	webpHeaderLength = 30
+
+	// avifHeaderLength is the maximum number of leading bytes searched for the size of an AVIF image.
+	// The image spatial extents property is in the meta box, which is placed before the image data.
+	avifHeaderLength = 16 * 1024
+)
+
+var errUnknownImageSize = errors.New("unknown image size")
+
+// imageInfo is the result of detectImage.
+type imageInfo struct {
+	// size is the displayed size, swapped if rotated.
+	size *fastimage.ImageSize
+
+	// rotated is true if the EXIF orientation of a JPEG image rotates it by 90 or 270 degrees.
+	rotated bool
+
+	// animated is true if a GIF or WebP image has more than one frame.
+	animated bool
+
+	// mimeType is the MIME type of the detected format, like "image/png".
+	mimeType string
+}
+
+// imageTypes maps file extensions of images to their MIME types.
+var imageTypes = map[string]string{
+	".jpg": "image/jpeg", ".jpeg": "image/jpeg", ".jpe": "image/jpeg", ".png": "image/png",
+	".gif": "image/gif", ".webp": "image/webp", ".avif": "image/avif", ".bmp": "image/bmp",
+	".tif": "image/tiff", ".tiff": "image/tiff", ".svg": "image/svg+xml", ".ico": "image/x-icon",
+}
+
+// fastimageTypes maps the formats detected by fastimage to their MIME types.
+var fastimageTypes = map[fastimage.ImageType]string{
+	fastimage.GIF: "image/gif", fastimage.PNG: "image/png", fastimage.JPEG: "image/jpeg",
+	fastimage.BMP: "image/bmp", fastimage.TIFF: "image/tiff",
+}
+
+// detectImageSize detects the displayed size of the image read from r, reading as little as needed.
+// See detectImage.
+func detectImageSize(r io.Reader) (*fastimage.ImageSize, error) {
+	info, err := detectImage(r)
+	if err != nil {
+		return nil, err
+	}
+	return info.size, nil
+}
+
+// detectImage detects the displayed size of the image read from r, reading as little as needed.
+// WebP and AVIF are detected here, and other formats are detected by fastimage.
+// If the EXIF orientation of a JPEG image rotates it by 90 or 270 degrees,
+// the width and the height are swapped.
+func detectImage(r io.Reader) (*imageInfo, error) {
+	br := bufio.NewReaderSize(r, avifHeaderLength)
+	head, _ := br.Peek(webpHeaderLength)
+	info := &imageInfo{}
+	var err error
+	switch {
+	case isWebP(head):
+		if info.size, err = webpSize(head); err != nil {
+			return nil, err
+		}
+		info.animated = isAnimatedWebP(head)
+		info.mimeType = "image/webp"
+		return info, nil
+	case isAVIF(head):
+		b, _ := br.Peek(avifHeaderLength)
+		if info.size, err = avifSize(b); err != nil {
+			return nil, err
+		}
+		info.mimeType = "image/avif"
+		return info, nil
+	case isJPEG(head):
+		b, _ := br.Peek(exifHeaderLength)
+		info.rotated = isRotatedOrientation(exifOrientation(b))
+	case isGIF(head):
+		b, _ := br.Peek(gifHeaderLength)
+		info.animated = isAnimatedGIF(b)
+	}
+
+	t, size, err := fastimage.DetectImageTypeFromReader(br)
+	if err != nil {
+		return nil, err
+	}
+	if size == nil {
+		return nil, errUnknownImageSize
+	}
+	if info.rotated {
+		size = &fastimage.ImageSize{Width: size.Height, Height: size.Width}
+	}
+	info.size = size
+	info.mimeType = fastimageTypes[t]
+	return info, nil
+}
+
+// imageFormat returns the lowercased file extension of the image src and its MIME type:
+// detected if not empty, otherwise the media type of the data URI or the type of the extension.
+// The MIME type is empty if unknown.
+func imageFormat(src, detected string) (ext, mimeType string) {
+	if isDataURI(src) {
+		header := src[5:]
+		if i := strings.IndexAny(header, ";,"); i >= 0 {
+			header = header[:i]
+		}
+		return "", firstNonEmpty(detected, strings.ToLower(strings.TrimSpace(header)))
+	}
+	if u, err := url.Parse(src); err == nil {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+	return ext, firstNonEmpty(detected, imageTypes[ext])
+}
+
+// matchImageFormat returns true if the image src of ext and mimeType (see imageFormat)
+// matches pattern of Option.IgnoreImageFormat or Option.OnlyImageFormats:
+//   - "data:..." matches data URIs starting with it, like "data:image/".
+//   - ".ext" matches the file extension of the path, or the MIME type of the extension.
+//   - "type/subtype" matches the MIME type, or all subtypes if it ends with "/", like "image/".
+//   - Others match URLs containing them.
+func matchImageFormat(pattern, src, ext, mimeType string) bool {
+	p := strings.ToLower(strings.TrimSpace(pattern))
+	switch {
+	case strings.HasPrefix(p, "data:"):
+		return isDataURI(src) && strings.HasPrefix(strings.ToLower(src), p)
+	case strings.HasPrefix(p, "."):
+		return ext == p || mimeType != "" && imageTypes[p] == mimeType
+	case strings.Contains(p, "/"):
+		return mimeType != "" && (mimeType == p || strings.HasSuffix(p, "/") && strings.HasPrefix(mimeType, p))
+	}
+	return strings.Contains(src, pattern)
+}
+
+// isSupportedImageFormat returns true if the image src passes opt.IgnoreImageFormat and
+// opt.OnlyImageFormats. detected is the MIME type of the detected format, or "" before detection.
+// Images of unknown MIME types pass opt.OnlyImageFormats until detected.
+func isSupportedImageFormat(src, detected string, opt *Option) bool {
+	ext, mimeType := imageFormat(src, detected)
+	if !opt.DecodeDataURIImages || !isDataURI(src) {
+		for _, p := range opt.IgnoreImageFormat {
+			if matchImageFormat(p, src, ext, mimeType) {
+				return false
+			}
+		}
+	}
+	if len(opt.OnlyImageFormats) == 0 || mimeType == "" {
+		return true
+	}
+	for _, p := range opt.OnlyImageFormats {
+		if matchImageFormat(p, src, ext, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWebP(b []byte) bool {
+	return len(b) >= 16 && bytes.Equal(b[0:4], []byte("RIFF")) && bytes.Equal(b[8:12], []byte("WEBP"))
+}
+
+// webpSize returns the size of a lossy (VP8), lossless (VP8L) or extended (VP8X) WebP image.
+func webpSize(b []byte) (*fastimage.ImageSize, error) {
+	if len(b) < webpHeaderLength {
+		return nil, errUnknownImageSize
+	}
+	var w, h uint32
+	switch string(b[12:16]) {
+	case "VP8 ":
+		if !bytes.Equal(b[23:26], []byte{0x9d, 0x01, 0x2a}) {
+			return nil, errUnknownImageSize
+		}
+		w = uint32(binary.LittleEndian.Uint16(b[26:28]) & 0x3fff)
+		h = uint32(binary.LittleEndian.Uint16(b[28:30]) & 0x3fff)
+	case "VP8L":
+		if b[20] != 0x2f {
+			return nil, errUnknownImageSize
+		}
+		bits := binary.LittleEndian.Uint32(b[21:25])
+		w = bits&0x3fff + 1
+		h = bits>>14&0x3fff + 1
+	case "VP8X":
+		w = (uint32(b[24]) | uint32(b[25])<<8 | uint32(b[26])<<16) + 1
+		h = (uint32(b[27]) | uint32(b[28])<<8 | uint32(b[29])<<16) + 1
+	default:
+		return nil, errUnknownImageSize
+	}
+	return &fastimage.ImageSize{Width: w, Height: h}, nil
+}
+
+// isAnimatedWebP returns true if the extended (VP8X) WebP header has the animation flag.
+func isAnimatedWebP(b []byte) bool {
+	return len(b) >= 21 && string(b[12:16]) == "VP8X" && b[20]&0x02 != 0
+}
+
+func isAVIF(b []byte) bool {
+	if len(b) < 12 || !bytes.Equal(b[4:8], []byte("ftyp")) {
+		return false
+	}
+	end := int(binary.BigEndian.Uint32(b[0:4]))
+	if end > len(b) {
+		end = len(b)
+	}
+	// major brand, then compatible brands after the minor version
+	for i := 8; i+4 <= end; i += 4 {
+		if i == 12 {
+			continue
+		}
+		if brand := string(b[i : i+4]); brand == "avif" || brand == "avis" {
+			return true
+		}
+	}
+	return false
+}
+
+// avifSize returns the largest image spatial extents (ispe) in meta/iprp/ipco of an AVIF image.
+func avifSize(b []byte) (*fastimage.ImageSize, error) {
+	var size *fastimage.ImageSize
+	eachBox(b, func(typ string, body []byte) {
+		if typ != "meta" || len(body) < 4 {
+			return
+		}
+		// meta is a full box with 4 bytes of version and flags
+		eachBox(body[4:], func(typ string, body []byte) {
+			if typ != "iprp" {
+				return
+			}
+			eachBox(body, func(typ string, body []byte) {
+				if typ != "ipco" {
+					return
+				}
+				eachBox(body, func(typ string, body []byte) {
+					if typ != "ispe" || len(body) < 12 {
+						return
+					}
+					w, h := binary.BigEndian.Uint32(body[4:8]), binary.BigEndian.Uint32(body[8:12])
+					if size == nil || uint64(w)*uint64(h) > uint64(size.Width)*uint64(size.Height) {
+						size = &fastimage.ImageSize{Width: w, Height: h}
+					}
+				})
+			})
+		})
+	})
+	if size == nil {
+		return nil, errUnknownImageSize
+	}
+	return size, nil
+}
+
+// eachBox calls f with the type and the body of each ISO-BMFF box in b.
+// A box truncated at the end of b is passed with the available body.
+func eachBox(b []byte, f func(typ string, body []byte)) {
+	for len(b) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(b[0:4]))
+		typ := string(b[4:8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(b))
+		case 1:
+			if len(b) < 16 {
+				return
+			}
+			size, header = binary.BigEndian.Uint64(b[8:16]), 16
+		}
+		if size < header {
+			return
+		}
+		if size > uint64(len(b)) {
+			size = uint64(len(b))
+		}
+		f(typ, b[header:size])
+		b = b[size:]
+	}
+}
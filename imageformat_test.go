@@ -0,0 +1,100 @@
+package readability
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func webpBytes(chunk string, data []byte) []byte {
+	b := append([]byte("RIFF\x00\x00\x00\x00WEBP"+chunk+"\x00\x00\x00\x00"), data...)
+	return append(b, make([]byte, 32)...)
+}
+
+func box(typ string, body ...[]byte) []byte {
+	b := bytes.Join(body, nil)
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(8+len(b)))
+	return append(append(size, typ...), b...)
+}
+
+func ispe(w, h uint32) []byte {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint32(b[4:], w)
+	binary.BigEndian.PutUint32(b[8:], h)
+	return box("ispe", b)
+}
+
+func TestDetectImageSize(t *testing.T) {
+	avif := append(
+		box("ftyp", []byte("mif1\x00\x00\x00\x00mif1avif")),
+		box("meta", make([]byte, 4), box("hdlr", make([]byte, 24)),
+			box("iprp", box("ipco", ispe(160, 90), ispe(1920, 1080))))...)
+	avif = append(avif, box("mdat", make([]byte, 64))...)
+
+	for _, tc := range []struct {
+		name string
+		b    []byte
+		w, h uint32
+	}{
+		{"vp8", webpBytes("VP8 ", []byte{0, 0, 0, 0x9d, 0x01, 0x2a, 0x80, 0x02, 0xe0, 0x01}), 640, 480},
+		{"vp8l", webpBytes("VP8L", []byte{0x2f, 0x7f, 0xc2, 0x77, 0x00}), 640, 480},
+		{"vp8x", webpBytes("VP8X", []byte{0, 0, 0, 0, 0x7f, 0x07, 0x00, 0x37, 0x04, 0x00}), 1920, 1080},
+		{"avif", avif, 1920, 1080},
+		{"png", pngBytes(320, 240), 320, 240},
+	} {
+		size, err := detectImageSize(bytes.NewReader(tc.b))
+		if assert.Nil(t, err, tc.name) {
+			assert.Equal(t, tc.w, size.Width, tc.name)
+			assert.Equal(t, tc.h, size.Height, tc.name)
+		}
+	}
+
+	_, err := detectImageSize(bytes.NewReader(webpBytes("VP8 ", make([]byte, 10))))
+	assert.NotNil(t, err)
+	_, err = detectImageSize(bytes.NewReader(box("ftyp", []byte("avif\x00\x00\x00\x00"))))
+	assert.NotNil(t, err)
+}
+
+func TestIsSupportedImageFormat(t *testing.T) {
+	opt := NewOption()
+	opt.IgnoreImageFormat = []string{".svg", "image/gif", "data:image/"}
+	for _, tc := range []struct {
+		src, detected string
+		want          bool
+	}{
+		{"https://example.com/logo.svg", "", false},
+		{"https://example.com/logo.SVG?v=1", "", false},
+		{"https://example.com/image?src=logo.svg", "", true},
+		{"https://example.com/svg/photo.jpg", "", true},
+		{"https://example.com/anim.gif", "", false},
+		{"https://example.com/image?id=3", "", true},
+		{"https://example.com/image?id=3", "image/gif", false},
+		{"https://example.com/image?id=3", "image/png", true},
+		{"data:image/png;base64,iVBOR", "", false},
+	} {
+		assert.Equal(t, tc.want, isSupportedImageFormat(tc.src, tc.detected, opt), tc.src+" "+tc.detected)
+	}
+
+	opt = NewOption()
+	opt.IgnoreImageFormat = nil
+	opt.OnlyImageFormats = []string{".jpg", "image/png"}
+	for _, tc := range []struct {
+		src, detected string
+		want          bool
+	}{
+		{"https://example.com/photo.jpg", "", true},
+		{"https://example.com/photo.jpeg", "", true},
+		{"https://example.com/photo.png", "", true},
+		{"https://example.com/photo.webp", "", false},
+		{"https://example.com/image?id=3", "", true},
+		{"https://example.com/image?id=3", "image/jpeg", true},
+		{"https://example.com/image?id=3", "image/webp", false},
+		{"data:image/png;base64,iVBOR", "", true},
+		{"data:image/gif;base64,R0lGOD", "", false},
+	} {
+		assert.Equal(t, tc.want, isSupportedImageFormat(tc.src, tc.detected, opt), tc.src+" "+tc.detected)
+	}
+}
@@ -0,0 +1,152 @@
+package readability
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/philipjkim/fastimage"
+)
+
+const (
+	// imageRangeBytes is the number of leading bytes requested with a Range header for an image probe,
+	// which is enough for the sizes of all supported formats.
+	imageRangeBytes = 32 * 1024
+
+	// maxImageDrainBytes is the maximum number of bytes read and discarded
+	// after detecting an image size, so that HTTP/1.1 connections can be reused for small images.
+	// Servers ignoring the Range header may send more than this.
+	maxImageDrainBytes = imageRangeBytes
+
+	// imageSignatureBytes is the number of leading bytes compared by Option.DedupeImagesBySignature.
+	imageSignatureBytes = 4 * 1024
+)
+
+var (
+	// sharedImageClient is used for image probes if Option.ImageClient is nil.
+	sharedImageClient = NewImageClient(true)
+
+	// sharedInsecureImageClient is used instead of sharedImageClient if Option.ImageInsecureSkipVerify is set.
+	sharedInsecureImageClient = newImageClient(true, true)
+)
+
+// NewImageClient returns an http.Client for image probes, whose transport keeps
+// idle connections per host for reuse and optionally multiplexes requests over HTTP/2.
+func NewImageClient(http2 bool) *http.Client {
+	return newImageClient(http2, false)
+}
+
+func newImageClient(http2, insecureSkipVerify bool) *http.Client {
+	var tlsConfig *tls.Config
+	if insecureSkipVerify {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:   http2,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 16,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:     tlsConfig,
+		},
+	}
+}
+
+// imageClient returns the client for image probes, following up to opt.ImageMaxRedirects redirects.
+// opt.ImageClient takes precedence over opt.ImageInsecureSkipVerify, and its CheckRedirect is
+// called for the redirects within the limit.
+// The returned client shares the transport, so connections are reused across probes.
+func imageClient(opt *Option) *http.Client {
+	client := sharedImageClient
+	if opt.ImageClient != nil {
+		client = opt.ImageClient
+	} else if opt.ImageInsecureSkipVerify {
+		client = sharedInsecureImageClient
+	}
+
+	c := *client
+	max := opt.ImageMaxRedirects
+	next := client.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return fmt.Errorf("stopped after %v redirects: %v", max, req.URL)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	}
+	return &c
+}
+
+// probeImageSize requests the first imageRangeBytes of src and detects its size from them.
+func probeImageSize(src string, opt *Option) (*fastimage.ImageSize, error) {
+	info, _, err := probeImage(src, opt)
+	if err != nil {
+		return nil, err
+	}
+	return info.size, nil
+}
+
+// probeImage acts same as probeImageSize, and also returns the rotation and animation
+// of the image (see detectImage) and the hash of the first imageSignatureBytes of src
+// if opt.DedupeImages and opt.DedupeImagesBySignature are set.
+//
+// Probes to hosts which timed out are skipped with errImageHostSkipped. See HostBreaker.
+func probeImage(src string, opt *Option) (info *imageInfo, signature string, err error) {
+	host := imageHost(src)
+	if !allowImageHost(host, opt) {
+		return nil, "", errImageHostSkipped
+	}
+	defer func() {
+		reportImageHost(host, err, opt)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(opt.ImageRequestTimeout)*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", imageRangeBytes-1))
+	resp, err := imageClient(opt).Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, "", &HTTPError{URL: src, StatusCode: resp.StatusCode}
+	}
+
+	var body io.Reader = resp.Body
+	if opt.DedupeImages && opt.DedupeImagesBySignature {
+		br := bufio.NewReaderSize(resp.Body, imageSignatureBytes)
+		head, _ := br.Peek(imageSignatureBytes)
+		signature = fmt.Sprintf("%x", sha1.Sum(head))
+		body = br
+	}
+
+	info, err = detectImage(body)
+	if err == errUnknownImageSize {
+		return nil, "", fmt.Errorf("unknown image type: %v", src)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	io.CopyN(ioutil.Discard, resp.Body, maxImageDrainBytes)
+	return info, signature, nil
+}
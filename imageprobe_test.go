@@ -0,0 +1,122 @@
+package readability
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pngBytes(w, h int) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)))
+	return buf.Bytes()
+}
+
+func TestProbeImageSizeReusesConnections(t *testing.T) {
+	img := pngBytes(320, 240)
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(img)
+	}))
+	ts.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageClient = NewImageClient(false)
+	for i := 0; i < 5; i++ {
+		size, err := probeImageSize(ts.URL+"/a.png", opt)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(320), size.Width)
+		assert.Equal(t, uint32(240), size.Height)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&conns))
+
+	_, err := probeImageSize(ts.URL+"/missing.png", opt)
+	assert.NotNil(t, err)
+}
+
+func TestProbeImageSizeRangeAndRedirects(t *testing.T) {
+	img := pngBytes(320, 240)
+	var ranges []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a.png":
+			ranges = append(ranges, r.Header.Get("Range"))
+			w.Write(img)
+		case "/r1":
+			http.Redirect(w, r, "/r2", http.StatusFound)
+		case "/r2":
+			http.Redirect(w, r, "/a.png", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	_, err := probeImageSize(ts.URL+"/a.png", opt)
+	assert.NotNil(t, err, "self-signed certificate is not trusted")
+
+	opt.ImageInsecureSkipVerify = true
+	size, err := probeImageSize(ts.URL+"/r1", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(320), size.Width)
+	assert.Equal(t, []string{"bytes=0-32767"}, ranges)
+
+	opt.ImageMaxRedirects = 1
+	_, err = probeImageSize(ts.URL+"/r1", opt)
+	assert.NotNil(t, err)
+}
+
+func TestImageClientPrecedence(t *testing.T) {
+	img := pngBytes(320, 240)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a.png":
+			w.Write(img)
+		case "/r1":
+			http.Redirect(w, r, "/a.png", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	// ImageInsecureSkipVerify is ignored for ImageClient
+	opt := NewOption()
+	opt.ImageInsecureSkipVerify = true
+	opt.ImageClient = &http.Client{}
+	_, err := probeImageSize(ts.URL+"/a.png", opt)
+	assert.NotNil(t, err)
+
+	// CheckRedirect of ImageClient is called within ImageMaxRedirects
+	var redirects []string
+	client := ts.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		redirects = append(redirects, req.URL.Path)
+		return errors.New("redirect refused")
+	}
+	opt.ImageClient = client
+	_, err = probeImageSize(ts.URL+"/r1", opt)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"/a.png"}, redirects)
+
+	opt.ImageMaxRedirects = 0
+	_, err = probeImageSize(ts.URL+"/r1", opt)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"/a.png"}, redirects)
+}
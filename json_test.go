@@ -0,0 +1,63 @@
+package readability
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/philipjkim/fastimage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageJSON(t *testing.T) {
+	img := Image{URL: "http://example.com/a.jpg", Size: &fastimage.ImageSize{Width: 640, Height: 480}, Source: ImageSourceOpenGraph}
+	b, err := json.Marshal(img)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"url":"http://example.com/a.jpg","width":640,"height":480,"source":"og"}`, string(b))
+
+	var decoded Image
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, img, decoded)
+
+	img.DominantColor = "#336699"
+	b, err = json.Marshal(img)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"dominantColor":"#336699"`)
+	decoded = Image{}
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, img, decoded)
+
+	b, err = json.Marshal(Image{URL: "http://example.com/b.jpg"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"url":"http://example.com/b.jpg","width":0,"height":0}`, string(b))
+}
+
+func TestContentJSON(t *testing.T) {
+	c := &Content{
+		Title:       "Title",
+		Description: "Description",
+		Images:      []Image{Image{URL: "http://example.com/a.jpg", Size: &fastimage.ImageSize{Width: 1, Height: 2}}},
+		Tags:        []string{"go"},
+		AMPURL:      "http://example.com/amp",
+	}
+	b, err := json.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"title":"Title","description":"Description","images":[{"url":"http://example.com/a.jpg","width":1,"height":2}],"tags":["go"],"ampUrl":"http://example.com/amp"}`, string(b))
+
+	var decoded Content
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, *c, decoded)
+}
+
+func TestOptionJSON(t *testing.T) {
+	opt := NewOption()
+	opt.Reranker = RerankerFunc(func(cs []CandidateFeatures) ([]float64, error) { return nil, nil })
+	b, err := json.Marshal(opt)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "reranker")
+	assert.Contains(t, string(b), `"imageRequestTimeout":1000`)
+
+	var decoded Option
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	opt.Reranker = nil
+	assert.Equal(t, *opt, decoded)
+}
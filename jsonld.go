@@ -0,0 +1,178 @@
+package readability
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jsonLDObjects returns all JSON-LD objects in the document,
+// flattening top-level arrays and @graph entries.
+func jsonLDObjects(doc *goquery.Document) []map[string]interface{} {
+	objs := []map[string]interface{}{}
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var v interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &v); err != nil {
+			logger.Warnf("jsonLDObjects: invalid JSON-LD: %v", err)
+			return
+		}
+		objs = appendJSONLDObjects(objs, v)
+	})
+	return objs
+}
+
+func appendJSONLDObjects(objs []map[string]interface{}, v interface{}) []map[string]interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			objs = appendJSONLDObjects(objs, e)
+		}
+	case map[string]interface{}:
+		objs = append(objs, t)
+		if g, ok := t["@graph"]; ok {
+			objs = appendJSONLDObjects(objs, g)
+		}
+	}
+	return objs
+}
+
+// jsonLDStrings returns string values of v, which can be a string,
+// an array of strings, or objects having "name" or "@id".
+func jsonLDStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{decodeEntities(t)}
+	case []interface{}:
+		strs := []string{}
+		for _, e := range t {
+			strs = append(strs, jsonLDStrings(e)...)
+		}
+		return strs
+	case map[string]interface{}:
+		if name, ok := t["name"].(string); ok {
+			return []string{decodeEntities(name)}
+		}
+		if id, ok := t["@id"].(string); ok {
+			return []string{decodeEntities(id)}
+		}
+	}
+	return nil
+}
+
+// getContentFromJSONLD returns metadata of the first JSON-LD article object.
+func getContentFromJSONLD(doc *goquery.Document, reqURL string) *Metadata {
+	md := &Metadata{}
+	for _, obj := range jsonLDObjects(doc) {
+		if !jsonLDArticle(obj) {
+			continue
+		}
+		md.Title = firstNonEmpty(jsonLDString(obj["headline"]), jsonLDString(obj["name"]))
+		md.Description = jsonLDString(obj["description"])
+		md.Body = jsonLDString(obj["articleBody"])
+		md.Author = strings.Join(jsonLDStrings(obj["author"]), ", ")
+		md.PublishedTime = jsonLDString(obj["datePublished"])
+		if img := jsonLDImage(obj["image"]); img != "" {
+			if u, err := absPath(img, reqURL); err == nil {
+				md.ImageURL = u
+			}
+		}
+		if based := jsonLDURL(firstNonNil(obj["isBasedOn"], obj["isBasedOnUrl"])); based != "" {
+			if u, err := absPath(based, reqURL); err == nil {
+				md.BasedOn = u
+			}
+		}
+		break
+	}
+	return md
+}
+
+func jsonLDArticle(obj map[string]interface{}) bool {
+	for _, t := range jsonLDStrings(obj["@type"]) {
+		if isArticleType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonLDString returns the first string value of v, or empty string.
+func jsonLDString(v interface{}) string {
+	strs := jsonLDStrings(v)
+	if len(strs) == 0 {
+		return ""
+	}
+	return strs[0]
+}
+
+// jsonLDValue returns the first string or number value of v, which can also be an array of them.
+func jsonLDValue(v interface{}) string {
+	switch t := v.(type) {
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []interface{}:
+		for _, e := range t {
+			if s := jsonLDValue(e); s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+	return strings.TrimSpace(jsonLDString(v))
+}
+
+// jsonLDImage returns the first image URL of v, which can be a URL string,
+// an ImageObject having "url", or an array of them.
+func jsonLDImage(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, e := range t {
+			if u := jsonLDImage(e); u != "" {
+				return u
+			}
+		}
+	case map[string]interface{}:
+		if u, ok := t["url"].(string); ok {
+			return u
+		}
+		if u, ok := t["contentUrl"].(string); ok {
+			return u
+		}
+	}
+	return ""
+}
+
+// jsonLDURL returns the first URL of v, which can be a URL string,
+// an object having "url" or "@id", or an array of them.
+func jsonLDURL(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, e := range t {
+			if u := jsonLDURL(e); u != "" {
+				return u
+			}
+		}
+	case map[string]interface{}:
+		if u, ok := t["url"].(string); ok {
+			return u
+		}
+		if u, ok := t["@id"].(string); ok {
+			return u
+		}
+	}
+	return ""
+}
+
+func firstNonNil(values ...interface{}) interface{} {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetContentFromJSONLD(t *testing.T) {
+	html := `<html><head><script type="application/ld+json">
+[{"@type": "Organization", "name": "Publisher"},
+ {"@context": "https://schema.org", "@type": ["NewsArticle"], "headline": "Headline",
+  "author": [{"@type": "Person", "name": "A"}, {"@type": "Person", "name": "B"}],
+  "datePublished": "2019-02-01", "articleBody": "Body text.",
+  "image": {"@type": "ImageObject", "url": "/img/a.jpg"}}]
+</script></head></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	md := getContentFromJSONLD(doc, "https://example.com/news/1")
+	assert.Equal(t, "Headline", md.Title)
+	assert.Equal(t, "A, B", md.Author)
+	assert.Equal(t, "2019-02-01", md.PublishedTime)
+	assert.Equal(t, "Body text.", md.Body)
+	assert.Equal(t, "https://example.com/img/a.jpg", md.ImageURL)
+}
+
+func TestGetContentFromJSONLDDecodesEntities(t *testing.T) {
+	html := `<script type="application/ld+json">{"@type": "NewsArticle", "headline": "Rock &amp; Roll&#8217;s&nbsp;Return",
+  "author": {"@type": "Person", "name": "O&#039;Brien"}}</script>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	md := getContentFromJSONLD(doc, "https://example.com/news/1")
+	assert.Equal(t, "Rock & Roll’s Return", md.Title)
+	assert.Equal(t, "O'Brien", md.Author)
+}
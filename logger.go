@@ -1,12 +1,30 @@
 package readability
 
 import (
-	"io/ioutil"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync/atomic"
 )
 
-var logger = log.New(ioutil.Discard, "[readability] ", log.LstdFlags)
+// Logger receives the logs of the library with levels.
+// *slog.Logger satisfies Logger, so library logs can be integrated with application logging:
+//
+//	readability.SetLogger(slog.Default())
+type Logger interface {
+	// Debug receives the details of operations, such as goroutines started and finished.
+	Debug(msg string, args ...any)
+
+	// Warn receives recoverable failures, such as timeouts and invalid markup.
+	Warn(msg string, args ...any)
+}
+
+// currentLogger is the Logger set by SetLogger, or nil if logs are discarded.
+var currentLogger atomic.Pointer[Logger]
+
+// logger formats the logs of the library for currentLogger.
+var logger levelLogger
 
 func init() {
 	if getOrDefault("DEBUG", "false") == "true" {
@@ -14,8 +32,62 @@ func init() {
 	}
 }
 
+// SetLogger sets l to receive the logs of the library. If l is nil, logs are discarded,
+// which is the default. It is safe to call SetLogger while extracting.
+func SetLogger(l Logger) {
+	if l == nil {
+		currentLogger.Store(nil)
+		return
+	}
+	currentLogger.Store(&l)
+}
+
 // Debug enables debug logging of the operations done by the library.
 // If called, lots of information will be print to stdout.
 func Debug() {
-	logger = log.New(os.Stdout, "[readability] ", log.LstdFlags)
+	SetLogger(stdLogger{log.New(os.Stdout, "[readability] ", log.LstdFlags)})
+}
+
+// levelLogger formats messages only if a Logger is set.
+type levelLogger struct{}
+
+func (levelLogger) get() Logger {
+	if l := currentLogger.Load(); l != nil {
+		return *l
+	}
+	return nil
+}
+
+// Printf logs a debug message.
+func (ll levelLogger) Printf(format string, v ...interface{}) {
+	if l := ll.get(); l != nil {
+		l.Debug(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+	}
+}
+
+// Println logs a debug message.
+func (ll levelLogger) Println(v ...interface{}) {
+	if l := ll.get(); l != nil {
+		l.Debug(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+	}
+}
+
+// Warnf logs a warning message.
+func (ll levelLogger) Warnf(format string, v ...interface{}) {
+	if l := ll.get(); l != nil {
+		l.Warn(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+	}
+}
+
+// stdLogger is a Logger printing with a *log.Logger.
+type stdLogger struct {
+	l *log.Logger
+}
+
+func (s stdLogger) Debug(msg string, args ...any) {
+	s.l.Println(append([]any{msg}, args...)...)
+}
+
+func (s stdLogger) Warn(msg string, args ...any) {
+	s.l.Println(append([]any{"WARN", msg}, args...)...)
 }
@@ -0,0 +1,38 @@
+package readability
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Logger = slog.Default()
+
+type recordingLogger struct {
+	logs []string
+}
+
+func (r *recordingLogger) Debug(msg string, args ...any) {
+	r.logs = append(r.logs, "DEBUG "+msg)
+}
+
+func (r *recordingLogger) Warn(msg string, args ...any) {
+	r.logs = append(r.logs, "WARN "+msg)
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	r := &recordingLogger{}
+	SetLogger(r)
+	logger.Printf("a: %v\n", 1)
+	logger.Println("b", 2)
+	logger.Warnf("c: %v", fmt.Errorf("failed"))
+	assert.Equal(t, []string{"DEBUG a: 1", "DEBUG b 2", "WARN c: failed"}, r.logs)
+
+	SetLogger(nil)
+	logger.Warnf("d")
+	assert.Equal(t, 3, len(r.logs))
+}
@@ -0,0 +1,118 @@
+package readability
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// articleTypes are schema.org types of articles, lowercased.
+var articleTypes = map[string]bool{
+	"article":              true,
+	"newsarticle":          true,
+	"reportagenewsarticle": true,
+	"analysisnewsarticle":  true,
+	"blogposting":          true,
+	"techarticle":          true,
+	"scholarlyarticle":     true,
+	"report":               true,
+}
+
+// isArticleType reports whether t is a schema.org article type,
+// either as a short name ("NewsArticle") or as a URL ("https://schema.org/NewsArticle").
+func isArticleType(t string) bool {
+	return articleTypes[schemaTypeName(t)]
+}
+
+// Metadata contains article values declared by a page with structured data (JSON-LD or microdata).
+type Metadata struct {
+	Title         string
+	Description   string
+	Body          string
+	Author        string
+	PublishedTime string
+	ImageURL      string
+
+	// BasedOn is the absolute URL of the work the article is based on (JSON-LD isBasedOn),
+	// such as the original of a republished article.
+	BasedOn string
+}
+
+// IsEmpty returns true if md has no value usable as title, description or image.
+func (md Metadata) IsEmpty() bool {
+	return md.Title == "" &&
+		md.Description == "" &&
+		md.Body == "" &&
+		md.ImageURL == ""
+}
+
+// merge fills empty fields of md with values of other.
+func (md *Metadata) merge(other *Metadata) {
+	md.Title = firstNonEmpty(md.Title, other.Title)
+	md.Description = firstNonEmpty(md.Description, other.Description)
+	md.Body = firstNonEmpty(md.Body, other.Body)
+	md.Author = firstNonEmpty(md.Author, other.Author)
+	md.PublishedTime = firstNonEmpty(md.PublishedTime, other.PublishedTime)
+	md.ImageURL = firstNonEmpty(md.ImageURL, other.ImageURL)
+	md.BasedOn = firstNonEmpty(md.BasedOn, other.BasedOn)
+}
+
+// getMetadata returns article metadata from JSON-LD, falling back to microdata for missing values.
+func getMetadata(doc *goquery.Document, reqURL string) *Metadata {
+	md := getContentFromJSONLD(doc, reqURL)
+	md.merge(getContentFromMicrodata(doc, reqURL))
+	logger.Printf("Metadata: %+v\n", *md)
+	return md
+}
+
+// lookupMetadata returns opengraph values and structured data of doc as enabled by opt.
+// If opt.MetadataTimeout is set, they are read from a copy of doc in a goroutine
+// and empty values are returned if they are not read in time.
+func lookupMetadata(doc *goquery.Document, reqURL string, opt *Option) (*OpenGraph, *Metadata) {
+	lookup := func(doc *goquery.Document) (*OpenGraph, *Metadata) {
+		og := &OpenGraph{}
+		if opt.LookupOpenGraphTags {
+			if v, err := getContentFromOpenGraph(doc, reqURL); err == nil {
+				og = v
+			}
+		}
+		md := &Metadata{}
+		if opt.LookupStructuredData {
+			md = getMetadata(doc, reqURL)
+		}
+		return og, md
+	}
+	if opt.MetadataTimeout == 0 || !opt.LookupOpenGraphTags && !opt.LookupStructuredData {
+		return lookup(doc)
+	}
+
+	type result struct {
+		og *OpenGraph
+		md *Metadata
+	}
+	// the copy keeps the goroutine from reading doc while it is modified by description extraction.
+	clone := goquery.CloneDocument(doc)
+	ch := make(chan result, 1)
+	go func() {
+		og, md := lookup(clone)
+		ch <- result{og, md}
+	}()
+	select {
+	case r := <-ch:
+		return r.og, r.md
+	case <-time.After(time.Duration(opt.MetadataTimeout) * time.Millisecond):
+		logger.Warnf("lookupMetadata timed out: reqURL: %s", reqURL)
+		incTimeout(opt, StageMetadata)
+		return &OpenGraph{}, &Metadata{}
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
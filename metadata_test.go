@@ -0,0 +1,31 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupMetadata(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>
+<meta property="og:title" content="OG Title">
+<script type="application/ld+json">{"@type": "NewsArticle", "headline": "LD Title", "author": {"name": "Jane"}}</script>
+</head></html>`))
+	for _, timeout := range []uint{0, 1000} {
+		opt := NewOption()
+		opt.MetadataTimeout = timeout
+		og, md := lookupMetadata(doc, "http://example.com", opt)
+		assert.Equal(t, "OG Title", og.Title)
+		assert.Equal(t, "LD Title", md.Title)
+		assert.Equal(t, "Jane", md.Author)
+	}
+
+	opt := NewOption()
+	opt.LookupOpenGraphTags = false
+	opt.LookupStructuredData = false
+	og, md := lookupMetadata(doc, "http://example.com", opt)
+	assert.True(t, og.IsEmpty())
+	assert.True(t, md.IsEmpty())
+}
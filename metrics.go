@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"time"
+)
+
+// Stage is a stage of the extraction pipeline reported to Metrics.
+type Stage string
+
+// Stages of the extraction pipeline.
+const (
+	// StageFetch requests and parses a page.
+	StageFetch Stage = "fetch"
+
+	// StageMetadata reads opengraph tags and structured data.
+	StageMetadata Stage = "metadata"
+
+	// StageDescription extracts the description by readability rules, including retries.
+	StageDescription Stage = "description"
+
+	// StageImages probes images for their sizes.
+	StageImages Stage = "images"
+
+	// StageExtract is the whole extraction from a parsed document.
+	StageExtract Stage = "extract"
+)
+
+// Metrics receives measurements of the extraction pipeline for monitoring.
+// Methods are called concurrently, so implementations must be safe for concurrent use.
+//
+// A Prometheus adapter is a few lines of code, see README.
+type Metrics interface {
+	// ObserveStage receives the duration of a stage.
+	ObserveStage(stage Stage, d time.Duration)
+
+	// IncTimeout is called when a stage times out.
+	IncTimeout(stage Stage)
+
+	// ObserveCandidates receives the number of description candidates of an attempt of readability rules.
+	ObserveCandidates(n int)
+
+	// IncImageProbe is called when an image is requested for its size. ok is false if the request failed.
+	IncImageProbe(ok bool)
+}
+
+// observeStage reports the duration of stage since start to opt.Metrics if not nil.
+// The stage is also recorded as run for Content.Completeness.
+func observeStage(opt *Option, stage Stage, start time.Time) {
+	opt.stages.markRun(stage)
+	if opt.Metrics != nil {
+		opt.Metrics.ObserveStage(stage, time.Since(start))
+	}
+}
+
+func incTimeout(opt *Option, stage Stage) {
+	opt.stages.markTimedOut(stage)
+	if opt.Metrics != nil {
+		opt.Metrics.IncTimeout(stage)
+	}
+}
+
+func observeCandidates(opt *Option, c *candidates) {
+	if opt.Metrics != nil && c != nil {
+		opt.Metrics.ObserveCandidates(len(c.List))
+	}
+}
+
+func incImageProbe(opt *Option, ok bool) {
+	if opt.Metrics != nil {
+		opt.Metrics.IncImageProbe(ok)
+	}
+}
@@ -0,0 +1,75 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	stages     map[Stage]int
+	timeouts   map[Stage]int
+	candidates []int
+	probes     map[bool]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{stages: map[Stage]int{}, timeouts: map[Stage]int{}, probes: map[bool]int{}}
+}
+
+func (m *recordingMetrics) ObserveStage(stage Stage, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stages[stage]++
+}
+
+func (m *recordingMetrics) IncTimeout(stage Stage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeouts[stage]++
+}
+
+func (m *recordingMetrics) ObserveCandidates(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.candidates = append(m.candidates, n)
+}
+
+func (m *recordingMetrics) IncImageProbe(ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.probes[ok]++
+}
+
+func TestMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/a.png" {
+			w.Write(pngBytes(400, 300))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	html := strings.Replace(articleHTML, `<div class="widget">`, `<img src="/a.png"><img src="/missing.png"><div class="widget">`, 1)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	m := newRecordingMetrics()
+	opt := NewOption()
+	opt.RetryLength = 0
+	opt.Metrics = m
+	_, err := ExtractFromDocument(doc, ts.URL, opt)
+	assert.Nil(t, err)
+
+	assert.Equal(t, map[Stage]int{StageExtract: 1, StageMetadata: 1, StageDescription: 1, StageImages: 1}, m.stages)
+	assert.Equal(t, map[Stage]int{}, m.timeouts)
+	assert.Equal(t, 1, len(m.candidates))
+	assert.True(t, m.candidates[0] > 0)
+	assert.Equal(t, map[bool]int{true: 1, false: 1}, m.probes)
+}
@@ -0,0 +1,88 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// getContentFromMicrodata returns metadata of the first microdata (itemscope/itemtype) article.
+func getContentFromMicrodata(doc *goquery.Document, reqURL string) *Metadata {
+	md := &Metadata{}
+	doc.Find("[itemscope][itemtype]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if !isMicrodataArticle(s) {
+			return true
+		}
+		props := microdataProps(s)
+		md.Title = firstNonEmpty(microdataValue(props["headline"]), microdataValue(props["name"]))
+		md.Description = microdataValue(props["description"])
+		md.Body = normalizeSpaces(microdataValue(props["articleBody"]))
+		md.Author = microdataValue(props["author"])
+		md.PublishedTime = microdataValue(props["datePublished"])
+		if img := microdataValue(props["image"]); img != "" {
+			if u, err := absPath(img, reqURL); err == nil {
+				md.ImageURL = u
+			}
+		}
+		return false
+	})
+	return md
+}
+
+func isMicrodataArticle(s *goquery.Selection) bool {
+	for _, t := range strings.Fields(s.AttrOr("itemtype", "")) {
+		if isArticleType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// microdataProps returns the first element of each property belonging to scope,
+// excluding properties of nested items.
+func microdataProps(scope *goquery.Selection) map[string]*goquery.Selection {
+	props := map[string]*goquery.Selection{}
+	scopeNode := scope.Get(0)
+	scope.Find("[itemprop]").Each(func(i int, s *goquery.Selection) {
+		owner := s.Parent().Closest("[itemscope]")
+		if owner.Length() == 0 || owner.Get(0) != scopeNode {
+			return
+		}
+		for _, name := range strings.Fields(s.AttrOr("itemprop", "")) {
+			if _, ok := props[name]; !ok {
+				props[name] = s
+			}
+		}
+	})
+	return props
+}
+
+// microdataValue returns the value of a property element as defined in the microdata spec.
+// For nested items (e.g. author as a Person), the value of its "name" property is returned.
+func microdataValue(s *goquery.Selection) string {
+	if s == nil {
+		return ""
+	}
+	if _, ok := s.Attr("itemscope"); ok {
+		return microdataValue(microdataProps(s)["name"])
+	}
+
+	var v string
+	switch goquery.NodeName(s) {
+	case "meta":
+		v = s.AttrOr("content", "")
+	case "img", "audio", "video", "source", "embed", "iframe", "track":
+		v = s.AttrOr("src", "")
+	case "a", "area", "link":
+		v = s.AttrOr("href", "")
+	case "object":
+		v = s.AttrOr("data", "")
+	case "data", "meter":
+		v = s.AttrOr("value", "")
+	case "time":
+		v = s.AttrOr("datetime", s.Text())
+	default:
+		v = s.Text()
+	}
+	return strings.TrimSpace(v)
+}
@@ -0,0 +1,58 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetContentFromMicrodata(t *testing.T) {
+	html := `<html><body>
+<div itemscope itemtype="https://schema.org/WebSite"><span itemprop="name">Site</span></div>
+<article itemscope itemtype="http://schema.org/NewsArticle">
+  <h1 itemprop="headline">Microdata Headline</h1>
+  <span itemprop="author" itemscope itemtype="https://schema.org/Person"><span itemprop="name">Jane Doe</span></span>
+  <time itemprop="datePublished" datetime="2019-02-01T09:00:00Z">Feb 1</time>
+  <img itemprop="image" src="/img/hero.jpg">
+  <div itemprop="articleBody"><p>First   paragraph.</p>
+  <p>Second paragraph.</p></div>
+</article>
+</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	md := getContentFromMicrodata(doc, "http://example.com/news/1")
+	assert.Equal(t, "Microdata Headline", md.Title)
+	assert.Equal(t, "Jane Doe", md.Author)
+	assert.Equal(t, "2019-02-01T09:00:00Z", md.PublishedTime)
+	assert.Equal(t, "http://example.com/img/hero.jpg", md.ImageURL)
+	assert.Equal(t, "First paragraph. Second paragraph.", md.Body)
+	assert.Equal(t, "", md.Description)
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<div itemscope itemtype="https://schema.org/Product"><span itemprop="name">P</span></div>`))
+	assert.True(t, getContentFromMicrodata(doc, "http://example.com").IsEmpty())
+}
+
+func TestGetMetadataFallback(t *testing.T) {
+	html := `<html><head>
+<script type="application/ld+json">{"@type": "NewsArticle", "headline": "JSON-LD Headline", "description": "From JSON-LD"}</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/Article">
+  <meta itemprop="headline" content="Microdata Headline">
+  <meta itemprop="author" content="Microdata Author">
+</div>
+</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	md := getMetadata(doc, "http://example.com")
+	assert.Equal(t, "JSON-LD Headline", md.Title)
+	assert.Equal(t, "From JSON-LD", md.Description)
+	assert.Equal(t, "Microdata Author", md.Author)
+
+	opt := NewOption()
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "JSON-LD Headline", c.Title)
+	assert.Equal(t, "From JSON-LD", c.Description)
+	assert.Equal(t, "Microdata Author", c.Author)
+	assert.Empty(t, c.Images)
+}
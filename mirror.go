@@ -0,0 +1,120 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"html"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Mirror is a page serving the content of another page, such as a search engine cache
+// or a text-only reader endpoint.
+type Mirror struct {
+	// Name identifies the mirror, like "google-cache".
+	Name string `json:"name"`
+
+	// URL is the URL of the mirrored page.
+	URL string `json:"url"`
+}
+
+// MirrorResolver returns the mirrors of reqURL, which are tried in order
+// when the page itself is blocked. See Option.MirrorResolver.
+type MirrorResolver interface {
+	Mirrors(reqURL string) []Mirror
+}
+
+// MirrorResolverFunc is an adapter to allow the use of ordinary functions as a MirrorResolver.
+type MirrorResolverFunc func(reqURL string) []Mirror
+
+// Mirrors calls f(reqURL).
+func (f MirrorResolverFunc) Mirrors(reqURL string) []Mirror {
+	return f(reqURL)
+}
+
+// PrefixMirror returns a MirrorResolver for a mirror serving pages at prefix followed by
+// their URLs, such as text-only reader endpoints like "https://r.jina.ai/".
+func PrefixMirror(name, prefix string) MirrorResolver {
+	return MirrorResolverFunc(func(reqURL string) []Mirror {
+		return []Mirror{{Name: name, URL: prefix + reqURL}}
+	})
+}
+
+// GoogleCacheMirror returns a MirrorResolver for the Google cache.
+func GoogleCacheMirror() MirrorResolver {
+	return MirrorResolverFunc(func(reqURL string) []Mirror {
+		return []Mirror{{
+			Name: "google-cache",
+			URL:  "https://webcache.googleusercontent.com/search?q=cache:" + url.QueryEscape(reqURL),
+		}}
+	})
+}
+
+// blockedStatusCodes are HTTP status codes returned by pages refusing crawlers.
+var blockedStatusCodes = map[int]bool{
+	http.StatusUnauthorized:       true,
+	http.StatusForbidden:          true,
+	http.StatusTooManyRequests:    true,
+	http.StatusServiceUnavailable: true,
+}
+
+// isBlocked returns true if err means the page refused to be requested.
+func isBlocked(err error) bool {
+	var he *HTTPError
+	return errors.Is(err, ErrBotChallenge) || errors.As(err, &he) && blockedStatusCodes[he.StatusCode]
+}
+
+// extractFromMirrors returns contents of reqURL extracted from the first mirror
+// returned by e.Option.MirrorResolver which has a non-empty description.
+// If no mirror succeeds, blockedErr is returned.
+func (e *Extractor) extractFromMirrors(ctx context.Context, reqURL string, blockedErr error) (*Content, error) {
+	for _, m := range e.Option.MirrorResolver.Mirrors(reqURL) {
+		page, err := e.fetch(ctx, m.URL)
+		if err != nil {
+			logger.Warnf("extractFromMirrors failed for %v: %v", m.URL, err)
+			continue
+		}
+		c, err := extractFromDocument(page.doc, reqURL, e.Option)
+		if err != nil {
+			logger.Warnf("extractFromMirrors failed for %v: %v", m.URL, err)
+			continue
+		}
+		if c.Description == "" {
+			continue
+		}
+		c.setFetched(page, e.Option)
+		m := m
+		c.Mirror = &m
+		return c, nil
+	}
+	return nil, blockedErr
+}
+
+var blankLines = regexp.MustCompile(`\n\s*\n`)
+
+// isPlainText returns true if contentType is plain text or markdown, as served by text-only mirrors.
+func isPlainText(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mt == "text/plain" || mt == "text/markdown")
+}
+
+// plainTextToHTML converts plain text into an HTML document with a paragraph per block
+// separated by blank lines, so that readability rules can score it.
+func plainTextToHTML(s string) string {
+	var sb strings.Builder
+	sb.WriteString("<html><body><article>")
+	for _, p := range blankLines.Split(strings.ReplaceAll(s, "\r\n", "\n"), -1) {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(p), "\n", "<br>"))
+		sb.WriteString("</p>")
+	}
+	sb.WriteString("</article></body></html>")
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractorExtractFromMirrors(t *testing.T) {
+	text := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor. ", 4)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blocked", "/down/blocked":
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		case "/missing":
+			http.NotFound(w, r)
+		case "/text/blocked":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprintf(w, "Title\n\n%s\n\n%s", text, text)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	opt.MirrorResolver = MirrorResolverFunc(func(reqURL string) []Mirror {
+		path := strings.TrimPrefix(reqURL, ts.URL)
+		return []Mirror{
+			{Name: "down", URL: ts.URL + "/down" + path},
+			{Name: "text", URL: ts.URL + "/text" + path},
+		}
+	})
+	e := NewExtractor(opt)
+
+	c, err := e.Extract(context.Background(), ts.URL+"/blocked")
+	assert.Nil(t, err)
+	assert.Equal(t, &Mirror{Name: "text", URL: ts.URL + "/text/blocked"}, c.Mirror)
+	assert.Contains(t, c.Description, "Lorem ipsum")
+
+	_, err = e.Extract(context.Background(), ts.URL+"/missing")
+	assert.Equal(t, &HTTPError{URL: ts.URL + "/missing", StatusCode: http.StatusNotFound}, err)
+
+	opt.MirrorResolver = PrefixMirror("down", ts.URL+"/down")
+	_, err = e.Extract(context.Background(), ts.URL+"/blocked")
+	assert.Equal(t, &HTTPError{URL: ts.URL + "/blocked", StatusCode: http.StatusForbidden}, err)
+}
+
+func TestMirrorResolvers(t *testing.T) {
+	assert.Equal(t, []Mirror{{Name: "reader", URL: "https://r.example.com/https://example.com/a"}},
+		PrefixMirror("reader", "https://r.example.com/").Mirrors("https://example.com/a"))
+	assert.Equal(t, []Mirror{{Name: "google-cache", URL: "https://webcache.googleusercontent.com/search?q=cache:https%3A%2F%2Fexample.com%2Fa%3Fb%3D1"}},
+		GoogleCacheMirror().Mirrors("https://example.com/a?b=1"))
+}
+
+func TestPlainTextToHTML(t *testing.T) {
+	assert.Equal(t, "<html><body><article><p>a &lt;b&gt;<br>c</p><p>d</p></article></body></html>",
+		plainTextToHTML("a <b>\r\nc\r\n\r\n  \n d\n"))
+}
@@ -0,0 +1,566 @@
+package readability
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	// mozillaCharThreshold is the number of characters an article needs to stop retrying.
+	mozillaCharThreshold = 500
+
+	// mozillaTopCandidates is the number of top candidates compared for a shared ancestor.
+	mozillaTopCandidates = 5
+
+	// mozillaMinTopCandidates is the number of top candidates which must share an ancestor
+	// to move the best candidate up to it.
+	mozillaMinTopCandidates = 3
+
+	// mozillaAncestorLevels is the number of ancestors a paragraph gives its score to.
+	mozillaAncestorLevels = 5
+)
+
+// mozillaPatterns are the class and id patterns of Readability.js.
+var mozillaPatterns = struct {
+	unlikely, maybe, positive, negative *regexp.Regexp
+}{
+	unlikely: regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`),
+	maybe:    regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`),
+	positive: regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`),
+	negative: regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`),
+}
+
+var (
+	// mozillaUnlikelyRoles are ARIA roles of elements removed as unlikely candidates.
+	mozillaUnlikelyRoles = map[string]bool{"menu": true, "menubar": true, "complementary": true,
+		"navigation": true, "alert": true, "alertdialog": true, "dialog": true}
+
+	// mozillaTagsToScore are the elements whose text is scored.
+	mozillaTagsToScore = map[string]bool{"section": true, "h2": true, "h3": true, "h4": true,
+		"h5": true, "h6": true, "p": true, "td": true, "pre": true}
+
+	// mozillaDivToPElements are descendants which keep a <div> from being transformed into <p>.
+	mozillaDivToPElements = map[string]bool{"blockquote": true, "dl": true, "div": true, "img": true,
+		"ol": true, "p": true, "pre": true, "table": true, "ul": true}
+
+	// mozillaBlocks are removed by mozillaPrepare if they have neither text nor mozillaMedia.
+	mozillaBlocks = map[string]bool{"div": true, "section": true, "header": true, "h1": true, "h2": true,
+		"h3": true, "h4": true, "h5": true, "h6": true}
+
+	// mozillaMedia are descendants which keep a block without text from being removed.
+	mozillaMedia = map[string]bool{"img": true, "picture": true, "video": true, "iframe": true,
+		"object": true, "embed": true, "svg": true}
+
+	// mozillaJunkTags are removed from the article before conditional cleaning.
+	mozillaJunkTags = map[string]bool{"footer": true, "aside": true, "link": true, "input": true,
+		"textarea": true, "select": true, "button": true}
+
+	// mozillaHidden matches inline styles hiding elements.
+	mozillaHidden = regexp.MustCompile(`(?i)display\s*:\s*none|visibility\s*:\s*hidden`)
+)
+
+// mozillaArticle extracts the article of doc by EngineMozilla within opt.DescriptionExtractionTimeout.
+func mozillaArticle(doc *goquery.Document, reqURL string, opt *Option) *articleResult {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan *articleResult)
+	done := make(chan struct{})
+
+	go func() {
+		logger.Println("goroutine@mozillaArticle started")
+		defer logger.Println("goroutine@mozillaArticle finished")
+		defer close(done)
+
+		r := mozillaPasses(ctx, doc, reqURL, opt)
+
+		select {
+		case ch <- r:
+			logger.Println("goroutine@mozillaArticle sent data to ch")
+		case <-ctx.Done():
+			logger.Println("goroutine@mozillaArticle didn't send data to ch (context closed)")
+		}
+	}()
+
+	timeout := time.After(time.Duration(opt.DescriptionExtractionTimeout) * time.Millisecond)
+	select {
+	case r := <-ch:
+		logger.Println("receiver@mozillaArticle got data from ch")
+		return r
+	case <-timeout:
+		logger.Warnf("mozillaArticle timed out")
+		incTimeout(opt, StageDescription)
+		// the passes check ctx at every node, so they stop right away and opt.trace
+		// is no longer written once this returns
+		cancel()
+		<-done
+		return &articleResult{prepared: doc, engine: EngineMozilla}
+	}
+}
+
+// mozillaPasses runs the passes of mozillaArticle on copies of doc until ctx is canceled,
+// and returns the first article of mozillaCharThreshold characters or the longest one.
+func mozillaPasses(ctx context.Context, doc *goquery.Document, reqURL string, opt *Option) *articleResult {
+	var best *articleResult
+	bestLength := -1
+	for pass := opt; pass != nil && ctx.Err() == nil; pass = relaxedOption(pass) {
+		work, origins := cloneDocumentWithOrigins(doc)
+		r := mozillaAttempt(ctx, work, reqURL, pass)
+		r.top = origins[r.top]
+		length := len(strings.Join(r.paragraphs, " "))
+		if length >= mozillaCharThreshold {
+			return r
+		}
+		if length > bestLength {
+			best, bestLength = r, length
+		}
+	}
+	return best
+}
+
+// mozillaAttempt is a pass of grabArticle on work with the flags of opt.
+func mozillaAttempt(ctx context.Context, work *goquery.Document, reqURL string, opt *Option) *articleResult {
+	var exp *Explanation
+	if opt.Explain {
+		exp = &Explanation{}
+	}
+	result := &articleResult{prepared: work, explanation: exp, engine: EngineMozilla}
+	body := work.Find("body").Get(0)
+	if body == nil {
+		return result
+	}
+
+	toScore := mozillaPrepare(ctx, body, opt)
+	if ctx.Err() != nil {
+		return result
+	}
+	scores := map[*html.Node]float64{}
+	var scored []*html.Node
+	initialize := func(n *html.Node) {
+		if _, ok := scores[n]; !ok {
+			scores[n] = mozillaInitialScore(n, opt)
+			scored = append(scored, n)
+		}
+	}
+	for _, e := range toScore {
+		if e.Parent == nil || e.Parent.Type != html.ElementNode {
+			continue
+		}
+		text := strings.TrimSpace(nodeText(e))
+		if len(text) < 25 {
+			continue
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+		level := 0
+		for a := e.Parent; a != nil && level < mozillaAncestorLevels; a = a.Parent {
+			if a.Type != html.ElementNode || a.Parent == nil || a.Parent.Type != html.ElementNode {
+				break
+			}
+			initialize(a)
+			divider := 1.0
+			if level == 1 {
+				divider = 2
+			} else if level > 1 {
+				divider = float64(level * 3)
+			}
+			scores[a] += score / divider
+			level++
+		}
+	}
+
+	// scale by link density and keep the top candidates, the first initialized first on ties
+	mozillaLinkStats(body, func(n *html.Node, st mozillaStats) {
+		if _, ok := scores[n]; ok {
+			scores[n] *= 1 - st.linkDensity()
+		}
+	})
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scores[scored[i]] > scores[scored[j]]
+	})
+	top := scored
+	if len(top) > mozillaTopCandidates {
+		top = top[:mozillaTopCandidates]
+	}
+
+	var topCandidate *html.Node
+	if len(top) == 0 || top[0].Data == "body" {
+		// wrap all the content of the body into a single candidate
+		topCandidate = &html.Node{Type: html.ElementNode, Data: "div"}
+		for c := body.FirstChild; c != nil; c = body.FirstChild {
+			body.RemoveChild(c)
+			topCandidate.AppendChild(c)
+		}
+		body.AppendChild(topCandidate)
+		initialize(topCandidate)
+	} else {
+		topCandidate = mozillaBestAncestor(top, scores, initialize)
+	}
+	result.heading = bestHeading(work.FindNodes(topCandidate), opt)
+	result.top = topCandidate
+
+	article := mozillaSiblings(work, topCandidate, scores, opt, exp)
+	removeElements(article.Get(0), mozillaJunkTags)
+	sanitize(article, &candidates{Map: map[string]candidate{}}, opt, exp)
+	result.render(article, reqURL, opt)
+	return result
+}
+
+// mozillaPrepare removes hidden elements, scripts, unlikely candidates and empty blocks
+// from the subtree of body, transforms <div>s without block elements into <p>,
+// and returns the elements to score in document order.
+//
+// The subtree is walked once, bottom-up: the blocks are checked with the statistics of
+// their descendants, which are prepared already, so that nested layouts stay linear.
+// The walk stops when ctx is canceled.
+func mozillaPrepare(ctx context.Context, body *html.Node, opt *Option) []*html.Node {
+	var walk func(n *html.Node) mozillaStats
+	walk = func(n *html.Node) mozillaStats {
+		st := mozillaStats{blank: true}
+		for c := n.FirstChild; c != nil && ctx.Err() == nil; {
+			next := c.NextSibling
+			switch c.Type {
+			case html.TextNode:
+				st.textLength += len(c.Data)
+				st.blank = st.blank && strings.TrimSpace(c.Data) == ""
+			case html.ElementNode:
+				if mozillaRemovable(c, opt) {
+					n.RemoveChild(c)
+					break
+				}
+				cs := walk(c)
+				if mozillaBlocks[c.Data] && cs.blank && !cs.media {
+					n.RemoveChild(c)
+					break
+				}
+				if c.Data == "div" {
+					if p := singleChild(c, "p"); p != nil && cs.linkDensity() < 0.25 {
+						// replace the div with its only paragraph
+						c.RemoveChild(p)
+						n.InsertBefore(p, c)
+						n.RemoveChild(c)
+						c = p
+					} else if !cs.block {
+						c.Data = "p"
+					}
+				}
+				st.add(c, cs)
+			}
+			c = next
+		}
+		return st
+	}
+	walk(body)
+
+	var toScore []*html.Node
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if mozillaTagsToScore[c.Data] {
+				toScore = append(toScore, c)
+			}
+			collect(c)
+		}
+	}
+	collect(body)
+	return toScore
+}
+
+// mozillaStats are the statistics of the descendants of an element used by mozillaPrepare.
+type mozillaStats struct {
+	textLength     int
+	linkTextLength int  // text length under <a>s, counted once per <a> ancestor as in subtreeStats
+	blank          bool // no text other than whitespace
+	media          bool // has a descendant of mozillaMedia
+	block          bool // has a descendant of mozillaDivToPElements
+}
+
+// add adds c, a child element, and cs, the statistics of its descendants, to st.
+func (st *mozillaStats) add(c *html.Node, cs mozillaStats) {
+	st.textLength += cs.textLength
+	st.linkTextLength += cs.linkTextLength
+	if c.Data == "a" {
+		st.linkTextLength += cs.textLength
+	}
+	st.blank = st.blank && cs.blank
+	st.media = st.media || cs.media || mozillaMedia[c.Data]
+	st.block = st.block || cs.block || mozillaDivToPElements[c.Data]
+}
+
+// linkDensity is the same as linkDensity of the element.
+func (st mozillaStats) linkDensity() float64 {
+	if st.textLength == 0 {
+		return 0
+	}
+	return float64(st.linkTextLength) / float64(st.textLength)
+}
+
+// mozillaLinkStats calls f with every element of the subtree of n, and n itself, and the statistics
+// of its descendants, computed in a single walk.
+func mozillaLinkStats(n *html.Node, f func(*html.Node, mozillaStats)) mozillaStats {
+	st := mozillaStats{blank: true}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			st.textLength += len(c.Data)
+			st.blank = st.blank && strings.TrimSpace(c.Data) == ""
+		case html.ElementNode:
+			st.add(c, mozillaLinkStats(c, f))
+		}
+	}
+	f(n, st)
+	return st
+}
+
+// mozillaRemovable returns true if n is hidden, a script or an unlikely candidate
+// (if opt.RemoveUnlikelyCandidates is set).
+func mozillaRemovable(n *html.Node, opt *Option) bool {
+	switch n.Data {
+	case "script", "style", "noscript", "template":
+		return true
+	}
+	if hasNodeAttr(n, "hidden") || nodeAttr(n, "aria-hidden") == "true" || mozillaHidden.MatchString(nodeAttr(n, "style")) {
+		return true
+	}
+	if opt.RemoveUnlikelyCandidates && n.Data != "body" && n.Data != "a" {
+		match := nodeAttr(n, "class") + " " + nodeAttr(n, "id")
+		if mozillaPatterns.unlikely.MatchString(match) && !mozillaPatterns.maybe.MatchString(match) &&
+			!hasAncestor(n, "table") && !hasAncestor(n, "code") {
+			return true
+		}
+		if mozillaUnlikelyRoles[nodeAttr(n, "role")] {
+			return true
+		}
+	}
+	return false
+}
+
+// mozillaInitialScore returns the initial score of n by its tag name and class weight.
+func mozillaInitialScore(n *html.Node, opt *Option) float64 {
+	score := 0.0
+	switch n.Data {
+	case "div":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+	return score + mozillaClassWeight(n, opt)
+}
+
+// mozillaClassWeight returns the weight of the class and the id of n by mozillaPatterns.
+func mozillaClassWeight(n *html.Node, opt *Option) float64 {
+	if !opt.WeightClasses {
+		return 0
+	}
+	weight := 0.0
+	for _, v := range []string{nodeAttr(n, "class"), nodeAttr(n, "id")} {
+		if v == "" {
+			continue
+		}
+		if mozillaPatterns.negative.MatchString(v) {
+			weight -= 25
+		}
+		if mozillaPatterns.positive.MatchString(v) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// mozillaBestAncestor returns the best candidate of top, moved up to an ancestor shared by
+// mozillaMinTopCandidates of the top candidates scoring close to the first one,
+// to an ancestor scoring higher, or to an ancestor of which it is the only child.
+func mozillaBestAncestor(top []*html.Node, scores map[*html.Node]float64, initialize func(*html.Node)) *html.Node {
+	best := top[0]
+	var alternatives [][]*html.Node
+	for _, c := range top[1:] {
+		if scores[c]/scores[best] >= 0.75 {
+			alternatives = append(alternatives, ancestors(c))
+		}
+	}
+	if len(alternatives) >= mozillaMinTopCandidates {
+		for p := best.Parent; p != nil && p.Type == html.ElementNode && p.Data != "body"; p = p.Parent {
+			shared := 0
+			for _, as := range alternatives {
+				for _, a := range as {
+					if a == p {
+						shared++
+						break
+					}
+				}
+			}
+			if shared >= mozillaMinTopCandidates {
+				best = p
+				break
+			}
+		}
+	}
+	initialize(best)
+
+	lastScore := scores[best]
+	threshold := lastScore / 3
+	for p := best.Parent; p != nil && p.Type == html.ElementNode && p.Data != "body"; p = p.Parent {
+		score, ok := scores[p]
+		if !ok {
+			continue
+		}
+		if score < threshold {
+			break
+		}
+		if score > lastScore {
+			best = p
+			break
+		}
+		lastScore = score
+	}
+
+	for p := best.Parent; p != nil && p.Type == html.ElementNode && p.Data != "body" && elementCount(p) == 1; p = p.Parent {
+		best = p
+	}
+	initialize(best)
+	return best
+}
+
+// mozillaSiblings returns the article of best merged with its qualified siblings.
+func mozillaSiblings(work *goquery.Document, best *html.Node, scores map[*html.Node]float64,
+	opt *Option, exp *Explanation) *goquery.Document {
+	thresholds := siblingThresholds(opt)
+	bestScore := scores[best]
+	siblingScoreThreshold := thresholds.scoreThreshold(bestScore)
+	exp.setSiblingScoreThreshold(siblingScoreThreshold)
+	bestClass := nodeAttr(best, "class")
+
+	output, _ := goquery.NewDocumentFromReader(strings.NewReader("<div></div>"))
+	root := output.Find("div")
+	siblings := []*html.Node{best}
+	if best.Parent != nil {
+		siblings = nil
+		for c := best.Parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				siblings = append(siblings, c)
+			}
+		}
+	}
+	for _, n := range siblings {
+		s := work.FindNodes(n)
+		score, scored := scores[n]
+		kept := false
+		var code ReasonCode
+		if n == best {
+			kept, code = true, ReasonBestCandidate
+		} else {
+			bonus := 0.0
+			if bestClass != "" && nodeAttr(n, "class") == bestClass {
+				bonus = bestScore * thresholds.ScoreRatio
+			}
+			if scored && score+bonus >= siblingScoreThreshold {
+				kept, code = true, ReasonSiblingScore
+			} else if n.Data == "p" {
+				ld := newSubtreeStats(n).linkDensity()
+				text := nodeText(n)
+				length := len(text)
+				if length > thresholds.ParagraphLength && ld < thresholds.MaxLinkDensity {
+					kept, code = true, ReasonLongParagraph
+				} else if length > 0 && length < thresholds.ParagraphLength && ld == 0 &&
+					patterns.SentenceEnd.FindString(text) != "" {
+					kept, code = true, ReasonShortSentence
+				}
+			}
+		}
+		exp.addSibling(newMySelection(s), score, kept, code)
+		if kept {
+			c := s.Clone()
+			switch n.Data {
+			case "div", "article", "section", "p":
+			default:
+				c.Get(0).Data = "div"
+			}
+			root.AppendSelection(c)
+		}
+	}
+	return output
+}
+
+// singleChild returns the only element child of n if it is tag and n has no text
+// outside of it, or nil.
+func singleChild(n *html.Node, tag string) *html.Node {
+	var only *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.ElementNode && only == nil:
+			only = c
+		case c.Type == html.ElementNode, c.Type == html.TextNode && strings.TrimSpace(c.Data) != "":
+			return nil
+		}
+	}
+	if only == nil || only.Data != tag {
+		return nil
+	}
+	return only
+}
+
+// elementCount returns the number of element children of n.
+func elementCount(n *html.Node) int {
+	count := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			count++
+		}
+	}
+	return count
+}
+
+// hasAncestor returns true if n has an ancestor element of tag.
+func hasAncestor(n *html.Node, tag string) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNodeAttr returns true if n has the attribute key.
+func hasNodeAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ancestors returns the ancestors of n, the parent first.
+func ancestors(n *html.Node) []*html.Node {
+	var as []*html.Node
+	for p := n.Parent; p != nil; p = p.Parent {
+		as = append(as, p)
+	}
+	return as
+}
+
+// removeElements removes the descendant elements of n of tags.
+func removeElements(n *html.Node, tags map[string]bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && tags[c.Data] {
+			n.RemoveChild(c)
+		} else {
+			removeElements(c, tags)
+		}
+		c = next
+	}
+}
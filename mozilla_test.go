@@ -0,0 +1,133 @@
+package readability
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+var modernPage = `<html><head><title>Modern</title></head><body>
+<nav role="navigation"><a href="/">Home</a> <a href="/news">News</a></nav>
+<main>
+<div class="story">
+<section><div>` + strings.Repeat("The council approved the new budget on Monday, after months of debate over the schools. ", 3) + `</div></section>
+<section><p>` + strings.Repeat("Parents had asked for more teachers, and the final plan adds forty positions across the city. ", 3) + `</p></section>
+<section><p>` + strings.Repeat("The mayor said the plan balances the needs of the schools with those of the roads and parks. ", 3) + `</p></section>
+<section><p>` + strings.Repeat("Opponents argued the budget relies on optimistic forecasts of tax revenue for the next years. ", 3) + `</p></section>
+</div>
+<div class="newsletter" style="display: none"><p>` + strings.Repeat("Subscribe to our newsletter, and get the news in your inbox every morning. ", 3) + `</p></div>
+<aside class="sidebar"><p>Most read: <a href="/a">One</a>, <a href="/b">Two</a></p></aside>
+</main>
+</body></html>`
+
+func TestMozillaPrepare(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body>
+<div hidden><p>hidden</p></div>
+<div aria-hidden="true"><p>hidden</p></div>
+<div class="comments"><p>comments</p></div>
+<div class="main-comments"><p>kept by the maybe pattern</p></div>
+<div id="wrap"><p>only paragraph</p></div>
+<div id="text">inline <b>text</b></div>
+<div id="blocks"><ul><li>item</li></ul></div>
+<section></section>
+<script>var a;</script>
+</body>`))
+	body := doc.Find("body").Get(0)
+	toScore := mozillaPrepare(context.Background(), body, NewOption())
+
+	text := doc.Find("body").Text()
+	assert.NotContains(t, text, "hidden")
+	assert.NotContains(t, text, "comments\n")
+	assert.Contains(t, text, "kept by the maybe pattern")
+	assert.Equal(t, 0, doc.Find("#wrap, section, script").Length())
+	// the div of a single paragraph is replaced by it, and the div of inline text becomes a paragraph
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#text")))
+	assert.Equal(t, "div", goquery.NodeName(doc.Find("#blocks")))
+	var scored []string
+	for _, n := range toScore {
+		scored = append(scored, strings.TrimSpace(nodeText(n)))
+	}
+	assert.Equal(t, []string{"kept by the maybe pattern", "only paragraph", "inline text"}, scored)
+
+	// unlikely candidates are kept without RemoveUnlikelyCandidates
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<body><div class="comments"><p>comments</p></div></body>`))
+	opt := NewOption()
+	opt.RemoveUnlikelyCandidates = false
+	mozillaPrepare(context.Background(), doc.Find("body").Get(0), opt)
+	assert.Contains(t, doc.Find("body").Text(), "comments")
+}
+
+func TestMozillaInitialScore(t *testing.T) {
+	node := func(tag, class string) *html.Node {
+		n := &html.Node{Type: html.ElementNode, Data: tag}
+		if class != "" {
+			n.Attr = []html.Attribute{{Key: "class", Val: class}}
+		}
+		return n
+	}
+	opt := NewOption()
+	assert.Equal(t, 5.0, mozillaInitialScore(node("div", ""), opt))
+	assert.Equal(t, 3.0, mozillaInitialScore(node("td", ""), opt))
+	assert.Equal(t, -3.0, mozillaInitialScore(node("li", ""), opt))
+	assert.Equal(t, -5.0, mozillaInitialScore(node("h2", ""), opt))
+	assert.Equal(t, 30.0, mozillaInitialScore(node("div", "article"), opt))
+	assert.Equal(t, -20.0, mozillaInitialScore(node("div", "sidebar"), opt))
+	opt.WeightClasses = false
+	assert.Equal(t, 5.0, mozillaInitialScore(node("div", "sidebar"), opt))
+}
+
+func TestMozillaArticle(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(modernPage))
+	opt := benchOption()
+	opt.Engine = EngineMozilla
+	c, err := ExtractFromDocument(doc, "http://example.com/news/budget", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, EngineMozilla, c.Engine)
+	assert.Equal(t, DescriptionSourceReadability, c.DescriptionSource)
+	// the top candidates are merged into the story sharing them
+	assert.True(t, strings.HasPrefix(c.Description, "The council approved"), c.Description)
+	assert.Contains(t, c.Description, "Opponents argued")
+	assert.NotContains(t, c.Description, "newsletter")
+	assert.NotContains(t, c.Description, "Most read")
+	assert.NotContains(t, c.Description, "Home")
+	// the engine works on copies
+	assert.Equal(t, 1, doc.Find("nav").Length())
+
+	empty, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><a href="/">Home</a></body>`))
+	// without candidates, the whole body is the article of the longest pass
+	a := mozillaArticle(empty, "http://example.com/", opt)
+	assert.Equal(t, EngineMozilla, a.engine)
+	assert.Equal(t, "Home", a.description)
+}
+
+func TestMozillaPrepareDeepNesting(t *testing.T) {
+	depth := 5000
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(strings.Repeat("<div><span>", depth) + "text" + strings.Repeat("</span></div>", depth)))
+	mozillaPrepare(context.Background(), doc.Find("body").Get(0), NewOption())
+	// only the innermost div has no block descendant
+	assert.Equal(t, depth-1, doc.Find("div").Length())
+	assert.Equal(t, 1, doc.Find("p").Length())
+
+	// blocks without text are removed from the inside out
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(strings.Repeat("<div><section>", depth) + "<script>var a;</script>" + strings.Repeat("</section></div>", depth)))
+	mozillaPrepare(context.Background(), doc.Find("body").Get(0), NewOption())
+	assert.Equal(t, 0, doc.Find("div, section").Length())
+}
+
+func TestMozillaTimeout(t *testing.T) {
+	page := `<html><head><title>Modern</title></head><body>` + strings.Repeat(`<div class="story"><section><p>`+
+		strings.Repeat("The council approved the new budget on Monday, after months of debate. ", 3)+`</p></section></div>`, 2000) + `</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	opt := benchOption()
+	opt.Engine = EngineMozilla
+	opt.DescriptionExtractionTimeout = 1
+	c, err := ExtractFromDocument(doc, "http://example.com/news/budget", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusTimedOut, c.Completeness.Description)
+	assert.Equal(t, "", c.Description)
+	assert.Equal(t, "Modern", c.Title)
+}
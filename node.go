@@ -0,0 +1,28 @@
+package readability
+
+import (
+	"errors"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ErrNotDocument is returned by ExtractFromNode if the root is not a document node.
+var ErrNotDocument = errors.New("root is not a document node")
+
+// ExtractFromNode acts same as ExtractFromDocument for root, the document node of a tree parsed
+// by html.Parse, for pipelines working on *html.Node. The tree is wrapped in a goquery.Document,
+// not copied or rendered and parsed again, and is not modified unless Option.ModifyDocument is set.
+// The article is returned as a tree too by Content.ArticleNode.
+//
+// It is a convenience, not a goquery-free API: the extraction is the goquery-based
+// pipeline of ExtractFromDocument.
+//
+// Fragments parsed by html.ParseFragment are not documents; append them to
+// the <body> of a document node first.
+func ExtractFromNode(root *html.Node, reqURL string, opt *Option) (*Content, error) {
+	if root == nil || root.Type != html.DocumentNode {
+		return nil, ErrNotDocument
+	}
+	return ExtractFromDocument(goquery.NewDocumentFromNode(root), reqURL, opt)
+}
@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+func TestExtractFromNode(t *testing.T) {
+	page := `<html><head><title>Title</title></head><body><article>
+<p>A long enough paragraph of the article, which is written as sentences to be extracted.</p>
+</article></body></html>`
+	root, err := html.Parse(strings.NewReader(page))
+	assert.Nil(t, err)
+	opt := benchOption()
+	c, err := ExtractFromNode(root, "https://example.com/a", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", c.Title)
+	assert.Contains(t, c.Description, "A long enough paragraph")
+	if assert.NotNil(t, c.ArticleNode()) {
+		assert.Equal(t, html.DocumentNode, c.ArticleNode().Type)
+	}
+
+	// the tree is not modified
+	var buf strings.Builder
+	html.Render(&buf, root)
+	again, _ := html.Parse(strings.NewReader(page))
+	var want strings.Builder
+	html.Render(&want, again)
+	assert.Equal(t, want.String(), buf.String())
+
+	_, err = ExtractFromNode(nil, "https://example.com/a", opt)
+	assert.Equal(t, ErrNotDocument, err)
+	body := root.FirstChild.LastChild
+	_, err = ExtractFromNode(body, "https://example.com/a", opt)
+	assert.Equal(t, ErrNotDocument, err)
+}
@@ -14,17 +14,18 @@ type OpenGraph struct {
 }
 
 // Set sets value to the key-related field.
+// Entities left in the title and the description by double encoding are decoded.
 func (og *OpenGraph) Set(key string, val string, urlStr string) error {
 	switch key {
 	case "og:title":
-		og.Title = val
+		og.Title = decodeEntities(val)
 	case "og:description":
-		og.Description = val
+		og.Description = decodeEntities(val)
 	case "og:image":
 		var err error
 		og.ImageURL, err = absPath(val, urlStr)
 		if err != nil {
-			logger.Printf("OpenGraph.Set failed: %v", err)
+			logger.Warnf("OpenGraph.Set failed: %v", err)
 		}
 	default:
 		return fmt.Errorf("Invalid key for OpenGraph.Set: %v", key)
@@ -1,6 +1,7 @@
 package readability
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
@@ -34,3 +35,12 @@ func TestGetContentFromOpenGraphForPageWithoutOGTags(t *testing.T) {
 	assert.Equal(t, "", c.Description)
 	assert.Equal(t, "", c.ImageURL)
 }
+
+func TestOpenGraphDecodesDoubleEncodedEntities(t *testing.T) {
+	html := `<meta property="og:title" content="Rock &amp;amp; Roll"><meta property="og:description" content="It&amp;#8217;s &lt;b&gt;back&lt;/b&gt;">`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err := getContentFromOpenGraph(doc, "https://example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "Rock & Roll", c.Title)
+	assert.Equal(t, "It’s <b>back</b>", c.Description)
+}
@@ -0,0 +1,150 @@
+package readability
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Upper bounds of option values. Extraction clamps values greater than them on its own copy of the option.
+const (
+	maxTimeout             = 60000
+	maxCheckImageLoopCount = 1000
+	maxImageCount          = 100
+)
+
+// Validate returns an error describing every nonsensical value of o, such as zero timeouts
+// or negative counts, which would otherwise make extraction hang or fail silently.
+// o is not modified, so a shared Option can be validated concurrently. Too large values
+// (such as timeouts over a minute) are valid, and clamped to safe ranges by extraction
+// on its own copy of o.
+func (o *Option) Validate() error {
+	if o == nil {
+		return errors.New("invalid option: nil")
+	}
+
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("invalid option: "+format, args...))
+	}
+	if o.RetryLength < 0 {
+		invalid("RetryLength must not be negative: %v", o.RetryLength)
+	}
+	if o.MinTextLength < 0 {
+		invalid("MinTextLength must not be negative: %v", o.MinTextLength)
+	}
+	if o.MaxImageCount < 0 {
+		invalid("MaxImageCount must not be negative: %v", o.MaxImageCount)
+	}
+	if o.ImageRequestTimeout == 0 {
+		invalid("ImageRequestTimeout must be greater than 0")
+	}
+	if o.CheckImageLoopCount == 0 && !o.SkipImageProbing {
+		invalid("CheckImageLoopCount must be greater than 0 unless SkipImageProbing is set")
+	}
+	if o.MaxHTMLSize < 0 {
+		invalid("MaxHTMLSize must not be negative: %v", o.MaxHTMLSize)
+	}
+	if o.MaxImageBytes < 0 {
+		invalid("MaxImageBytes must not be negative: %v", o.MaxImageBytes)
+	}
+	if o.MaxRedirects < 0 {
+		invalid("MaxRedirects must not be negative: %v", o.MaxRedirects)
+	}
+	if o.ImageFetchConcurrency < 0 {
+		invalid("ImageFetchConcurrency must not be negative: %v", o.ImageFetchConcurrency)
+	}
+	if o.ImageMaxRedirects < 0 {
+		invalid("ImageMaxRedirects must not be negative: %v", o.ImageMaxRedirects)
+	}
+	if o.DescriptionExtractionTimeout == 0 {
+		invalid("DescriptionExtractionTimeout must be greater than 0")
+	}
+	if o.RerankTopN < 0 {
+		invalid("RerankTopN must not be negative: %v", o.RerankTopN)
+	}
+	if o.CandidateCount < 0 {
+		invalid("CandidateCount must not be negative: %v", o.CandidateCount)
+	}
+	for i, f := range o.IgnoreImageFormat {
+		if f == "" {
+			invalid("IgnoreImageFormat[%v] is empty, which ignores all images", i)
+		}
+	}
+	for code := range o.CleanRules {
+		if !isCleanRule(code) {
+			invalid("CleanRules has an unknown rule: %q", code)
+		}
+	}
+	for i, f := range o.OnlyImageFormats {
+		if f == "" {
+			invalid("OnlyImageFormats[%v] is empty, which keeps all images", i)
+		}
+	}
+	switch o.SortImagesBy {
+	case "", ImageOrderDocument, ImageOrderArea, ImageOrderScore:
+	default:
+		invalid("SortImagesBy is unknown: %q", o.SortImagesBy)
+	}
+	if o.Engine != "" && !isBuiltinEngine(o.Engine) && registeredEngine(o.Engine) == nil {
+		invalid("Engine is unknown: %q", o.Engine)
+	}
+	switch o.CharsetPolicy {
+	case "", CharsetPolicyAuto, CharsetPolicyHeader, CharsetPolicyMeta:
+	default:
+		invalid("CharsetPolicy is unknown: %q", o.CharsetPolicy)
+	}
+	if p := o.HTMLPolicy; p != nil {
+		switch p.URLs {
+		case URLRewriteNone, URLRewriteAbsolute, URLRewriteRelative:
+		default:
+			invalid("HTMLPolicy.URLs is unknown: %q", p.URLs)
+		}
+	}
+	if t := o.SiblingThresholds; t != nil {
+		if t.MinScore < 0 || t.ScoreRatio < 0 || t.ParagraphLength < 0 || t.MaxLinkDensity < 0 {
+			invalid("SiblingThresholds must not be negative: %+v", *t)
+		}
+	}
+	if _, ok := profiles[o.Profile]; o.Profile != "" && !ok {
+		invalid("Profile is unknown: %q", o.Profile)
+	}
+	for domain, srcs := range o.ExcludedDescriptionSources {
+		for i, src := range srcs {
+			if !isKnownDescriptionSource(src) {
+				invalid("ExcludedDescriptionSources[%q][%v] is unknown: %q", domain, i, src)
+			}
+		}
+	}
+	for i, src := range o.PrimaryImageSources {
+		if !isKnownPrimaryImageSource(src) {
+			invalid("PrimaryImageSources[%v] is unknown: %q", i, src)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// clamp clamps too large values of o to safe ranges. o must be a copy made by copyOption,
+// never the Option of the caller.
+func (o *Option) clamp() {
+	for _, t := range []*uint{&o.ImageRequestTimeout, &o.ImageProbingTimeout,
+		&o.DescriptionExtractionTimeout, &o.MetadataTimeout} {
+		if *t > maxTimeout {
+			*t = maxTimeout
+		}
+	}
+	if o.CheckImageLoopCount > maxCheckImageLoopCount {
+		o.CheckImageLoopCount = maxCheckImageLoopCount
+	}
+	if o.MaxImageCount > maxImageCount {
+		o.MaxImageCount = maxImageCount
+	}
+}
+
+func isKnownPrimaryImageSource(src ImageSource) bool {
+	for _, s := range DefaultPrimaryImageSources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionValidate(t *testing.T) {
+	assert.Nil(t, NewOption().Validate())
+
+	var nilOpt *Option
+	assert.NotNil(t, nilOpt.Validate())
+
+	opt := NewOption()
+	opt.ImageRequestTimeout = 0
+	opt.DescriptionExtractionTimeout = 0
+	opt.MaxImageCount = -1
+	opt.IgnoreImageFormat = []string{".svg", ""}
+	opt.PrimaryImageSources = []ImageSource{"unknown"}
+	opt.SortImagesBy = "random"
+	opt.CharsetPolicy = "guess"
+	opt.ImageMaxRedirects = -1
+	opt.Profile = "wiki"
+	opt.CheckImageLoopCount = 0
+	err := opt.Validate()
+	assert.NotNil(t, err)
+	for _, s := range []string{"ImageRequestTimeout", "DescriptionExtractionTimeout", "MaxImageCount", "CheckImageLoopCount", "IgnoreImageFormat[1]", "PrimaryImageSources[0]", "SortImagesBy", "CharsetPolicy", "ImageMaxRedirects", "Profile"} {
+		assert.True(t, strings.Contains(err.Error(), s), s)
+	}
+
+	opt = NewOption()
+	opt.CheckImageLoopCount = 0
+	opt.SkipImageProbing = true
+	assert.Nil(t, opt.Validate())
+
+	// too large values are valid, and clamped only on a copy
+	opt = NewOption()
+	opt.ImageRequestTimeout = 1000000
+	opt.ImageProbingTimeout = 1000000
+	opt.MetadataTimeout = 1000000
+	opt.CheckImageLoopCount = 1000000
+	opt.MaxImageCount = 1000000
+	assert.Nil(t, opt.Validate())
+	assert.Equal(t, uint(1000000), opt.ImageRequestTimeout)
+	assert.Equal(t, 1000000, opt.MaxImageCount)
+	clamped := copyOption(opt)
+	clamped.clamp()
+	assert.Equal(t, uint(maxTimeout), clamped.ImageRequestTimeout)
+	assert.Equal(t, uint(maxTimeout), clamped.ImageProbingTimeout)
+	assert.Equal(t, uint(maxTimeout), clamped.MetadataTimeout)
+	assert.Equal(t, uint(maxCheckImageLoopCount), clamped.CheckImageLoopCount)
+	assert.Equal(t, maxImageCount, clamped.MaxImageCount)
+	assert.Equal(t, uint(1000000), opt.CheckImageLoopCount)
+}
+
+func TestExtractFromDocumentValidatesOption(t *testing.T) {
+	opt := NewOption()
+	opt.DescriptionExtractionTimeout = 0
+	_, err := ExtractFromDocument(nil, "http://example.com", opt)
+	assert.NotNil(t, err)
+}
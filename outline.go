@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Heading is a heading of the article, for rendering a table of contents.
+type Heading struct {
+	// Level is 1 for <h1> to 6 for <h6>.
+	Level int `json:"level"`
+
+	Text string `json:"text"`
+
+	// Anchor is the fragment identifier linking to the heading in the page, like "history"
+	// for "https://example.com/article#history", or empty if the heading has no id.
+	Anchor string `json:"anchor,omitempty"`
+}
+
+// outline returns the headings in s in document order, without empty ones.
+func outline(s *goquery.Selection) []Heading {
+	var hs []Heading
+	s.Find("h1, h2, h3, h4, h5, h6").Each(func(_ int, h *goquery.Selection) {
+		text := plainText(h)
+		if text == "" {
+			return
+		}
+		hs = append(hs, Heading{
+			Level:  int(goquery.NodeName(h)[1] - '0'),
+			Text:   text,
+			Anchor: headingAnchor(h),
+		})
+	})
+	return hs
+}
+
+// headingAnchor returns the id of h, or of an element inside h like <span id="history">,
+// or the name of an <a name> inside h.
+func headingAnchor(h *goquery.Selection) string {
+	if id := strings.TrimSpace(h.AttrOr("id", "")); id != "" {
+		return id
+	}
+	if id := strings.TrimSpace(h.Find("[id]").First().AttrOr("id", "")); id != "" {
+		return id
+	}
+	return strings.TrimSpace(h.Find("a[name]").First().AttrOr("name", ""))
+}
@@ -0,0 +1,40 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutline(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div>
+<h1 id="top">Title</h1>
+<h2><span class="mw-headline" id="History">History</span></h2>
+<h3><a name="early"></a>Early  years</h3>
+<h2> </h2>
+<h4>Notes</h4>
+</div>`))
+	assert.Equal(t, []Heading{
+		{Level: 1, Text: "Title", Anchor: "top"},
+		{Level: 2, Text: "History", Anchor: "History"},
+		{Level: 3, Text: "Early years", Anchor: "early"},
+		{Level: 4, Text: "Notes"},
+	}, outline(doc.Selection))
+}
+
+func TestExtractFromDocumentOutline(t *testing.T) {
+	p := "<p>" + strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor. ", 4) + "</p>"
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Guide</title></head><body>
+<nav><h2>Menu</h2></nav>
+<div class="content"><h2 id="setup">Setup</h2>` + p + p + `<h3 id="config">Configuration</h3>` + p + `</div></body></html>`))
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	c, err := ExtractFromDocument(doc, "https://example.com/guide", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, []Heading{
+		{Level: 2, Text: "Setup", Anchor: "setup"},
+		{Level: 3, Text: "Configuration", Anchor: "config"},
+	}, c.Outline)
+}
@@ -0,0 +1,66 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// paywallClass matches class and id of paywall overlays and locked content,
+	// like "paywall", "tp-modal" (Piano) or "subscriber-only".
+	paywallClass = regexp.MustCompile(`(?i)paywall|regwall|subscriber[-_]?only|premium[-_]?content|locked[-_]?content|content[-_]?gate|tp-modal|piano[-_]?(inline|offer|template)|meter[-_]?(wall|barrier)`)
+
+	// paywallPrompt matches prompts to subscribe or sign in to read the rest of an article.
+	paywallPrompt = regexp.MustCompile(`(?i)subscribe (now )?to (continue|keep) reading|continue reading,? (please )?(subscribe|sign in|log in)|already (a )?subscriber\?|(article|story|content) is (only )?(available|reserved|exclusive) (to|for) (paid )?(subscribers|members)|subscribers only|subscribe to (read|unlock)|(sign|log) in to (read|continue reading)`)
+)
+
+// paywallMaxLength is the maximum length (runes) of a description considered truncated
+// by a paywall when the page prompts to subscribe.
+const paywallMaxLength = 1000
+
+// paywallSignals returns whether doc is marked as paywalled, by JSON-LD isAccessibleForFree
+// set to false or by an element with a paywall class or id, and whether doc prompts to subscribe.
+// doc must be read before the extraction removes nodes from it.
+func paywallSignals(doc *goquery.Document) (marked, prompted bool) {
+	for _, obj := range jsonLDObjects(doc) {
+		if isNotFree(obj["isAccessibleForFree"]) {
+			return true, false
+		}
+		if parts, ok := obj["hasPart"]; ok {
+			for _, p := range appendJSONLDObjects(nil, parts) {
+				if isNotFree(p["isAccessibleForFree"]) {
+					return true, false
+				}
+			}
+		}
+	}
+
+	body := doc.Find("body")
+	body.Find("[class], [id]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		marked = paywallClass.MatchString(s.AttrOr("class", "") + " " + s.AttrOr("id", ""))
+		return !marked
+	})
+	if marked {
+		return true, false
+	}
+	return false, paywallPrompt.MatchString(body.Text())
+}
+
+// isNotFree returns true if v, a value of isAccessibleForFree, is false or "False".
+func isNotFree(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return !t
+	case string:
+		return strings.EqualFold(strings.TrimSpace(t), "false")
+	}
+	return false
+}
+
+// isTruncated returns true if the description of c is shorter than paywallMaxLength.
+func isTruncated(c *Content, opt *Option) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(descriptionText(c, opt))) < paywallMaxLength
+}
@@ -0,0 +1,44 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaywallSignals(t *testing.T) {
+	for _, tc := range []struct {
+		html             string
+		marked, prompted bool
+	}{
+		{`<script type="application/ld+json">{"@type": "NewsArticle", "isAccessibleForFree": "False"}</script>`, true, false},
+		{`<script type="application/ld+json">{"@type": "NewsArticle", "isAccessibleForFree": false}</script>`, true, false},
+		{`<script type="application/ld+json">{"@type": "NewsArticle", "hasPart": {"@type": "WebPageElement", "isAccessibleForFree": false, "cssSelector": ".locked"}}</script>`, true, false},
+		{`<script type="application/ld+json">{"@type": "NewsArticle", "isAccessibleForFree": true}</script>`, false, false},
+		{`<body><div class="article"><p>Text</p></div><div id="tp-modal"></div></body>`, true, false},
+		{`<body><p>Text</p><p>Subscribe to continue reading.</p></body>`, false, true},
+		{`<body><p>Text</p><p>Subscribe to our newsletter.</p></body>`, false, false},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		marked, prompted := paywallSignals(doc)
+		assert.Equal(t, tc.marked, marked, tc.html)
+		assert.Equal(t, tc.prompted, prompted, tc.html)
+	}
+}
+
+func TestExtractFromDocumentPaywalled(t *testing.T) {
+	p := "<p>" + strings.Repeat("The council approved the new budget after a long debate on Tuesday. ", 5) + "</p>"
+	extract := func(body string) *Content {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Budget</title></head><body><div class="article">` + body + `</div></body></html>`))
+		opt := NewOption()
+		opt.ImageRequestTimeout = 10
+		c, err := ExtractFromDocument(doc, "https://example.com/budget", opt)
+		assert.Nil(t, err)
+		return c
+	}
+	assert.True(t, extract(p+`<div class="cta">Already a subscriber? Sign in to read the full story.</div>`).Paywalled)
+	assert.False(t, extract(strings.Repeat(p, 5)+`<div class="cta">Already a subscriber? Sign in.</div>`).Paywalled)
+	assert.False(t, extract(p).Paywalled)
+}
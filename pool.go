@@ -0,0 +1,56 @@
+package readability
+
+import (
+	"bytes"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// maxPooledBufferSize is the maximum capacity of buffers kept in the pools,
+// so that a single huge page doesn't pin its memory for the process lifetime.
+const maxPooledBufferSize = 256 * 1024
+
+// bufferPool reuses buffers for serializing nodes and collecting their text,
+// which are otherwise allocated for every candidate and every conditionally cleaned node.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// textBuilderPool reuses textBuilders of plainText and paragraphs.
+var textBuilderPool = sync.Pool{New: func() any { return &textBuilder{} }}
+
+func getTextBuilder() *textBuilder {
+	tb := textBuilderPool.Get().(*textBuilder)
+	tb.reset()
+	return tb
+}
+
+func putTextBuilder(tb *textBuilder) {
+	if cap(tb.buf) <= maxPooledBufferSize {
+		textBuilderPool.Put(tb)
+	}
+}
+
+// innerHTML returns the serialized children of n, same as Html of goquery.Selection.
+// "" is returned if rendering fails.
+func innerHTML(n *html.Node) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(buf, c); err != nil {
+			return ""
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,44 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInnerHTML(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	doc.Find("div, p").Each(func(_ int, s *goquery.Selection) {
+		want, _ := s.Html()
+		assert.Equal(t, want, innerHTML(s.Get(0)))
+	})
+}
+
+func TestMySelectionHTML(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div id="a"><p>one</p></div>`))
+	sel := newMySelection(doc.Find("#a"))
+	assert.Equal(t, "<p>one</p>", sel.HTML())
+	assert.Equal(t, "", newMySelection(doc.Find("#b")).HTML())
+}
+
+func TestPooledTextIsNotShared(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<p>first paragraph</p><p>second</p>`))
+	a := plainText(doc.Find("p").First())
+	b := plainText(doc.Find("p").Last())
+	assert.Equal(t, "first paragraph", a)
+	assert.Equal(t, "second", b)
+	assert.Equal(t, []string{"first paragraph", "second"}, paragraphs(doc.Selection))
+}
+
+func BenchmarkExtractArticle(b *testing.B) {
+	opt := NewOption()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+		b.StartTimer()
+		extractArticle(doc, "", opt)
+	}
+}
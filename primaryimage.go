@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/philipjkim/fastimage"
+)
+
+// DefaultPrimaryImageSources is the default fallback order of Option.PrimaryImageSources.
+var DefaultPrimaryImageSources = []ImageSource{
+	ImageSourceOpenGraph,
+	ImageSourceMetadata,
+	ImageSourceArticle,
+	ImageSourceVideoEmbed,
+	ImageSourceFavicon,
+}
+
+// primaryImage returns the first image found in opt.PrimaryImageSources order, or nil.
+// articleImages is called only if ImageSourceArticle is reached.
+// videos are the thumbnails of embedded videos, which are empty unless Option.VideoThumbnails is set.
+func primaryImage(doc *goquery.Document, reqURL string, og *OpenGraph, md *Metadata,
+	articleImages func() []Image, videos []Image, opt *Option) *Image {
+	for _, src := range opt.PrimaryImageSources {
+		var u string
+		switch src {
+		case ImageSourceOpenGraph:
+			u = og.ImageURL
+		case ImageSourceMetadata:
+			u = md.ImageURL
+		case ImageSourceArticle:
+			if imgs := articleImages(); len(imgs) > 0 {
+				img := imgs[0]
+				return &img
+			}
+		case ImageSourceVideoEmbed:
+			if len(videos) > 0 {
+				img := videos[0]
+				return &img
+			}
+		case ImageSourceFavicon:
+			u = faviconURL(doc, reqURL)
+		}
+		if u != "" {
+			size := &fastimage.ImageSize{Width: 0, Height: 0}
+			if src == ImageSourceOpenGraph && opt.SkipImageProbing {
+				size = openGraphImageSize(doc)
+			}
+			return &Image{URL: u, Size: size, Source: src}
+		}
+	}
+	return nil
+}
+
+// faviconURL returns the absolute URL of the site icon, preferring apple-touch-icon
+// since it is usually larger. It falls back to /favicon.ico of the request host.
+func faviconURL(doc *goquery.Document, reqURL string) string {
+	for _, sel := range []string{
+		`link[rel~="apple-touch-icon"]`,
+		`link[rel~="apple-touch-icon-precomposed"]`,
+		`link[rel~="icon"]`,
+	} {
+		if href, ok := doc.Find(sel).First().Attr("href"); ok {
+			if u, err := absPath(href, reqURL); err == nil {
+				return u
+			}
+		}
+	}
+	u, err := absPath("/favicon.ico", reqURL)
+	if err != nil {
+		return ""
+	}
+	return u
+}
@@ -0,0 +1,46 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimaryImage(t *testing.T) {
+	html := `<html><head>
+<meta property="og:title" content="Title" />
+<link rel="icon" href="/static/icon.png">
+</head><body><img src="/a.jpg" width="640" height="480"></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	c, err := ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.com/a.jpg", c.PrimaryImage.URL)
+	assert.Equal(t, uint32(640), c.PrimaryImage.Size.Width)
+	assert.Equal(t, ImageSourceArticle, c.PrimaryImage.Source)
+	assert.Equal(t, 1, len(c.Images))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt.PrimaryImageSources = []ImageSource{ImageSourceOpenGraph, ImageSourceFavicon}
+	c, _ = ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Equal(t, "http://example.com/static/icon.png", c.PrimaryImage.URL)
+	assert.Equal(t, ImageSourceFavicon, c.PrimaryImage.Source)
+	assert.Empty(t, c.Images)
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt.PrimaryImageSources = nil
+	c, _ = ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Nil(t, c.PrimaryImage)
+}
+
+func TestFaviconURL(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>
+<link rel="shortcut icon" href="/favicon.png"><link rel="apple-touch-icon" href="//cdn.example.com/touch.png">
+</head></html>`))
+	assert.Equal(t, "https://cdn.example.com/touch.png", faviconURL(doc, "https://example.com/a"))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<html></html>`))
+	assert.Equal(t, "https://example.com/favicon.ico", faviconURL(doc, "https://example.com/a/b"))
+}
@@ -0,0 +1,227 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Availability is the availability of a product, named as the schema.org ItemAvailability values.
+type Availability string
+
+// Product availabilities.
+const (
+	AvailabilityInStock      Availability = "InStock"
+	AvailabilityOutOfStock   Availability = "OutOfStock"
+	AvailabilityPreOrder     Availability = "PreOrder"
+	AvailabilityBackOrder    Availability = "BackOrder"
+	AvailabilityDiscontinued Availability = "Discontinued"
+	AvailabilityLimited      Availability = "LimitedAvailability"
+)
+
+// Product is a product declared by a page, for e-commerce link previews.
+type Product struct {
+	Name  string `json:"name,omitempty"`
+	Brand string `json:"brand,omitempty"`
+
+	// Price is the price declared by the page, as is, like "19.99".
+	// For offers with a price range, it is the lowest price.
+	Price string `json:"price,omitempty"`
+
+	// Currency is the ISO 4217 currency code of Price, like "USD".
+	Currency string `json:"currency,omitempty"`
+
+	Availability Availability `json:"availability,omitempty"`
+
+	// Images contains the absolute URLs of the product images.
+	Images []string `json:"images,omitempty"`
+}
+
+// availabilities maps lowercased schema.org ItemAvailability names and og:product availabilities
+// to Availability.
+var availabilities = map[string]Availability{
+	"instock":             AvailabilityInStock,
+	"in stock":            AvailabilityInStock,
+	"instoreonly":         AvailabilityInStock,
+	"onlineonly":          AvailabilityInStock,
+	"available for order": AvailabilityInStock,
+	"outofstock":          AvailabilityOutOfStock,
+	"out of stock":        AvailabilityOutOfStock,
+	"soldout":             AvailabilityOutOfStock,
+	"preorder":            AvailabilityPreOrder,
+	"presale":             AvailabilityPreOrder,
+	"backorder":           AvailabilityBackOrder,
+	"discontinued":        AvailabilityDiscontinued,
+	"limitedavailability": AvailabilityLimited,
+}
+
+// availability returns the Availability of v, which can be a schema.org URL like
+// "https://schema.org/InStock" or an og:product value like "in stock", or "" if it is unknown.
+func availability(v string) Availability {
+	return availabilities[schemaTypeName(v)]
+}
+
+// merge fills empty fields of p with values of other.
+func (p *Product) merge(other *Product) {
+	p.Name = firstNonEmpty(p.Name, other.Name)
+	p.Brand = firstNonEmpty(p.Brand, other.Brand)
+	if p.Price == "" {
+		p.Price, p.Currency = other.Price, other.Currency
+	}
+	p.Currency = firstNonEmpty(p.Currency, other.Currency)
+	if p.Availability == "" {
+		p.Availability = other.Availability
+	}
+	if len(p.Images) == 0 {
+		p.Images = other.Images
+	}
+}
+
+func (p *Product) isEmpty() bool {
+	return p.Name == "" && p.Price == "" && p.Availability == ""
+}
+
+// product returns the product of doc declared by JSON-LD, falling back to microdata and
+// og:product tags for missing values, or nil if doc has no product.
+func product(doc *goquery.Document, reqURL string) *Product {
+	p := jsonLDProduct(doc, reqURL)
+	p.merge(microdataProduct(doc, reqURL))
+	p.merge(openGraphProduct(doc, reqURL))
+	if p.isEmpty() {
+		return nil
+	}
+	return p
+}
+
+// jsonLDProduct returns the first JSON-LD Product object.
+func jsonLDProduct(doc *goquery.Document, reqURL string) *Product {
+	for _, obj := range jsonLDObjects(doc) {
+		if !jsonLDType(obj, "Product") {
+			continue
+		}
+		p := &Product{
+			Name:  jsonLDString(obj["name"]),
+			Brand: jsonLDString(obj["brand"]),
+		}
+		offer := jsonLDOffer(obj["offers"])
+		if offer != nil {
+			p.Price = jsonLDValue(firstNonNil(offer["price"], offer["lowPrice"]))
+			p.Currency = jsonLDString(offer["priceCurrency"])
+			if spec, ok := offer["priceSpecification"].(map[string]interface{}); ok && p.Price == "" {
+				p.Price = jsonLDValue(spec["price"])
+				p.Currency = firstNonEmpty(p.Currency, jsonLDString(spec["priceCurrency"]))
+			}
+			p.Availability = availability(jsonLDString(offer["availability"]))
+		}
+		p.Images = productImages(jsonLDImages(obj["image"]), reqURL)
+		return p
+	}
+	return &Product{}
+}
+
+// jsonLDOffer returns the first offer of v, which can be an Offer, an AggregateOffer or an array of them.
+func jsonLDOffer(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			if o := jsonLDOffer(e); o != nil {
+				return o
+			}
+		}
+	case map[string]interface{}:
+		return t
+	}
+	return nil
+}
+
+// jsonLDImages returns all image URLs of v, which can be a URL string,
+// an ImageObject having "url", or an array of them.
+func jsonLDImages(v interface{}) []string {
+	if list, ok := v.([]interface{}); ok {
+		var urls []string
+		for _, e := range list {
+			urls = append(urls, jsonLDImages(e)...)
+		}
+		return urls
+	}
+	if u := jsonLDImage(v); u != "" {
+		return []string{u}
+	}
+	return nil
+}
+
+// microdataProduct returns the first microdata Product item.
+func microdataProduct(doc *goquery.Document, reqURL string) *Product {
+	p := &Product{}
+	doc.Find("[itemscope][itemtype]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		isProduct := false
+		for _, t := range strings.Fields(s.AttrOr("itemtype", "")) {
+			isProduct = isProduct || schemaTypeName(t) == "product"
+		}
+		if !isProduct {
+			return true
+		}
+		props := microdataProps(s)
+		p.Name = microdataValue(props["name"])
+		p.Brand = microdataValue(props["brand"])
+		if offer := props["offers"]; offer != nil {
+			op := microdataProps(offer)
+			p.Price = firstNonEmpty(microdataValue(op["price"]), microdataValue(op["lowPrice"]))
+			if price := op["price"]; price != nil {
+				// prices are often marked up like <span itemprop="price" content="19.99">$19.99</span>
+				if c, ok := price.Attr("content"); ok {
+					p.Price = strings.TrimSpace(c)
+				}
+			}
+			p.Currency = microdataValue(op["priceCurrency"])
+			p.Availability = availability(microdataValue(op["availability"]))
+		}
+		var images []string
+		for _, img := range microdataPropAll(s, "image") {
+			images = append(images, microdataValue(img))
+		}
+		p.Images = productImages(images, reqURL)
+		return false
+	})
+	return p
+}
+
+// openGraphProduct returns the product declared by og:product tags (product:price:amount, ...)
+// if og:type is "product", with og:title and og:image.
+func openGraphProduct(doc *goquery.Document, reqURL string) *Product {
+	if !strings.HasPrefix(strings.ToLower(metaContent(doc, `meta[property="og:type"]`)), "product") {
+		return &Product{}
+	}
+	p := &Product{
+		Name:         metaContent(doc, `meta[property="og:title"]`),
+		Brand:        metaContent(doc, `meta[property="product:brand"], meta[property="og:brand"]`),
+		Price:        metaContent(doc, `meta[property="product:price:amount"], meta[property="og:price:amount"]`),
+		Currency:     metaContent(doc, `meta[property="product:price:currency"], meta[property="og:price:currency"]`),
+		Availability: availability(metaContent(doc, `meta[property="product:availability"], meta[property="og:availability"]`)),
+	}
+	var images []string
+	doc.Find(`meta[property="og:image"], meta[property="og:image:url"]`).Each(func(_ int, s *goquery.Selection) {
+		images = append(images, s.AttrOr("content", ""))
+	})
+	p.Images = productImages(images, reqURL)
+	return p
+}
+
+// productImages returns the deduplicated absolute URLs of images.
+func productImages(images []string, reqURL string) []string {
+	var urls []string
+	seen := map[string]bool{}
+	for _, img := range images {
+		img = strings.TrimSpace(img)
+		if img == "" {
+			continue
+		}
+		u, err := absPath(img, reqURL)
+		if err != nil || seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls
+}
@@ -0,0 +1,67 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductFromJSONLD(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Product", "name": "Trail Shoe",
+ "brand": {"@type": "Brand", "name": "Acme"},
+ "image": ["/img/shoe-1.jpg", {"@type": "ImageObject", "url": "https://cdn.example.com/shoe-2.jpg"}, "/img/shoe-1.jpg"],
+ "offers": [{"@type": "AggregateOffer", "lowPrice": 89.5, "highPrice": 120, "priceCurrency": "EUR",
+   "availability": "https://schema.org/InStock"}]}
+</script>`))
+	assert.Equal(t, &Product{
+		Name:         "Trail Shoe",
+		Brand:        "Acme",
+		Price:        "89.5",
+		Currency:     "EUR",
+		Availability: AvailabilityInStock,
+		Images:       []string{"https://shop.example.com/img/shoe-1.jpg", "https://cdn.example.com/shoe-2.jpg"},
+	}, product(doc, "https://shop.example.com/p/trail-shoe"))
+}
+
+func TestProductFromMicrodata(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div itemscope itemtype="http://schema.org/Product">
+<h1 itemprop="name">Desk Lamp</h1>
+<img itemprop="image" src="/lamp.jpg">
+<div itemprop="offers" itemscope itemtype="http://schema.org/Offer">
+  <span itemprop="price" content="24.00">$24</span>
+  <meta itemprop="priceCurrency" content="USD">
+  <link itemprop="availability" href="http://schema.org/OutOfStock">
+</div>
+</div>`))
+	assert.Equal(t, &Product{
+		Name:         "Desk Lamp",
+		Price:        "24.00",
+		Currency:     "USD",
+		Availability: AvailabilityOutOfStock,
+		Images:       []string{"https://shop.example.com/lamp.jpg"},
+	}, product(doc, "https://shop.example.com/lamp"))
+}
+
+func TestProductFromOpenGraph(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<head>
+<meta property="og:type" content="product">
+<meta property="og:title" content="Coffee Grinder">
+<meta property="og:image" content="https://shop.example.com/grinder.jpg">
+<meta property="product:price:amount" content="59.99">
+<meta property="product:price:currency" content="GBP">
+<meta property="product:availability" content="preorder">
+</head>`))
+	assert.Equal(t, &Product{
+		Name:         "Coffee Grinder",
+		Price:        "59.99",
+		Currency:     "GBP",
+		Availability: AvailabilityPreOrder,
+		Images:       []string{"https://shop.example.com/grinder.jpg"},
+	}, product(doc, "https://shop.example.com/grinder"))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<head><meta property="og:type" content="article"><meta property="og:title" content="News"></head>`))
+	assert.Nil(t, product(doc, "https://example.com/news"))
+}
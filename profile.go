@@ -0,0 +1,144 @@
+package readability
+
+import "regexp"
+
+// Profile is a preset of option values and class patterns tuned for a kind of page.
+type Profile string
+
+// Extraction profiles.
+const (
+	// ProfileNews is tuned for news articles: share bars, newsletter sign-ups and
+	// "most read" lists are removed, and titles are cleaned.
+	ProfileNews Profile = "news"
+
+	// ProfileBlog is tuned for blog posts: author bios and share bars are removed,
+	// and images are collected from the post.
+	ProfileBlog Profile = "blog"
+
+	// ProfileForum is tuned for forum threads: posts, replies and comments are content,
+	// and short posts are kept.
+	ProfileForum Profile = "forum"
+
+	// ProfileProduct is tuned for product pages: descriptions and specifications are content,
+	// reviews and recommendations are removed, and more images are collected, largest first.
+	ProfileProduct Profile = "product"
+
+	// ProfileRecipe is tuned for recipes: ingredients and instructions are content,
+	// and the short items of their lists are kept.
+	ProfileRecipe Profile = "recipe"
+)
+
+// classPatterns decide whether nodes are unlikely candidates by their class and id,
+// and weight them. See isUnlikelyNode and classWeight.
+type classPatterns struct {
+	unlikely *regexp.Regexp
+	maybe    *regexp.Regexp
+	positive *regexp.Regexp
+	negative *regexp.Regexp
+}
+
+func (p *classPatterns) all() []*regexp.Regexp {
+	return []*regexp.Regexp{p.unlikely, p.maybe, p.positive, p.negative}
+}
+
+var defaultClassPatterns = &classPatterns{
+	unlikely: patterns.UnlikelyCandidates,
+	maybe:    patterns.OKMaybeItsACandidate,
+	positive: patterns.Positive,
+	negative: patterns.Negative,
+}
+
+type profile struct {
+	patterns *classPatterns
+
+	// tune sets the option values of the profile.
+	tune func(o *Option)
+}
+
+var profiles = map[Profile]*profile{
+	ProfileNews: {
+		patterns: &classPatterns{
+			unlikely: regexp.MustCompile("(?i)combx|comment|community|disqus|extra|foot|header|menu|remark|rss|shoutbox|sidebar|sponsor|ad-break|agegate|pagination|pager|popup|newsletter|subscribe|share|social|most-read|trending|taboola|outbrain"),
+			maybe:    patterns.OKMaybeItsACandidate,
+			positive: regexp.MustCompile("(?i)article|body|content|entry|hentry|main|page|pagination|post|text|blog|story|headline|lede"),
+			negative: regexp.MustCompile("(?i)combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|shoutbox|sidebar|sponsor|shopping|tags|tool|widget|newsletter|subscribe|share|social|trending|taboola"),
+		},
+		tune: func(o *Option) {
+			o.CleanTitle = true
+			o.TitleCase = true
+		},
+	},
+	ProfileBlog: {
+		patterns: &classPatterns{
+			unlikely: regexp.MustCompile("(?i)combx|comment|community|disqus|extra|foot|header|menu|remark|rss|shoutbox|sidebar|sponsor|ad-break|agegate|pagination|pager|popup|author-bio|share|widget"),
+			maybe:    patterns.OKMaybeItsACandidate,
+			positive: regexp.MustCompile("(?i)article|body|content|entry|hentry|main|page|pagination|post|text|blog|story"),
+			negative: regexp.MustCompile("(?i)combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|shoutbox|sidebar|sponsor|shopping|tags|tool|widget|author-bio|share|archive"),
+		},
+		tune: func(o *Option) {
+			o.CleanTitle = true
+			o.PreferArticleImages = true
+		},
+	},
+	ProfileForum: {
+		patterns: &classPatterns{
+			unlikely: regexp.MustCompile("(?i)combx|extra|foot|header|menu|rss|sidebar|sponsor|ad-break|agegate|pagination|pager|popup|signature"),
+			maybe:    regexp.MustCompile("(?i)and|article|body|column|main|shadow|post|message|reply|thread"),
+			positive: regexp.MustCompile("(?i)article|body|content|entry|hentry|main|page|post|text|message|reply|thread|topic|comment"),
+			negative: regexp.MustCompile("(?i)combx|contact|foot|footer|masthead|media|outbrain|promo|related|scroll|sidebar|sponsor|shopping|tool|widget|signature|avatar|userinfo"),
+		},
+		tune: func(o *Option) {
+			o.RetryLength = 100
+			o.MinTextLength = 10
+			o.CleanConditionally = false
+			o.PreferArticleImages = true
+		},
+	},
+	ProfileProduct: {
+		patterns: &classPatterns{
+			unlikely: regexp.MustCompile("(?i)combx|comment|community|disqus|extra|foot|header|menu|remark|rss|shoutbox|sidebar|sponsor|ad-break|agegate|pagination|pager|popup|review|recommend|also-bought|carousel|cart"),
+			maybe:    regexp.MustCompile("(?i)and|article|body|column|main|shadow|product|description"),
+			positive: regexp.MustCompile("(?i)article|body|content|main|page|text|product|description|details|spec|features"),
+			negative: regexp.MustCompile("(?i)combx|comment|com-|contact|foot|footer|masthead|meta|outbrain|promo|related|scroll|shoutbox|sidebar|sponsor|tags|tool|widget|review|rating|recommend|carousel|cart"),
+		},
+		tune: func(o *Option) {
+			o.RetryLength = 100
+			o.MinTextLength = 15
+			o.MaxImageCount = 10
+			o.SortImagesBy = ImageOrderArea
+		},
+	},
+	ProfileRecipe: {
+		patterns: &classPatterns{
+			unlikely: regexp.MustCompile("(?i)combx|comment|community|disqus|extra|foot|header|menu|remark|rss|shoutbox|sidebar|sponsor|ad-break|agegate|pagination|pager|popup|review|rating|jump-to|print"),
+			maybe:    regexp.MustCompile("(?i)and|article|body|column|main|shadow|recipe|ingredient|instruction"),
+			positive: regexp.MustCompile("(?i)article|body|content|entry|hentry|main|page|post|text|recipe|ingredient|instruction|direction|method|step"),
+			negative: regexp.MustCompile("(?i)combx|comment|com-|contact|foot|footer|masthead|meta|outbrain|promo|related|scroll|shoutbox|sidebar|sponsor|shopping|tags|tool|widget|review|rating"),
+		},
+		tune: func(o *Option) {
+			o.MinTextLength = 10
+			o.MaxImageCount = 5
+			o.CleanConditionally = false
+			o.PreferArticleImages = true
+		},
+	},
+}
+
+// NewProfileOption returns the default option tuned for p, with Profile set to p.
+// It returns the default option for unknown profiles.
+func NewProfileOption(p Profile) *Option {
+	o := NewOption()
+	if pr, ok := profiles[p]; ok {
+		o.Profile = p
+		pr.tune(o)
+	}
+	return o
+}
+
+// classPatterns returns the class patterns of o.Profile, or the default ones if it is empty.
+func (o *Option) classPatterns() *classPatterns {
+	if pr, ok := profiles[o.Profile]; ok {
+		return pr.patterns
+	}
+	return defaultClassPatterns
+}
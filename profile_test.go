@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProfileOption(t *testing.T) {
+	for p := range profiles {
+		opt := NewProfileOption(p)
+		assert.Equal(t, p, opt.Profile)
+		assert.Nil(t, opt.Validate(), p)
+	}
+
+	opt := NewProfileOption(ProfileForum)
+	assert.Equal(t, 10, opt.MinTextLength)
+	assert.False(t, opt.CleanConditionally)
+
+	assert.Equal(t, NewOption(), NewProfileOption("wiki"))
+}
+
+func TestProfileClassPatterns(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(
+		`<div class="comment-list"></div><div class="share-bar"></div>`))
+	comments := doc.Find(".comment-list")
+	share := doc.Find(".share-bar")
+
+	opt := NewOption()
+	assert.True(t, isUnlikelyNode(comments.Get(0), opt.classPatterns()))
+	assert.False(t, isUnlikelyNode(share.Get(0), opt.classPatterns()))
+	assert.Equal(t, -25.0, classWeight(comments, opt))
+
+	opt.Profile = ProfileForum
+	assert.False(t, isUnlikelyNode(comments.Get(0), opt.classPatterns()))
+	assert.Equal(t, 25.0, classWeight(comments, opt))
+
+	opt.Profile = ProfileNews
+	assert.True(t, isUnlikelyNode(share.Get(0), opt.classPatterns()))
+}
+
+func TestExtractWithForumProfile(t *testing.T) {
+	post := "I have been trying to get the new firmware to work with my router for days now, and nothing helps. "
+	reply := "Same problem here. Rolling back to the previous version of the firmware fixed it for me, try that first. "
+	page := `<html><head><title>Firmware problem</title></head><body>
+<div class="thread">
+<div class="post"><p>` + strings.Repeat(post, 3) + `</p></div>
+<div class="comment"><p>` + strings.Repeat(reply, 2) + `</p></div>
+</div></body></html>`
+
+	extract := func(opt *Option) string {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		opt.LookupOpenGraphTags = false
+		opt.LookupStructuredData = false
+		c, err := ExtractFromDocument(doc, "https://forum.example.com/t/1", opt)
+		assert.Nil(t, err)
+		return c.Description
+	}
+	assert.NotContains(t, extract(NewOption()), "Same problem here")
+	desc := extract(NewProfileOption(ProfileForum))
+	assert.Contains(t, desc, "trying to get the new firmware")
+	assert.Contains(t, desc, "Same problem here")
+}
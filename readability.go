@@ -4,8 +4,10 @@ package readability
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
 	"net/url"
 	"os"
 	"regexp"
@@ -21,50 +23,134 @@ import (
 )
 
 // Image contains URL and Size (width and height in pixel).
+//
+// Image is encoded in JSON as
+// {"url": string, "width": int, "height": int, "source": string, "dominantColor": string, "rotated": bool, "animated": bool},
+// where width and height are 0 if unknown.
 type Image struct {
 	URL  string
 	Size *fastimage.ImageSize
+
+	// Source is where the image is found.
+	Source ImageSource
+
+	// DominantColor is the most common color of the image in "#rrggbb" form.
+	// It is set only for the lead image if Option.ComputeDominantColor is true.
+	DominantColor string
+
+	// Rotated is true if the EXIF orientation of the image rotates it by 90 or 270 degrees.
+	// Size is the displayed size, that is, the width and the height stored in the file are swapped.
+	// It is detected only when the size is probed by a request.
+	Rotated bool
+
+	// Animated is true if the image is an animated GIF or WebP.
+	// It is detected only when the size is probed by a request, from the leading bytes of the image.
+	Animated bool
+
+	// Caption is the text of the <figcaption> of the <figure> containing the image in the document.
+	Caption string
 }
 
 func (i Image) String() string {
-	return fmt.Sprintf("{URL: %v, Size: %vx%v}", i.URL, i.Size.Width, i.Size.Height)
+	w, h := i.size()
+	return fmt.Sprintf("{URL: %v, Size: %vx%v}", i.URL, w, h)
+}
+
+func (i Image) size() (width, height uint32) {
+	if i.Size == nil {
+		return 0, 0
+	}
+	return i.Size.Width, i.Size.Height
+}
+
+func (i Image) area() uint64 {
+	w, h := i.size()
+	return uint64(w) * uint64(h)
+}
+
+type imageJSON struct {
+	URL    string      `json:"url"`
+	Width  uint32      `json:"width"`
+	Height uint32      `json:"height"`
+	Source ImageSource `json:"source,omitempty"`
+
+	DominantColor string `json:"dominantColor,omitempty"`
+	Rotated       bool   `json:"rotated,omitempty"`
+	Animated      bool   `json:"animated,omitempty"`
+	Caption       string `json:"caption,omitempty"`
+}
+
+// MarshalJSON encodes i with its size flattened into width and height.
+func (i Image) MarshalJSON() ([]byte, error) {
+	w, h := i.size()
+	return json.Marshal(imageJSON{URL: i.URL, Width: w, Height: h, Source: i.Source,
+		DominantColor: i.DominantColor, Rotated: i.Rotated, Animated: i.Animated, Caption: i.Caption})
+}
+
+// UnmarshalJSON decodes i from the format of MarshalJSON.
+func (i *Image) UnmarshalJSON(data []byte) error {
+	var v imageJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	i.URL = v.URL
+	i.Size = &fastimage.ImageSize{Width: v.Width, Height: v.Height}
+	i.Source = v.Source
+	i.DominantColor = v.DominantColor
+	i.Rotated = v.Rotated
+	i.Animated = v.Animated
+	i.Caption = v.Caption
+	return nil
 }
 
 // Option contains variety of options for extracting page content and images.
+//
+// Option can be encoded in JSON with lowerCamelCase keys of the field names,
+// except ImageClient, ImageHostBreaker, CharsetReader, Reranker, MirrorResolver, Metrics and Transformers
+// which are never encoded.
 type Option struct {
 	// RetryLength is minimum length for a page description.
 	// It will retry to extract page description with more liberal rule
 	// if extracted description length is less than this value.
-	RetryLength int
+	RetryLength int `json:"retryLength"`
 
 	// MinTextLength is minimum length of an inner text for a tag.
 	// If a tag has short inner text (length is less than MinTextLength),
 	// the text will be discarded from the page description candidates.
-	MinTextLength int
+	MinTextLength int `json:"minTextLength"`
 
 	// RemoveUnlikelyCandidates is a flag whether to remove some tags
 	// if they are considered relatively unimportant.
-	RemoveUnlikelyCandidates bool
+	RemoveUnlikelyCandidates bool `json:"removeUnlikelyCandidates"`
 
 	// WeightClasses is a flag whether to give more/less weight to some tags
 	// if they contain some positive/negative words in id/class value.
-	WeightClasses bool
+	WeightClasses bool `json:"weightClasses"`
 
 	// CleanConditionally is a flag whether to remove some tags
-	// using various rules in conditionalCleanReason().
-	CleanConditionally bool
+	// using various rules in conditionalCleanCode().
+	CleanConditionally bool `json:"cleanConditionally"`
+
+	// CleanRules toggles the rules of CleanConditionally, such as {"TOO_MANY_LINKS": false}
+	// to keep link lists. If nil, all rules are applied. See CleanRuleSet.
+	CleanRules CleanRuleSet `json:"cleanRules"`
 
 	// RemoveEmptyNodes is a flag whether to remove some tags which have empty inner text.
-	RemoveEmptyNodes bool
+	RemoveEmptyNodes bool `json:"removeEmptyNodes"`
+
+	// RemoveResponsiveDuplicates is a flag whether to remove blocks shown only on some screen sizes
+	// (like "d-md-none" or "hidden-xs") which duplicate the text of another block,
+	// such as the mobile and desktop variants of the same content.
+	RemoveResponsiveDuplicates bool `json:"removeResponsiveDuplicates"`
 
 	// MinImageWidth is the minimum width (pixel) for choosing images.
-	MinImageWidth uint32
+	MinImageWidth uint32 `json:"minImageWidth"`
 
 	// MinImageHeight is the minimum height (pixel) for choosing images.
-	MinImageHeight uint32
+	MinImageHeight uint32 `json:"minImageHeight"`
 
 	// MaxImageCount is the maximum number of images for a web page.
-	MaxImageCount int
+	MaxImageCount int `json:"maxImageCount"`
 
 	// CheckImageLoopCount is the number of images
 	// for parallel requests to fetch the image size.
@@ -73,23 +159,281 @@ type Option struct {
 	// will be requested over network.
 	// (img tags with both width/height attributes (pixels in int) are not conunted,
 	// since they are not requested over network to get image size.)
-	CheckImageLoopCount uint
+	CheckImageLoopCount uint `json:"checkImageLoopCount"`
+
+	// ImageFetchConcurrency is the maximum number of image size requests sent at once
+	// by an extraction. Other requests wait for them, within ImageProbingTimeout.
+	// If 0, all requests (up to CheckImageLoopCount) are sent at once.
+	ImageFetchConcurrency int `json:"imageFetchConcurrency"`
+
+	// MaxImagesToParse is the number of <img> and <video poster> elements considered for images,
+	// in document order, whether or not they are requested. It bounds the work on gallery pages
+	// with hundreds of images, while CheckImageLoopCount bounds the requests. If 0, all elements are considered.
+	MaxImagesToParse uint `json:"maxImagesToParse"`
 
 	// ImageRequestTimeout is timeout(ms) for a single image request.
-	ImageRequestTimeout uint
+	ImageRequestTimeout uint `json:"imageRequestTimeout"`
+
+	// SkipImageProbing is a flag whether to never request images, for latency-critical services.
+	// Image sizes are told only by width and height attributes, scaled by the descriptor
+	// of the image in the srcset attribute if it is listed, and by og:image:width and og:image:height,
+	// and images whose size is unknown are skipped. ComputeDominantColor is ignored.
+	SkipImageProbing bool `json:"skipImageProbing"`
+
+	// ImageProbingTimeout is timeout(ms) for probing all images of a page. Images not probed in time
+	// are skipped. If 0, ImageRequestTimeout plus 50ms is used.
+	ImageProbingTimeout uint `json:"imageProbingTimeout"`
+
+	// MaxHTMLSize is the maximum size (bytes) of a page read by Extract and Extractor.
+	// Larger pages are truncated to it while downloading, instead of reading and parsing them
+	// whole, and get WarningHTMLTruncated. If 0, pages are read without limit.
+	MaxHTMLSize int64 `json:"maxHTMLSize"`
+
+	// MaxImageBytes is the maximum size (bytes) of an image downloaded by Image.Fetch.
+	// If 0, images are downloaded without limit.
+	MaxImageBytes int64 `json:"maxImageBytes"`
+
+	// ComputeDominantColor is a flag whether to download the lead image (Content.PrimaryImage,
+	// or the first of Content.Images) to compute Image.DominantColor, which preview UIs use
+	// for placeholder backgrounds. Only JPEG, PNG and GIF images up to 1MB are supported.
+	ComputeDominantColor bool `json:"computeDominantColor"`
+
+	// MaxRedirects is the maximum number of redirects followed by a page request of Extract
+	// and Extractor. A page redirecting more times fails with *TooManyRedirectsError, so 0 means
+	// that no redirect is followed and any redirected page fails. It overrides CheckRedirect of
+	// Extractor.Client. The redirects followed are recorded in Content.Redirects.
+	MaxRedirects int `json:"maxRedirects"`
+
+	// ImageMaxRedirects is the maximum number of redirects followed by a single image request.
+	// If 0, redirects are not followed. CheckRedirect of ImageClient, if set, is still called
+	// for the redirects within the limit.
+	ImageMaxRedirects int `json:"imageMaxRedirects"`
+
+	// ImageHostBreaker, if set, skips image probes to hosts which timed out in the extractions
+	// sharing it, in addition to the breaker of each extraction. See HostBreaker.
+	ImageHostBreaker *HostBreaker `json:"-"`
+
+	// ImageInsecureSkipVerify is a flag whether to skip verifying TLS certificates of images,
+	// which is useful for internal crawlers with self-signed certificates.
+	// It is ignored if ImageClient is set; configure the transport of ImageClient instead.
+	ImageInsecureSkipVerify bool `json:"imageInsecureSkipVerify"`
+
+	// PreferHeroImage is a flag whether to put the hero image, found right before or after
+	// the <h1> title block, first in Content.Images, even if it is outside the article.
+	// It is off by default, which keeps the images of earlier versions.
+	PreferHeroImage bool `json:"preferHeroImage"`
+
+	// VideoThumbnails is a flag whether to add the thumbnails of YouTube and Vimeo videos
+	// embedded in the page to Content.Images, with the source ImageSourceVideoEmbed, so that
+	// posts with only a video have a preview image. YouTube thumbnails are built from the video IDs,
+	// while Vimeo thumbnails are looked up with its oEmbed API unless SkipImageProbing is set.
+	VideoThumbnails bool `json:"videoThumbnails"`
+
+	// PreferArticleImages is a flag whether to collect images only from the extracted article,
+	// falling back to the whole document if no image is found there. It is used only if
+	// the description is extracted by readability rules, not by opengraph or structured data.
+	PreferArticleImages bool `json:"preferArticleImages"`
+
+	// DedupeImages is a flag whether to drop variants of an image already chosen, such as
+	// the same image resized by CDN query parameters or with a size suffix in the file name.
+	DedupeImages bool `json:"dedupeImages"`
+
+	// DedupeImagesBySignature is a flag whether to also drop images whose leading bytes are
+	// identical to an image already chosen, such as the same file served from different hosts.
+	// It is used only if DedupeImages is set, and only for images requested to detect sizes.
+	DedupeImagesBySignature bool `json:"dedupeImagesBySignature"`
+
+	// SortImagesBy is the order of Content.Images. If empty, ImageOrderDocument is used.
+	// With ImageOrderDocument, the result is the same for every run regardless of which
+	// image request finishes first. With ImageOrderArea and ImageOrderScore, all images
+	// (up to CheckImageLoopCount) are compared before the first MaxImageCount are chosen.
+	SortImagesBy ImageOrder `json:"sortImagesBy"`
+
+	// ImageClient is used for requests to fetch image sizes.
+	// If nil, a client shared by all extractions is used, which reuses connections per host
+	// and multiplexes requests over HTTP/2 when supported. See NewImageClient.
+	// If set, ImageInsecureSkipVerify is ignored, and the transport of ImageClient decides
+	// whether to verify certificates.
+	ImageClient *http.Client `json:"-"`
 
 	// IgnoreImageFormat is an array of strings for ignoring some images.
-	// If an image URL contains at least one of strings in this array, the image will be ignored.
-	IgnoreImageFormat []string
+	// An image is ignored if it matches at least one of them:
+	//   - ".ext" like ".svg" matches the file extension of the URL path (not the query),
+	//     or the MIME type of the extension detected by requesting the image.
+	//   - "type/subtype" like "image/gif" matches the MIME type detected by the request,
+	//     declared by a data URI or guessed from the extension. "image/" matches all image types.
+	//   - "data:..." like "data:image/" matches data URIs starting with it.
+	//   - Others match image URLs containing them.
+	IgnoreImageFormat []string `json:"ignoreImageFormat"`
+
+	// OnlyImageFormats is an array of strings for keeping only some images, in the same forms
+	// as IgnoreImageFormat. If not empty, an image is ignored unless it matches at least one of them.
+	// Images whose MIME types are unknown, such as ones without extensions whose sizes are
+	// given by attributes, are kept.
+	OnlyImageFormats []string `json:"onlyImageFormats"`
+
+	// DecodeDataURIImages is a flag whether to include images inlined as data URIs, such as
+	// "data:image/png;base64,...", which some pages use for hero images. Their sizes are detected
+	// by decoding them locally. If set, data URIs are not matched against IgnoreImageFormat,
+	// and Image.URL of them is the data URI itself.
+	DecodeDataURIImages bool `json:"decodeDataURIImages"`
+
+	// CharsetPolicy decides the charset of a requested page when the Content-Type header
+	// and <meta> in the page declare different charsets. If empty, CharsetPolicyAuto is used.
+	// The decision is available as Content.Charset.
+	CharsetPolicy CharsetPolicy `json:"charsetPolicy"`
+
+	// CharsetReader decodes requested pages in charsets other than UTF-8, UTF-16 and windows-1252
+	// (which ISO-8859-1 and US-ASCII are decoded as), such as EUC-KR or Shift_JIS.
+	// If nil, pages in such charsets are read as UTF-8.
+	// charset.NewReaderLabel of golang.org/x/net/html/charset can be used.
+	CharsetReader CharsetReader `json:"-"`
 
 	// DescriptionAsPlainText is a flag whether to strip all tags in a description value.
-	DescriptionAsPlainText bool
+	DescriptionAsPlainText bool `json:"descriptionAsPlainText"`
+
+	// KeepFigures is a flag whether to keep <figure>s in the description with their <figcaption>s
+	// and <img>s (only src and alt attributes, with src resolved to absolute URLs) if HTMLPolicy is nil.
+	// With DescriptionAsPlainText, only the captions are separated from the text around them.
+	KeepFigures bool `json:"keepFigures"`
+
+	// HTMLPolicy is the whitelist of elements and attributes kept in the description
+	// if DescriptionAsPlainText is false. If nil, only <div> and <p> are kept without attributes.
+	// See NewHTMLPolicy.
+	HTMLPolicy *HTMLPolicy `json:"htmlPolicy,omitempty"`
+
+	// SiblingThresholds decides which siblings of the best description candidate are included
+	// in the description. If nil, NewSiblingThresholds is used.
+	SiblingThresholds *SiblingThresholds `json:"siblingThresholds,omitempty"`
 
 	// DescriptionExtractionTimeout is timeout(ms) for extracting description for a page.
-	DescriptionExtractionTimeout uint
+	DescriptionExtractionTimeout uint `json:"descriptionExtractionTimeout"`
+
+	// ParallelScoring is a flag whether to score the sections of a page in parallel
+	// when extracting description. It speeds up huge pages (10k+ nodes) on multi-core machines
+	// without changing the result.
+	ParallelScoring bool `json:"parallelScoring"`
+
+	// ModifyDocument is a flag whether to extract description by modifying the document itself
+	// instead of a copy for each pass, which saves copying the document.
+	// The document passed to ExtractFromDocument is then left modified,
+	// and retries with more liberal rules (see RetryLength) see the nodes removed by the previous passes.
+	ModifyDocument bool `json:"modifyDocument"`
+
+	// MetadataTimeout is timeout(ms) for reading opengraph tags and structured data.
+	// If they are not read in time, the page is extracted without them. If 0, there is no timeout.
+	MetadataTimeout uint `json:"metadataTimeout"`
 
 	// LookupOpenGraphTags is a flag whether to use opengraph tag value for title, descriptions and image if exists.
-	LookupOpenGraphTags bool
+	LookupOpenGraphTags bool `json:"lookupOpenGraphTags"`
+
+	// LookupStructuredData is a flag whether to use article values declared with JSON-LD or microdata
+	// (schema.org Article, NewsArticle, ...) if exists. Opengraph values take precedence over them.
+	LookupStructuredData bool `json:"lookupStructuredData"`
+
+	// CleanTitle is a flag whether to strip the site name and the section from the title,
+	// like "Article Headline | Site Name – Section" to "Article Headline".
+	// The title before cleaning is available as Content.RawTitle.
+	CleanTitle bool `json:"cleanTitle"`
+
+	// TitleCase is a flag whether to re-case titles written in all capitals for display,
+	// like "MAN BITES DOG" to "Man Bites Dog", with the conventions of TitleLocale. See TitleCase.
+	// The title before re-casing is available as Content.RawTitle.
+	TitleCase bool `json:"titleCase"`
+
+	// TitleLocale is the BCP 47 language tag (like "en-US") used by TitleCase.
+	// If empty, the lang attribute of <html> is used, then English.
+	TitleLocale string `json:"titleLocale"`
+
+	// ShareableImagesOnly is a flag whether to return only images the publisher designated
+	// for sharing (og:image, twitter:image and <link rel="image_src">), never images in the article.
+	// If set, Content.PrimaryImage is the first of them and PrimaryImageSources is not used.
+	ShareableImagesOnly bool `json:"shareableImagesOnly"`
+
+	// ExcludedDescriptionSources maps domains to description sources never used for
+	// the pages of the domain and its subdomains, like {"spam.example": ["og", "metadata"]}.
+	// The sources of "*" are excluded for all pages. If excluded sources have the only
+	// descriptions in opengraph or structured data, the article is extracted by readability rules.
+	ExcludedDescriptionSources map[string][]DescriptionSource `json:"excludedDescriptionSources,omitempty"`
+
+	// PrimaryImageSources is the fallback order of sources for Content.PrimaryImage.
+	// Sources not in this list are never used for the primary image.
+	// If ImageSourceArticle is reached when opengraph or structured data values are used,
+	// images in the document are requested to fill Content.Images.
+	PrimaryImageSources []ImageSource `json:"primaryImageSources"`
+
+	// FollowAMP is a flag whether to extract contents from the AMP version of the page
+	// (<link rel="amphtml">) if the description extracted from the page is shorter than RetryLength.
+	// It is used only by functions requesting pages, such as Extract.
+	FollowAMP bool `json:"followAMP"`
+
+	// Explain is a flag whether to record why each section of the extracted article
+	// was kept or removed. The result is available as Content.Explanation.
+	Explain bool `json:"explain"`
+
+	// Reranker re-ranks the top description candidates if not nil.
+	Reranker Reranker `json:"-"`
+
+	// RerankTopN is the number of top candidates passed to Reranker.
+	// If RerankTopN is 0 or greater than the number of candidates, all candidates are passed.
+	RerankTopN int `json:"rerankTopN"`
+
+	// CandidateCount is the number of top description candidates returned in Content.Candidates,
+	// so that downstream systems can choose another candidate when the best one looks wrong.
+	// If 0, no candidates are returned.
+	CandidateCount int `json:"candidateCount"`
+
+	// Engine is the algorithm extracting the description from the page body:
+	// a built-in engine or the name of an engine registered by RegisterEngine.
+	// If empty, EngineReadability is used.
+	Engine EngineName `json:"engine"`
+
+	// MirrorResolver returns mirrors of a page, such as caches or text-only readers,
+	// which are tried in order when the page itself is blocked (HTTP 401, 403, 429 or 503,
+	// or a bot-protection challenge).
+	// It is used only by Extractor.
+	MirrorResolver MirrorResolver `json:"-"`
+
+	// ReaderRetry is a flag whether to request the page again with a reader profile
+	// (a reader User-Agent, no cookies, then the ?amp and ?outputType=amp variants)
+	// if the page is dominated by a cookie consent banner.
+	// It is used only by Extractor.
+	ReaderRetry bool `json:"readerRetry"`
+
+	// Metrics receives measurements of the extraction pipeline if not nil.
+	Metrics Metrics `json:"-"`
+
+	// Profile selects the class patterns tuned for a kind of page, such as ProfileForum,
+	// which decide unlikely candidates and class weights. If empty, the default patterns are used.
+	// Setting Profile doesn't change other option values; NewProfileOption sets them too.
+	Profile Profile `json:"profile"`
+
+	// Fingerprint is a flag whether to set Content.Fingerprint for ReextractFromDocument.
+	// With ModifyDocument, the fingerprint is computed on the modified document, so it rarely matches.
+	Fingerprint bool `json:"fingerprint"`
+
+	// Transformers transform the text fields of the result in order, such as machine translation
+	// or profanity masking, before it is returned. See Transformer.
+	Transformers []Transformer `json:"-"`
+
+	// DryRun is a flag whether to run every stage without sending requests other than the fetch
+	// of the page by Extract or Extractor: images are not probed (images whose size is unknown
+	// are skipped as with SkipImageProbing), and oEmbed lookups, dominant color downloads,
+	// AMP and reader fetches are not sent. The requests are listed in Content.PlannedRequests instead,
+	// for capacity planning and debugging outbound traffic. MirrorResolver is not used.
+	DryRun bool `json:"dryRun"`
+
+	// trace records the extraction if not nil. See ExtractWithDebug.
+	trace *DebugTrace
+
+	// stages records the stages run and timed out during an extraction. See Content.Completeness.
+	stages *stageTracker
+
+	// plan records the requests planned during an extraction with DryRun. See Content.PlannedRequests.
+	plan *requestPlan
+
+	// imageHosts skips image probes to hosts which timed out during an extraction.
+	imageHosts *HostBreaker
 }
 
 // NewOption returns the default option.
@@ -101,15 +445,28 @@ func NewOption() *Option {
 		WeightClasses:                true,
 		CleanConditionally:           true,
 		RemoveEmptyNodes:             true,
+		RemoveResponsiveDuplicates:   true,
 		MinImageWidth:                200,
 		MinImageHeight:               100,
 		MaxImageCount:                3,
 		CheckImageLoopCount:          10,
+		MaxImagesToParse:             100,
 		ImageRequestTimeout:          1000,
-		IgnoreImageFormat:            []string{"data:image/", ".svg", ".webp"},
+		MaxImageBytes:                10 * 1024 * 1024,
+		MaxRedirects:                 10,
+		ImageMaxRedirects:            5,
+		PreferHeroImage:              false,
+		DedupeImages:                 true,
+		SortImagesBy:                 ImageOrderDocument,
+		IgnoreImageFormat:            []string{"data:image/", ".svg"},
+		CharsetPolicy:                CharsetPolicyAuto,
 		DescriptionAsPlainText:       true,
 		DescriptionExtractionTimeout: 500,
 		LookupOpenGraphTags:          true,
+		LookupStructuredData:         true,
+		PrimaryImageSources:          DefaultPrimaryImageSources,
+		RerankTopN:                   5,
+		Engine:                       EngineReadability,
 	}
 }
 
@@ -120,16 +477,70 @@ func copyOption(o *Option) *Option {
 		RemoveUnlikelyCandidates:     o.RemoveUnlikelyCandidates,
 		WeightClasses:                o.WeightClasses,
 		CleanConditionally:           o.CleanConditionally,
+		CleanRules:                   o.CleanRules,
 		RemoveEmptyNodes:             o.RemoveEmptyNodes,
+		RemoveResponsiveDuplicates:   o.RemoveResponsiveDuplicates,
 		MinImageWidth:                o.MinImageWidth,
 		MinImageHeight:               o.MinImageHeight,
 		MaxImageCount:                o.MaxImageCount,
 		CheckImageLoopCount:          o.CheckImageLoopCount,
+		ImageFetchConcurrency:        o.ImageFetchConcurrency,
+		MaxImagesToParse:             o.MaxImagesToParse,
 		ImageRequestTimeout:          o.ImageRequestTimeout,
+		SkipImageProbing:             o.SkipImageProbing,
+		ImageProbingTimeout:          o.ImageProbingTimeout,
+		MaxHTMLSize:                  o.MaxHTMLSize,
+		MaxImageBytes:                o.MaxImageBytes,
+		MaxRedirects:                 o.MaxRedirects,
+		ImageMaxRedirects:            o.ImageMaxRedirects,
+		ImageHostBreaker:             o.ImageHostBreaker,
+		ComputeDominantColor:         o.ComputeDominantColor,
+		ImageInsecureSkipVerify:      o.ImageInsecureSkipVerify,
+		PreferHeroImage:              o.PreferHeroImage,
+		VideoThumbnails:              o.VideoThumbnails,
+		PreferArticleImages:          o.PreferArticleImages,
+		DedupeImages:                 o.DedupeImages,
+		DedupeImagesBySignature:      o.DedupeImagesBySignature,
+		SortImagesBy:                 o.SortImagesBy,
+		ImageClient:                  o.ImageClient,
 		IgnoreImageFormat:            o.IgnoreImageFormat,
+		OnlyImageFormats:             o.OnlyImageFormats,
+		DecodeDataURIImages:          o.DecodeDataURIImages,
+		CharsetPolicy:                o.CharsetPolicy,
+		CharsetReader:                o.CharsetReader,
 		DescriptionAsPlainText:       o.DescriptionAsPlainText,
+		KeepFigures:                  o.KeepFigures,
+		HTMLPolicy:                   o.HTMLPolicy,
+		SiblingThresholds:            o.SiblingThresholds,
 		DescriptionExtractionTimeout: o.DescriptionExtractionTimeout,
+		ParallelScoring:              o.ParallelScoring,
+		ModifyDocument:               o.ModifyDocument,
+		MetadataTimeout:              o.MetadataTimeout,
 		LookupOpenGraphTags:          o.LookupOpenGraphTags,
+		LookupStructuredData:         o.LookupStructuredData,
+		CleanTitle:                   o.CleanTitle,
+		TitleCase:                    o.TitleCase,
+		TitleLocale:                  o.TitleLocale,
+		ShareableImagesOnly:          o.ShareableImagesOnly,
+		PrimaryImageSources:          o.PrimaryImageSources,
+		ExcludedDescriptionSources:   o.ExcludedDescriptionSources,
+		FollowAMP:                    o.FollowAMP,
+		Explain:                      o.Explain,
+		Reranker:                     o.Reranker,
+		RerankTopN:                   o.RerankTopN,
+		CandidateCount:               o.CandidateCount,
+		Engine:                       o.Engine,
+		MirrorResolver:               o.MirrorResolver,
+		ReaderRetry:                  o.ReaderRetry,
+		Metrics:                      o.Metrics,
+		Profile:                      o.Profile,
+		Fingerprint:                  o.Fingerprint,
+		Transformers:                 o.Transformers,
+		trace:                        o.trace,
+		DryRun:                       o.DryRun,
+		stages:                       o.stages,
+		plan:                         o.plan,
+		imageHosts:                   o.imageHosts,
 	}
 }
 
@@ -138,14 +549,14 @@ type pattern struct {
 	OKMaybeItsACandidate *regexp.Regexp
 	Positive             *regexp.Regexp
 	Negative             *regexp.Regexp
-	DivToPElements       *regexp.Regexp
 	ReplaceBrs           *regexp.Regexp
 	ReplaceFonts         *regexp.Regexp
 	Normalize            *regexp.Regexp
 	KillBreaks           *regexp.Regexp
 	Video                *regexp.Regexp
-	Tag                  *regexp.Regexp
 	Trimmable            *regexp.Regexp
+	Newlines             *regexp.Regexp
+	SentenceEnd          *regexp.Regexp
 }
 
 func newPattern() *pattern {
@@ -153,163 +564,670 @@ func newPattern() *pattern {
 	mc := regexp.MustCompile("(?i)and|article|body|column|main|shadow")
 	pos := regexp.MustCompile("(?i)article|body|content|entry|hentry|main|page|pagination|post|text|blog|story")
 	neg := regexp.MustCompile("(?i)combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|shoutbox|sidebar|sponsor|shopping|tags|tool|widget")
-	dtp := regexp.MustCompile("(?i)<(a|blockquote|dl|div|img|ol|p|pre|table|ul)")
 	rb := regexp.MustCompile("(?i)(<br[^>]*>[ \n\r\t]*){2,}")
 	rf := regexp.MustCompile("(?i)<(\\/?)font[^>]*>")
 	nm := regexp.MustCompile("\\s{2,}")
 	kb := regexp.MustCompile("(<br\\s*\\/?>(\\s|&nbsp;?)*){1,}")
 	vid := regexp.MustCompile("(?i)http:\\/\\/(www\\.)?(youtube|vimeo)\\.com")
-	tag := regexp.MustCompile("<.*?>")
 	tr := regexp.MustCompile("[\r\n\t ]+")
+	nl := regexp.MustCompile("[\r\n\f]+")
+	se := regexp.MustCompile("\\.( |$)")
 	return &pattern{
 		UnlikelyCandidates:   uc,
 		OKMaybeItsACandidate: mc,
 		Positive:             pos,
 		Negative:             neg,
-		DivToPElements:       dtp,
 		ReplaceBrs:           rb,
 		ReplaceFonts:         rf,
 		Normalize:            nm,
 		KillBreaks:           kb,
 		Video:                vid,
-		Tag:                  tag,
 		Trimmable:            tr,
+		Newlines:             nl,
+		SentenceEnd:          se,
 	}
 }
 
 var patterns = newPattern()
 
+// all returns every pattern of p.
+func (p *pattern) all() []*regexp.Regexp {
+	return []*regexp.Regexp{p.UnlikelyCandidates, p.OKMaybeItsACandidate, p.Positive, p.Negative,
+		p.ReplaceBrs, p.ReplaceFonts, p.Normalize, p.KillBreaks, p.Video, p.Trimmable, p.Newlines, p.SentenceEnd}
+}
+
 // Content contains primary readable content of a webpage.
+//
+// Content is encoded in JSON with lowerCamelCase keys of the field names
+// ("title", "description", "images", "primaryImage", "ampUrl", ...).
+// Keys of optional fields are omitted if empty. See README for the schema.
 type Content struct {
-	Title       string
-	Description string
-	Author      string
-	Images      []Image
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Author      string  `json:"author,omitempty"`
+	Images      []Image `json:"images"`
+
+	// TitleSource is where Title comes from. If <title> is empty or generic (like "Home"),
+	// the first <h1> is used, then the most important heading inside the article.
+	// If no title is found at all, it is derived from the URL (see TitleSourceURL).
+	TitleSource TitleSource `json:"titleSource,omitempty"`
+
+	// RawTitle is the title before cleaning and re-casing.
+	// It is set only if Option.CleanTitle or Option.TitleCase is true.
+	RawTitle string `json:"rawTitle,omitempty"`
+
+	// DescriptionSource is where Description comes from. It is empty if Description is empty.
+	// See Option.ExcludedDescriptionSources.
+	DescriptionSource DescriptionSource `json:"descriptionSource,omitempty"`
+
+	// PrimaryImage is the representative image of the page,
+	// chosen in the order of Option.PrimaryImageSources. It is nil if no image is found.
+	PrimaryImage *Image `json:"primaryImage,omitempty"`
+
+	// PublishedTime is the published date/time declared by structured data, as is.
+	PublishedTime string `json:"publishedTime,omitempty"`
+
+	// Tags contains deduplicated, lowercased tags/keywords of the page.
+	Tags []string `json:"tags,omitempty"`
+
+	// ThemeColor is the accent color of the site declared by <meta name="theme-color">, as is.
+	ThemeColor string `json:"themeColor,omitempty"`
+
+	// TileColor is the color declared by <meta name="msapplication-TileColor">, as is.
+	TileColor string `json:"tileColor,omitempty"`
+
+	// AMPURL is the absolute URL of the AMP version of the page, if exists.
+	AMPURL string `json:"ampUrl,omitempty"`
+
+	// FromAMP is true if the content was extracted from the AMP version of the page.
+	FromAMP bool `json:"fromAmp,omitempty"`
+
+	// Charset describes how the page was decoded. It is set only if the page is requested
+	// by this package, such as Extract.
+	Charset *CharsetDecision `json:"charset,omitempty"`
+
+	// Redirects contains the URLs redirected from, in order, if the page was redirected
+	// when requested by this package. The last one redirected to the final URL.
+	Redirects []string `json:"redirects,omitempty"`
+
+	// NormalizedURL is the URL of the page normalized for deduplication: the final URL
+	// if the page is requested by this package, otherwise the URL passed for extraction,
+	// with the host lowercased, and the fragment and tracking parameters like utm_source removed.
+	NormalizedURL string `json:"normalizedUrl,omitempty"`
+
+	// Warnings contains the reasons the extraction is likely bad, such as a navigation menu
+	// extracted as the description. It is empty if nothing suspicious is found.
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// OriginalSource is the original of the article if it is republished from another page,
+	// found by JSON-LD isBasedOn, <link rel="canonical"> to another site, or a credit line
+	// like "originally published at".
+	OriginalSource *Syndication `json:"originalSource,omitempty"`
+
+	// Sources contains the sources cited by the article, such as the items of a reference list
+	// or of a "Sources:" block, for fact-checking. They are not removed from Description.
+	Sources []Source `json:"sources,omitempty"`
+
+	// Recipe is the recipe declared by the page with JSON-LD, microdata or the markup of
+	// common recipe plugins, or nil if the page has no recipe.
+	Recipe *Recipe `json:"recipe,omitempty"`
+
+	// Product is the product declared by the page with JSON-LD, microdata or og:product tags,
+	// or nil if the page has no product.
+	Product *Product `json:"product,omitempty"`
+
+	// Audio contains the audio files of the page, such as podcast episodes, declared by JSON-LD
+	// (PodcastEpisode and AudioObject), og:audio tags and <audio> elements, in that order.
+	Audio []Media `json:"audio,omitempty"`
+
+	// Paywalled is true if the article is behind a paywall, so Description is likely truncated:
+	// the page declares isAccessibleForFree false with JSON-LD, has a paywall element
+	// (like class="paywall"), or prompts to subscribe to continue reading with a short description.
+	Paywalled bool `json:"paywalled,omitempty"`
+
+	// Fingerprint identifies the article for ReextractFromDocument. It is set only if
+	// Option.Fingerprint is true and the description is extracted by readability rules.
+	Fingerprint *Fingerprint `json:"fingerprint,omitempty"`
+
+	// TextDirection is the direction of the text, for rendering Arabic or Hebrew articles:
+	// the dir attribute of <html> or <body> if it is "ltr" or "rtl", otherwise
+	// the direction of the script of most letters of the title and the description.
+	TextDirection TextDirection `json:"textDirection,omitempty"`
+
+	// Mirror is the mirror the content was extracted from if the page itself was blocked.
+	// See Option.MirrorResolver.
+	Mirror *Mirror `json:"mirror,omitempty"`
+
+	// ReaderURL is the URL requested with the reader profile if the page was dominated
+	// by a cookie consent banner. See Option.ReaderRetry.
+	ReaderURL string `json:"readerUrl,omitempty"`
+
+	// Completeness tells which stages of the extraction completed, skipped or timed out.
+	Completeness *Completeness `json:"completeness,omitempty"`
+
+	// PlannedRequests contains the requests not sent since Option.DryRun is set,
+	// in the order they would have been sent, without duplicates.
+	PlannedRequests []PlannedRequest `json:"plannedRequests,omitempty"`
+
+	// Outline contains the headings (<h1> to <h6>) of the article in document order,
+	// for tables of contents and deep links. It is set only if the description is extracted
+	// by readability rules.
+	Outline []Heading `json:"outline,omitempty"`
+
+	// Candidates contains the top description candidates with their scores and texts,
+	// the best one first, if Option.CandidateCount is set and the description is extracted
+	// by readability rules.
+	Candidates []ArticleCandidate `json:"candidates,omitempty"`
+
+	// Engine is the engine which extracted the description if DescriptionSource is
+	// DescriptionSourceReadability, that is, not from metadata. See Option.Engine.
+	Engine EngineName `json:"engine,omitempty"`
+
+	// Paragraphs contains the plain text of each block of the article, such as paragraphs
+	// and headings, in document order, with whitespaces collapsed, for NLP pipelines.
+	// It is set only if the description is extracted by readability rules. It is used by Chunks.
+	Paragraphs []string `json:"paragraphs,omitempty"`
+
+	// Explanation is set only if Option.Explain is true
+	// and the description is extracted by readability rules.
+	Explanation *Explanation `json:"explanation,omitempty"`
+
+	// articleNode is the root of the DOM of the article. See ArticleNode.
+	articleNode *html.Node
+}
+
+// ArticleNode returns the root (a document node) of the parsed DOM of the article
+// rendered as the description, for consumers running their own passes on it
+// without re-parsing the description. It returns nil unless the description is extracted
+// by readability rules in this process.
+//
+// The DOM is the one before the description is converted to plain text if
+// Option.DescriptionAsPlainText is set. Changes on it are not reflected in the fields of c.
+func (c *Content) ArticleNode() *html.Node {
+	return c.articleNode
 }
 
 // Extract requests to reqURL then returns contents extracted from the response.
+// A response with a 4xx or 5xx status code is returned as an *HTTPError.
 func Extract(reqURL string, opt *Option) (*Content, error) {
-	doc, err := goquery.NewDocument(reqURL)
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+	fetch := func(u string) (*fetchedPage, error) {
+		return fetchDocument(u, opt)
+	}
+	page, err := fetch(reqURL)
+	if err != nil {
+		return nil, err
+	}
+	c, err := extractFromDocument(page.doc, reqURL, opt)
 	if err != nil {
 		return nil, err
 	}
-	return ExtractFromDocument(doc, reqURL, opt)
+	c.setFetched(page, opt)
+	c.setRedirects(page)
+	return extractFromAMP(c, opt, fetch), nil
+}
+
+// fetchDocument requests to reqURL with http.DefaultClient then parses the response
+// with the charset decided by opt.CharsetPolicy.
+// A response with a 4xx or 5xx status code is an *HTTPError, as with Extractor.
+func fetchDocument(reqURL string, opt *Option) (*fetchedPage, error) {
+	defer observeStage(opt, StageFetch, time.Now())
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client, redirects := pageClient(http.DefaultClient, opt)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, redirectError(err)
+	}
+	defer resp.Body.Close()
+	if t := responseBotChallenge(resp); t != "" {
+		return nil, &BotChallengeError{URL: reqURL, Type: t, StatusCode: resp.StatusCode}
+	}
+	if resp.StatusCode >= 400 {
+		return nil, statusError(resp, reqURL, opt)
+	}
+	page, err := parsePage(resp, opt)
+	if err != nil {
+		return nil, err
+	}
+	page.redirects = *redirects
+	return page, nil
 }
 
 // ExtractFromDocument returns Content when extraction succeeds, otherwise error.
+// opt is validated with Option.Validate before extraction, and too large values are clamped
+// on a copy of opt, so opt can be shared by concurrent extractions.
 // reqURL is required for converting relative image paths to absolute.
 //
 // If you already have *goquery.Document after requesting HTTP, use this function,
 // otherwise use Extract(reqURL, opt).
+// doc is not modified unless Option.ModifyDocument is set.
+//
+// The result is deterministic: the same doc, reqURL and opt give the same Content,
+// encoded in JSON byte for byte, unless a stage times out (see Content.Completeness)
+// or image responses differ between runs.
+//
+// If doc is a bot-protection challenge page such as Cloudflare's "Just a moment...",
+// a *BotChallengeError is returned instead of extracting the challenge as the content.
 func ExtractFromDocument(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
-	if opt.LookupOpenGraphTags {
-		og, err := getContentFromOpenGraph(doc, reqURL)
-		if err == nil && !og.IsEmpty() {
-			return &Content{
-				Title:       og.Title,
-				Description: og.Description,
-				Images: []Image{
-					Image{
-						URL:  og.ImageURL,
-						Size: &fastimage.ImageSize{Width: 0, Height: 0},
-					},
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+	return extractFromDocument(doc, reqURL, opt)
+}
+
+// extractFromDocument acts same as ExtractFromDocument for opt validated by the caller,
+// which is the entry point validating it once, such as Extract and Extractor.Extract.
+func extractFromDocument(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
+	if t := botChallenge(doc); t != "" {
+		return nil, &BotChallengeError{URL: reqURL, Type: t}
+	}
+	defer observeStage(opt, StageExtract, time.Now())
+	opt = copyOption(opt)
+	opt.clamp()
+	opt.stages = newStageTracker()
+	opt.imageHosts = NewHostBreaker(1, 0)
+	if opt.DryRun {
+		opt.plan = &requestPlan{}
+	}
+	paywallMarked, paywallPrompted := paywallSignals(doc)
+	dir := declaredDirection(doc)
+	c, err := extractContent(doc, reqURL, opt)
+	if err != nil {
+		return nil, err
+	}
+	c.NormalizedURL = normalizeURL(reqURL)
+	c.Paywalled = paywallMarked || paywallPrompted && isTruncated(c, opt)
+	c.Completeness = opt.stages.completeness(opt)
+	if strings.TrimSpace(c.Title) == "" {
+		if t := slugTitle(reqURL); t != "" {
+			c.Title, c.TitleSource = t, TitleSourceURL
+		}
+	}
+	if opt.CleanTitle || opt.TitleCase {
+		c.RawTitle = c.Title
+	}
+	if opt.CleanTitle {
+		c.Title = cleanTitle(doc, c.Title)
+	}
+	if opt.TitleCase {
+		c.Title = displayTitle(doc, c.Title, opt)
+	}
+	if len(opt.Transformers) > 0 {
+		if err := transform(c, opt); err != nil {
+			return nil, err
+		}
+		// the dir attribute is the direction of the text before transformed, like translated
+		dir = ""
+	}
+	c.TextDirection = textDirection(dir, c.Title, descriptionText(c, opt))
+	c.Warnings = warnings(c, opt)
+	if opt.ComputeDominantColor && !opt.SkipImageProbing {
+		setDominantColor(c, opt)
+	}
+	c.PlannedRequests = opt.plan.list()
+	return c, nil
+}
+
+func extractContent(doc *goquery.Document, reqURL string, opt *Option) (*Content, error) {
+	// relative URLs are resolved against <base href> if the page has it
+	base := documentBase(doc, reqURL)
+	c := &Content{
+		ThemeColor: themeColor(doc),
+		TileColor:  tileColor(doc),
+		AMPURL:     ampURL(doc, base),
+	}
+	hero := ""
+	if opt.PreferHeroImage {
+		hero = heroImageURL(doc, base)
+	}
+	var shareable, videos []Image
+	if opt.ShareableImagesOnly {
+		shareable = shareableImages(doc, base, opt.MaxImageCount)
+	} else if opt.VideoThumbnails {
+		videos = videoThumbnails(doc, base, opt)
+	}
+
+	metadataStart := time.Now()
+	og, md := lookupMetadata(doc, base, opt)
+	observeStage(opt, StageMetadata, metadataStart)
+	c.PublishedTime = md.PublishedTime
+	c.OriginalSource = originalSource(doc, reqURL, md)
+	c.Sources = sources(doc, base)
+	c.Recipe = recipe(doc)
+	c.Product = product(doc, base)
+	c.Audio = audio(doc, base)
+
+	// the readability rules are used instead if excluded sources have the only descriptions
+	excluded := excludedDescriptionSources(reqURL, opt)
+	metaDesc, metaSource, dropped := metadataDescription(og, md, excluded)
+	useReadability := dropped && metaDesc == "" && !excluded[DescriptionSourceReadability]
+	if (!og.IsEmpty() || !md.IsEmpty()) && !useReadability {
+		c.Tags = tags(doc, nil)
+		c.Title = firstNonEmpty(og.Title, md.Title)
+		if og.Title != "" {
+			c.TitleSource = TitleSourceOpenGraph
+		} else if md.Title != "" {
+			c.TitleSource = TitleSourceMetadata
+		}
+		c.Description = metaDesc
+		if c.Description != "" {
+			c.DescriptionSource = metaSource
+		}
+		c.Author = md.Author
+		if opt.ShareableImagesOnly {
+			c.setShareableImages(shareable)
+			return c, nil
+		}
+		if og.ImageURL != "" {
+			size := &fastimage.ImageSize{Width: 0, Height: 0}
+			if opt.SkipImageProbing {
+				size = openGraphImageSize(doc)
+			}
+			c.Images = []Image{
+				Image{
+					URL:    og.ImageURL,
+					Size:   size,
+					Source: ImageSourceOpenGraph,
 				},
-			}, nil
+			}
+		} else if md.ImageURL != "" {
+			c.Images = []Image{
+				Image{
+					URL:    md.ImageURL,
+					Size:   &fastimage.ImageSize{Width: 0, Height: 0},
+					Source: ImageSourceMetadata,
+				},
+			}
 		}
+		c.PrimaryImage = primaryImage(doc, base, og, md, func() []Image {
+			if len(c.Images) == 0 {
+				c.Images = images(doc, base, opt, hero)
+			}
+			return c.Images
+		}, videos, opt)
+		c.Images = appendVideoThumbnails(c.Images, videos, opt)
+		return c, nil
 	}
 
-	title := strings.TrimSpace(doc.Find("title").First().Text())
-	return &Content{
-		Title:       title,
-		Description: description(doc, opt),
-		Author:      author(doc),
-		Images:      images(doc, reqURL, opt),
-	}, nil
+	c.Title, c.TitleSource = documentTitle(doc, reqURL)
+	article := &articleResult{prepared: doc}
+	if !excluded[DescriptionSourceReadability] {
+		descriptionStart := time.Now()
+		article = extractArticleWith(doc, base, opt)
+		observeStage(opt, StageDescription, descriptionStart)
+	}
+	if c.TitleSource == "" && article.heading != "" {
+		c.Title, c.TitleSource = article.heading, TitleSourceHeading
+	} else if c.TitleSource == "" && c.Title != "" {
+		c.TitleSource = TitleSourceTitle
+	}
+	c.Description, c.Explanation, c.Paragraphs = article.description, article.explanation, article.paragraphs
+	if c.Description != "" {
+		c.DescriptionSource = DescriptionSourceReadability
+		c.Engine = article.engine
+	}
+	c.articleNode = article.node
+	c.Tags = tags(doc, article.top)
+	c.Outline = article.outline
+	c.Candidates = article.candidates
+	c.Fingerprint = article.fingerprint
+	c.Author = firstNonEmpty(md.Author, article.author, author(article.prepared))
+	if opt.ShareableImagesOnly {
+		c.setShareableImages(shareable)
+		return c, nil
+	}
+	if len(article.images) > 0 {
+		c.Images = article.images
+	} else if opt.PreferArticleImages && article.doc != nil {
+		c.Images = images(article.doc, base, opt, hero)
+	}
+	if len(c.Images) == 0 {
+		c.Images = images(article.prepared, base, opt, hero)
+	}
+	c.PrimaryImage = primaryImage(article.prepared, base, og, md, func() []Image {
+		return c.Images
+	}, videos, opt)
+	c.Images = appendVideoThumbnails(c.Images, videos, opt)
+	return c, nil
 }
 
-func description(doc *goquery.Document, opt *Option) string {
-	candidates, err := prepareCandidates(doc, opt)
-	if err != nil {
-		return ""
+func description(doc *goquery.Document, opt *Option) (string, *Explanation) {
+	a := extractArticleWith(doc, "", opt)
+	return a.description, a.explanation
+}
+
+// articleResult is the article extracted by readability rules.
+type articleResult struct {
+	description string
+
+	// doc is the article with the tags (such as <img>) which are stripped for the description.
+	// It is set only if Option.PreferArticleImages is true.
+	doc *goquery.Document
+
+	// paragraphs contains the plain text of each block of the article.
+	paragraphs []string
+
+	// node is the root of the DOM of the article rendered as the description.
+	node *html.Node
+
+	// fingerprint is set only if Option.Fingerprint is true.
+	fingerprint *Fingerprint
+
+	// outline contains the headings of the article.
+	outline []Heading
+
+	// heading is the most important heading inside the best candidate. See bestHeading.
+	heading string
+
+	// top is the best candidate in the document given to the engine, if it has one there.
+	top *html.Node
+
+	// candidates contains the top Option.CandidateCount candidates.
+	candidates []ArticleCandidate
+
+	// engine is the engine which extracted the article.
+	engine EngineName
+
+	// author and images are set only by engines registered by RegisterEngine.
+	author string
+	images []Image
+
+	// prepared is the copy of the document prepared by the last pass (without <script>, <style>
+	// and unlikely candidates), where images are searched. It is the original document if the pass failed.
+	prepared *goquery.Document
+
+	// explanation is set only if Option.Explain is true.
+	explanation *Explanation
+}
+
+// extractArticle extracts the article of doc, retrying with more liberal rules if it is too short.
+// Each pass works on its own copy of doc, since the rules remove and rename nodes
+// and a retry must see the whole page. If opt.ModifyDocument is set, doc itself is used instead.
+func extractArticle(doc *goquery.Document, reqURL string, opt *Option) *articleResult {
+	var exp *Explanation
+	if opt.Explain {
+		exp = &Explanation{}
 	}
-	article, err := getArticle(candidates)
+
+	opt.trace.beginPass(opt)
+	work := doc
+	var origins map[*html.Node]*html.Node
+	if !opt.ModifyDocument {
+		work, origins = cloneDocumentWithOrigins(doc)
+	}
+	candidates, err := prepareCandidates(work, opt)
 	if err != nil {
-		return ""
+		// the timed out walk has stopped, so doc (which is work if opt.ModifyDocument is set)
+		// can be read for images and the author
+		return &articleResult{prepared: doc, explanation: exp, engine: EngineReadability}
 	}
-	cleanedArticle := sanitize(article, candidates, opt)
-	if opt.DescriptionAsPlainText {
-		cleanedArticle = patterns.Tag.ReplaceAllString(cleanedArticle, " ")
-		cleanedArticle = patterns.Trimmable.ReplaceAllString(cleanedArticle, " ")
-
-	}
-	if len(cleanedArticle) < opt.RetryLength {
-		newOpts := copyOption(opt)
-		if newOpts.RemoveUnlikelyCandidates {
-			newOpts.RemoveUnlikelyCandidates = false
-		} else if newOpts.WeightClasses {
-			newOpts.WeightClasses = false
-		} else if newOpts.CleanConditionally {
-			newOpts.CleanConditionally = false
-		} else {
-			return cleanedArticle
+	observeCandidates(opt, candidates)
+	preferArticleAncestor(candidates, opt)
+	if err := rerankCandidates(candidates, opt); err != nil {
+		logger.Warnf("description: %v", err)
+	}
+	opt.trace.setCandidates(candidates, opt)
+	var heading string
+	if candidates != nil && len(candidates.List) > 0 {
+		heading = bestHeading(candidates.List[0].Node.Selection, opt)
+	}
+	result := &articleResult{heading: heading, prepared: work, explanation: exp, engine: EngineReadability,
+		candidates: topCandidates(candidates, opt.CandidateCount)}
+	if candidates != nil && len(candidates.List) > 0 {
+		result.top = candidates.List[0].Node.Get(0)
+		if origins != nil {
+			result.top = origins[result.top]
 		}
-		return description(doc, newOpts)
+		if opt.Fingerprint && result.top != nil {
+			result.fingerprint = fingerprint(result.top)
+		}
+	}
+	if article, err := getArticle(candidates, opt, exp); err == nil {
+		sanitize(article, candidates, opt, exp)
+		result.render(article, reqURL, opt)
+	}
+	if len(result.description) < opt.RetryLength {
+		if next := relaxedOption(opt); next != nil {
+			return extractArticle(doc, reqURL, next)
+		}
+	}
+	return result
+}
+
+// render sets the description, the outline and the paragraphs of r from article,
+// the sanitized article, with the tags stripped or sanitized by opt.HTMLPolicy.
+func (r *articleResult) render(article *goquery.Document, reqURL string, opt *Option) {
+	if opt.PreferArticleImages {
+		r.doc = goquery.CloneDocument(article)
+	}
+	r.outline = outline(article.Selection)
+	if opt.HTMLPolicy != nil && !opt.DescriptionAsPlainText {
+		sanitizeHTML(article.Get(0), opt.HTMLPolicy, reqURL)
+	} else {
+		stripTags(article, reqURL, opt)
+	}
+	if opt.DescriptionAsPlainText {
+		r.description = plainText(article.Selection)
+	} else {
+		r.description = serializeArticle(article)
 	}
+	r.paragraphs = paragraphs(article.Selection)
+	r.node = article.Get(0)
+}
 
-	return cleanedArticle
+// relaxedOption returns a copy of opt with the next rule of retries disabled,
+// in the order of RemoveUnlikelyCandidates, WeightClasses and CleanConditionally,
+// or nil if all of them are already disabled.
+func relaxedOption(opt *Option) *Option {
+	next := copyOption(opt)
+	switch {
+	case next.RemoveUnlikelyCandidates:
+		next.RemoveUnlikelyCandidates = false
+	case next.WeightClasses:
+		next.WeightClasses = false
+	case next.CleanConditionally:
+		next.CleanConditionally = false
+	default:
+		return nil
+	}
+	return next
 }
 
 func prepareCandidates(doc *goquery.Document, opt *Option) (*candidates, error) {
-	doc.Find("style, script").Each(func(i int, s *goquery.Selection) {
-		s.Remove()
-	})
+	removeResponsiveDuplicates(doc, opt)
 
-	err := removeUnlikelyCandidates(doc, opt)
+	err := prepareDocument(doc, opt)
 	if err != nil {
-		logger.Printf("prepareCandidates failed: %s", err)
-		return nil, err
-	}
-	err = transformMisusedDivsIntoP(doc, opt)
-	if err != nil {
-		logger.Printf("prepareCandidates failed: %s", err)
+		logger.Warnf("prepareCandidates failed: %s", err)
 		return nil, err
 	}
 
 	return getCandidates(doc, opt)
 }
 
-func getArticle(candidates *candidates) (*goquery.Document, error) {
+// preferArticleAncestor puts the closest <article> ancestor of the best candidate, if exists,
+// before it in the candidates, since the whole <article> is more likely the primary content
+// than a part of it. The former best candidate becomes the second one.
+func preferArticleAncestor(c *candidates, opt *Option) {
+	if c == nil || len(c.List) == 0 {
+		return
+	}
+	best := c.List[0]
+	article := best.Node.Selection.ParentsFiltered("article").First()
+	if goquery.NodeName(best.Node.Selection) == "article" || article.Length() == 0 {
+		return
+	}
+
+	sel := newMySelection(article)
+	ac, ok := c.Map[sel.HTML()]
+	if !ok {
+		score := scoreNode(article, opt)
+		ac = candidate{Node: sel, Score: score, RawScore: score}
+	}
+	ac.Score = math.Max(ac.Score, best.Score)
+	c.Map[sel.HTML()] = ac
+	list := candidateList{ac}
+	for _, x := range c.List {
+		if x.Node.HTML() != sel.HTML() {
+			list = append(list, x)
+		}
+	}
+	c.List = list
+}
+
+func getArticle(candidates *candidates, opt *Option, exp *Explanation) (*goquery.Document, error) {
 	if candidates == nil || len(candidates.List) == 0 {
 		return nil, fmt.Errorf("Empty candidates")
 	}
 	bestCandidate := candidates.List[0]
-	siblingScoreThreshold := math.Max(10.0, bestCandidate.Score*0.2)
+	thresholds := siblingThresholds(opt)
+	siblingScoreThreshold := thresholds.scoreThreshold(bestCandidate.Score)
+	exp.setSiblingScoreThreshold(siblingScoreThreshold)
 	output, _ := goquery.NewDocumentFromReader(strings.NewReader("<div></div>"))
-	re := regexp.MustCompile("\\.( |$)")
 	bestCandidate.Node.Parent().Children().Each(func(i int, s *goquery.Selection) {
 		sel := newMySelection(s)
+		score := candidates.Map[sel.HTML()].Score
 		append := false
+		var code ReasonCode
 		if sel.HTML() == bestCandidate.Node.HTML() {
 			append = true
+			code = ReasonBestCandidate
+		}
+		if !append && opt.RemoveUnlikelyCandidates && pageElements[goquery.NodeName(s)] {
+			opt.trace.addRemoval(RuleUnlikelyCandidate, "", s.Get(0))
+			exp.addSibling(sel, score, false, "")
+			return
 		}
-		if candidates.Map[sel.HTML()].Score >= siblingScoreThreshold {
+		if !append && score >= siblingScoreThreshold {
 			append = true
+			code = ReasonSiblingScore
 		}
 
-		if goquery.NodeName(s) == "p" {
+		if !append && goquery.NodeName(s) == "p" {
 			ld := linkDensity(s)
 			text := s.Text()
 			length := len(text)
 
-			if length > 80 && ld < 0.25 {
+			if length > thresholds.ParagraphLength && ld < thresholds.MaxLinkDensity {
 				append = true
-			} else if length < 80 && ld == 0 && re.FindString(text) != "" {
+				code = ReasonLongParagraph
+			} else if length < thresholds.ParagraphLength && ld == 0 && patterns.SentenceEnd.FindString(text) != "" {
 				append = true
+				code = ReasonShortSentence
 			}
 		}
+		exp.addSibling(sel, score, append, code)
 
 		if append {
 			sCopy := s.Clone()
+			if code != ReasonBestCandidate && opt.RemoveUnlikelyCandidates {
+				removePageElements(s.Get(0), sCopy.Get(0), opt)
+			}
 			if goquery.NodeName(s) != "div" && goquery.NodeName(s) != "p" {
 				sCopy.Get(0).Data = "div"
 			}
@@ -319,59 +1237,107 @@ func getArticle(candidates *candidates) (*goquery.Document, error) {
 	return output, nil
 }
 
-func sanitize(doc *goquery.Document, candidates *candidates, opt *Option) string {
-	doc.Find("h1, h2, h3, h4, h5, h6").Each(func(i int, s *goquery.Selection) {
-		if classWeight(s, opt) < 0 || linkDensity(s) > 0.33 {
-			s.Remove()
+// pageElements are HTML5 elements which are the header or the footer of the page outside
+// the best candidate, and of the article inside it.
+var pageElements = map[string]bool{"header": true, "footer": true}
+
+// removePageElements removes pageElements from clone, a copy of n made by getArticle.
+// The removals are traced by the nodes of n, since clone isn't in the document.
+func removePageElements(n, clone *html.Node, opt *Option) {
+	for c, cc := n.FirstChild, clone.FirstChild; c != nil && cc != nil; c = c.NextSibling {
+		next := cc.NextSibling
+		if c.Type == html.ElementNode && pageElements[c.Data] {
+			opt.trace.addRemoval(RuleUnlikelyCandidate, "", c)
+			clone.RemoveChild(cc)
+		} else {
+			removePageElements(c, cc, opt)
 		}
-	})
-	doc.Find("form, object, iframe, embed").Each(func(i int, s *goquery.Selection) {
-		s.Remove()
-	})
+		cc = next
+	}
+}
 
-	if opt.RemoveEmptyNodes {
-		doc.Find("p").Each(func(i int, s *goquery.Selection) {
-			if strings.TrimSpace(s.Text()) == "" {
-				s.Remove()
+func sanitize(doc *goquery.Document, candidates *candidates, opt *Option, exp *Explanation) {
+	for _, n := range doc.Nodes {
+		sanitizeNode(doc, n, opt)
+	}
+	cleanConditionally(doc, candidates, "table, ul, div", opt, exp)
+}
+
+// sanitizeNode removes headers with negative class weight or many links, embedded content
+// and, if opt.RemoveEmptyNodes is set, empty paragraphs from the subtree of n in a single walk.
+// Headers are checked before their descendants are touched, and paragraphs after.
+func sanitizeNode(doc *goquery.Document, n *html.Node, opt *Option) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			switch {
+			case embeddedElements[c.Data]:
+				opt.trace.addRemoval(RuleEmbeddedContent, "", c)
+				n.RemoveChild(c)
+			case headerElements[c.Data] && isBadHeader(doc.FindNodes(c), opt):
+				opt.trace.addRemoval(RuleBadHeader, "", c)
+				n.RemoveChild(c)
+			default:
+				sanitizeNode(doc, c, opt)
+				if opt.RemoveEmptyNodes && c.Data == "p" && isBlank(c) {
+					opt.trace.addRemoval(RuleEmptyParagraph, "", c)
+					n.RemoveChild(c)
+				}
 			}
-		})
+		}
+		c = next
 	}
+}
 
-	cleanConditionally(doc, candidates, "table, ul, div", opt)
+var headerElements = map[string]bool{"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true}
+
+// embeddedElements are removed from the article with their descendants.
+var embeddedElements = map[string]bool{"form": true, "object": true, "iframe": true, "embed": true}
+
+func isBadHeader(s *goquery.Selection, opt *Option) bool {
+	return classWeight(s, opt) < 0 || linkDensity(s) > 0.33
+}
+
+// spaceyTags are elements whose text is separated from the text around them when they are stripped.
+var spaceyTags = map[string]bool{
+	"br": true, "hr": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"dl": true, "dd": true, "ol": true, "li": true, "ul": true, "address": true, "blockquote": true, "center": true,
+}
 
+// stripTags replaces all elements of doc except <div> and <p> with their inner text,
+// and removes attributes of <div> and <p>. The text is inserted as text nodes, not re-parsed
+// as HTML, so that decoded entities like "&lt;" stay literal text.
+// If opt.KeepFigures is set, <figure>s are kept with their <figcaption>s and <img>s. See figureImage.
+func stripTags(doc *goquery.Document, reqURL string, opt *Option) {
 	whitelist := map[string]bool{"div": true, "p": true}
-	st := []string{"br", "hr", "h1", "h2", "h3", "h4", "h5", "h6", "dl", "dd",
-		"ol", "li", "ul", "address", "blockquote", "center"}
-	spacey := map[string]bool{}
-	for _, tag := range st {
-		spacey[tag] = true
+	if opt.KeepFigures {
+		whitelist["figure"], whitelist["figcaption"] = true, true
 	}
-
 	doc.Find("*").Each(func(i int, s *goquery.Selection) {
 		tagName := goquery.NodeName(s)
 		// If element is in whitelist, delete all its attributes
 		if whitelist[tagName] {
 			s.Nodes[0].Attr = []html.Attribute{}
+		} else if tagName == "img" && opt.KeepFigures && s.ParentsFiltered("figure").Length() > 0 {
+			figureImage(s, reqURL)
 		} else {
-			// If element is root, replace the node as a text node
-			if s.Parent() == nil {
-				s.ReplaceWithHtml(s.Text())
-			} else {
-				if spacey[tagName] {
-					s.ReplaceWithHtml(" " + s.Text() + " ")
-				} else {
-					s.ReplaceWithHtml(s.Text())
-				}
+			text := s.Text()
+			// If element is not root, separate the text of spacey elements
+			if s.Parent() != nil && spaceyTags[tagName] {
+				text = " " + text + " "
 			}
+			s.ReplaceWithNodes(&html.Node{Type: html.TextNode, Data: text})
 		}
 	})
+}
 
-	re := regexp.MustCompile("[\r\n\f]+")
+// serializeArticle returns the HTML of doc, the sanitized article, with line breaks collapsed.
+func serializeArticle(doc *goquery.Document) string {
 	html, _ := doc.Html()
-	return re.ReplaceAllString(html, "\n")
+	return replaceAllChunked(patterns.Newlines, html, "\n")
 }
 
-func cleanConditionally(doc *goquery.Document, candidates *candidates, selector string, opt *Option) {
+func cleanConditionally(doc *goquery.Document, candidates *candidates, selector string, opt *Option, exp *Explanation) {
 	if !opt.CleanConditionally {
 		return
 	}
@@ -381,143 +1347,122 @@ func cleanConditionally(doc *goquery.Document, candidates *candidates, selector
 		weight := classWeight(s, opt)
 		score := candidates.Map[sel.HTML()].Score
 		tagName := goquery.NodeName(s)
+		d := CleanDecision{Node: sel.String(), Score: score, Weight: weight, Kept: true}
 
-		if weight+score < 0 {
+		st := newSubtreeStats(s.Get(0))
+		if weight+score < 0 && opt.CleanRules.Enabled(ReasonNegativeScore) {
+			opt.trace.addRemoval(RuleCleanConditionally, ReasonNegativeScore, s.Get(0))
 			s.Remove()
-		} else if strings.Count(s.Text(), ",") < 11 {
-			counts := map[string]int{}
-			for _, tag := range []string{"p", "img", "li", "a", "embed", "input"} {
-				counts[tag] = s.Find(tag).Length()
-				counts["li"] -= 100
-				// For every img under a noscript tag discount one from the count to avoid double counting
-				counts["img"] -= s.Find("noscript").Find("img").Length()
-				cl := len(strings.TrimSpace(s.Text()))
-				ld := linkDensity(s)
-				reason := conditionalCleanReason(tagName, counts, cl, opt, weight, ld)
-				if reason != "" {
-					s.Remove()
-				}
+			d.Kept = false
+			d.Code = ReasonNegativeScore
+		} else if strings.Count(st.text, ",") > 10 && opt.CleanRules.Enabled(ReasonManyCommas) {
+			d.Code = ReasonManyCommas
+		} else {
+			c := newCleanStats(tagName, weight, st)
+			d.Counts, d.TextLength, d.LinkDensity = c.counts, c.textLength, c.linkDensity
+			d.Code = ReasonPassed
+			if code := conditionalCleanCode(c, opt); code != "" {
+				opt.trace.addRemoval(RuleCleanConditionally, code, s.Get(0))
+				s.Remove()
+				d.Kept = false
+				d.Code = code
 			}
 		}
+		exp.addCleaning(d)
 	})
 }
 
-func conditionalCleanReason(tagName string, counts map[string]int,
-	cl int, opt *Option, weight float64, ld float64) string {
-	if counts["img"] > counts["p"] && counts["img"] > 1 {
-		return "too many images"
-	} else if counts["li"] > counts["p"] && tagName != "ul" && tagName != "ok" {
-		return "more <li>s than <p>s"
-	} else if counts["input"]*3 > counts["p"] {
-		return "<p>s less than 3 * <inputs>s"
-	} else if cl < opt.MinTextLength && counts["img"] != 1 {
-		return "too short content length without a single image"
-	} else if (weight < 25 && ld > 0.2) || (weight >= 25 && ld > 0.5) {
-		return "too many links for its weight"
-	} else if (counts["embed"] == 1 && cl < 75) || counts["embed"] > 1 {
-		return "<embed>s with too short content length, or too many <embed>s"
-	} else {
-		return ""
-	}
+// unlikelyElements are HTML5 elements which rarely contain the primary content.
+// <header> and <footer> are pageElements instead, since those of an <article> are a part of it.
+var unlikelyElements = map[string]bool{
+	"aside": true,
+	"nav":   true,
 }
 
-func removeUnlikelyCandidates(doc *goquery.Document, opt *Option) error {
-	if !opt.RemoveUnlikelyCandidates {
-		return nil
-	}
-
+// prepareDocument removes <script>, <style> and, if opt.RemoveUnlikelyCandidates is set,
+// unlikely candidates from doc, and transforms <div>s without block element children into <p>
+// in a single walk of the tree.
+func prepareDocument(doc *goquery.Document, opt *Option) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	ch := make(chan error)
-	quit := false
+	done := make(chan struct{})
 
 	go func() {
-		logger.Println("goroutine@removeUnlikelyCandidates started")
-		defer logger.Println("goroutine@removeUnlikelyCandidates finished")
+		logger.Println("goroutine@prepareDocument started")
+		defer logger.Println("goroutine@prepareDocument finished")
+		defer close(done)
 
-		sel := doc.Find("*")
-		sel.EachWithBreak(func(i int, s *goquery.Selection) bool {
-			if quit {
-				return false
-			}
-			cls, _ := s.Attr("class")
-			id, _ := s.Attr("id")
-			str := cls + id
-			if patterns.UnlikelyCandidates.FindString(str) != "" &&
-				patterns.OKMaybeItsACandidate.FindString(str) == "" &&
-				goquery.NodeName(s) != "html" &&
-				goquery.NodeName(s) != "body" {
-				s.Remove()
-			}
-			return true
-		})
+		for _, n := range doc.Nodes {
+			prepareNode(ctx, n, opt)
+		}
 
 		select {
 		case ch <- nil:
-			logger.Println("goroutine@removeUnlikelyCandidates sent data to ch")
+			logger.Println("goroutine@prepareDocument sent data to ch")
 		case <-ctx.Done():
-			logger.Println("goroutine@removeUnlikelyCandidates didn't send data to ch (context closed)")
+			logger.Println("goroutine@prepareDocument didn't send data to ch (context closed)")
 		}
 	}()
 
 	timeout := time.After(time.Duration(opt.DescriptionExtractionTimeout) * time.Millisecond)
 	select {
 	case err := <-ch:
-		logger.Println("receiver@removeUnlikelyCandidates got data from ch")
+		logger.Println("receiver@prepareDocument got data from ch")
 		return err
 	case <-timeout:
-		quit = true
-		err := fmt.Errorf("removeUnlikelyCandidates timed out")
-		logger.Println(err)
+		err := fmt.Errorf("prepareDocument timed out")
+		logger.Warnf("%v", err)
+		incTimeout(opt, StageDescription)
+		// the walk checks ctx at every node, so it stops right away and doc is no longer
+		// modified once this returns, even if it is the caller's document (see Option.ModifyDocument)
+		cancel()
+		<-done
 		return err
 	}
 }
 
-func transformMisusedDivsIntoP(doc *goquery.Document, opt *Option) error {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	ch := make(chan error)
-	quit := false
+// prepareNode prepares the subtree of n as described in prepareDocument.
+// Children are prepared before n, so that removed children don't keep n from being transformed.
+func prepareNode(ctx context.Context, n *html.Node, opt *Option) {
+	for c := n.FirstChild; c != nil && ctx.Err() == nil; {
+		next := c.NextSibling
+		if c.Type != html.ElementNode {
+			c = next
+			continue
+		}
+		switch {
+		case c.Data == "script" || c.Data == "style":
+			opt.trace.addRemoval(RuleScriptStyle, "", c)
+			n.RemoveChild(c)
+		case opt.RemoveUnlikelyCandidates && c.Data != "html" && c.Data != "body" &&
+			(isUnlikelyNode(c, opt.classPatterns()) || unlikelyElements[c.Data]):
+			opt.trace.addRemoval(RuleUnlikelyCandidate, "", c)
+			n.RemoveChild(c)
+		default:
+			prepareNode(ctx, c, opt)
+		}
+		c = next
+	}
+	if n.Type == html.ElementNode && n.Data == "div" && !hasDivToPChild(n) && ctx.Err() == nil {
+		n.Data = "p"
+	}
+}
 
-	go func() {
-		logger.Println("goroutine@transformMisusedDivsIntoP started")
-		defer logger.Println("goroutine@transformMisusedDivsIntoP finished")
+// divToPElements are the children which keep a <div> from being transformed into <p>.
+var divToPElements = map[string]bool{"a": true, "blockquote": true, "dl": true, "div": true, "img": true,
+	"ol": true, "p": true, "pre": true, "table": true, "ul": true}
 
-		sel := doc.Find("*")
-		sel.EachWithBreak(func(i int, s *goquery.Selection) bool {
-			if quit {
-				return false
-			}
-			if goquery.NodeName(s) == "div" {
-				innerHTML, _ := s.Html()
-				if patterns.DivToPElements.FindString(innerHTML) == "" {
-					s.Get(0).Data = "p"
-				}
-			}
+// hasDivToPChild returns true if n has a child element of divToPElements.
+// Only children are checked, so that no subtree is walked twice.
+func hasDivToPChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && divToPElements[c.Data] {
 			return true
-		})
-
-		select {
-		case ch <- nil:
-			logger.Println("goroutine@transformMisusedDivsIntoP sent data to ch")
-		case <-ctx.Done():
-			logger.Println("goroutine@transformMisusedDivsIntoP didn't send data to ch (context closed)")
 		}
-	}()
-
-	timeout := time.After(time.Duration(opt.DescriptionExtractionTimeout) * time.Millisecond)
-	select {
-	case err := <-ch:
-		logger.Println("receiver@transformMisusedDivsIntoP got data from ch")
-		return err
-	case <-timeout:
-		quit = true
-		err := fmt.Errorf("transformMisusedDivsIntoP timed out")
-		logger.Println(err)
-		return err
 	}
+	return false
 }
 
 func getCandidates(doc *goquery.Document, opt *Option) (*candidates, error) {
@@ -525,60 +1470,46 @@ func getCandidates(doc *goquery.Document, opt *Option) (*candidates, error) {
 	defer cancel()
 
 	ch := make(chan *candidates)
-	quit := false
+	done := make(chan struct{})
 
 	go func() {
 		logger.Println("goroutine@getCandidates started")
 		defer logger.Println("goroutine@getCandidates finished")
-
-		cMap := map[string]candidate{}
-		doc.Find("p, td").EachWithBreak(func(i int, s *goquery.Selection) bool {
-			if quit {
-				return false
-			}
-			parent := s.Parent()
-			var grandParent *goquery.Selection
-			if parent == nil {
-				grandParent = nil
-			} else {
-				grandParent = parent.Parent()
-			}
-			innerText := s.Text()
-
-			if len(innerText) < opt.MinTextLength {
-				return true
-			}
-
-			score := 1.0
-			score += float64(len(strings.Split(innerText, ",")))
-			score += math.Min((float64(len(innerText)) / 100.0), 3.0)
-
-			psel := newMySelection(parent)
-			if _, ok := cMap[psel.HTML()]; !ok {
-				cMap[psel.HTML()] = candidate{Node: psel, Score: scoreNode(parent, opt) + score}
+		defer close(done)
+
+		// Only the first paragraph of a node gives a score to it.
+		var scores []paragraphScore
+		seen := map[*html.Node]bool{}
+		for _, ps := range collectParagraphScores(ctx, doc, opt) {
+			if !seen[ps.node] {
+				seen[ps.node] = true
+				scores = append(scores, ps)
 			}
-
-			if grandParent != nil {
-				gsel := newMySelection(grandParent)
-				if _, ok := cMap[gsel.HTML()]; !ok {
-					cMap[gsel.HTML()] = candidate{
-						Node:  gsel,
-						Score: scoreNode(grandParent, opt) + (score / 2.0),
-					}
-				}
-			}
-			return true
-		})
+		}
 
 		// Scale the final candidates score based on link density.
 		// Good content should have a relatively small link density (5% or less)
 		// and be mostly unaffected by this operation.
-		for k, v := range cMap {
-			cMap[k] = candidate{Node: v.Node, Score: v.Score * (1 - linkDensity(v.Node.Selection))}
+		keys := make([]string, len(scores))
+		list := make([]candidate, len(scores))
+		forEach(len(scores), opt.ParallelScoring, func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
+			sel := newMySelection(doc.FindNodes(scores[i].node))
+			raw := scoreNode(sel.Selection, opt) + scores[i].score
+			keys[i] = sel.HTML()
+			list[i] = candidate{Node: sel, Score: raw * (1 - linkDensity(sel.Selection)), RawScore: raw}
+		})
+		cMap := map[string]candidate{}
+		for i, key := range keys {
+			if _, ok := cMap[key]; !ok && list[i].Node != nil {
+				cMap[key] = list[i]
+			}
 		}
 
 		select {
-		case ch <- &candidates{Map: cMap, List: sortCandidates(cMap)}:
+		case ch <- &candidates{Map: cMap, List: sortCandidates(keys, cMap)}:
 			logger.Println("goroutine@getCandidates sent data to ch")
 		case <-ctx.Done():
 			logger.Println("goroutine@getCandidates didn't send data to ch (context canceled)")
@@ -589,20 +1520,25 @@ func getCandidates(doc *goquery.Document, opt *Option) (*candidates, error) {
 	for {
 		select {
 		case result := <-ch:
-			quit = true
 			logger.Println("receiver@getCandidates got data from ch")
 			return result, nil
 		case <-timeout:
-			quit = true
 			err := fmt.Errorf("getCandidates timed out")
-			logger.Println(err)
+			logger.Warnf("%v", err)
+			incTimeout(opt, StageDescription)
+			// scoring checks ctx at every node, so it stops right away
+			cancel()
+			<-done
 			return nil, err
 		}
 	}
 }
 
 var elemScores = map[string]float64{
+	"article":    10,
+	"main":       8,
 	"div":        5,
+	"section":    3,
 	"blockquote": 3,
 	"form":       -3,
 	"th":         -5,
@@ -622,45 +1558,61 @@ func classWeight(s *goquery.Selection, opt *Option) float64 {
 		return weight
 	}
 
+	p := opt.classPatterns()
 	if c, _ := s.Attr("class"); c != "" {
-		if patterns.Negative.FindString(c) != "" {
+		if p.negative.FindString(c) != "" {
 			weight -= 25.0
 		}
-		if patterns.Positive.FindString(c) != "" {
+		if p.positive.FindString(c) != "" {
 			weight += 25.0
 		}
 	}
 	if i, _ := s.Attr("id"); i != "" {
-		if patterns.Negative.FindString(i) != "" {
+		if p.negative.FindString(i) != "" {
 			weight -= 25.0
 		}
-		if patterns.Positive.FindString(i) != "" {
+		if p.positive.FindString(i) != "" {
 			weight += 25.0
 		}
 	}
 	return weight
 }
 
+// linkDensity returns the ratio of the length of link texts to the length of the text of s.
+// It is 0 for s without text, rather than NaN which can't be encoded in JSON (see Explanation).
 func linkDensity(s *goquery.Selection) float64 {
 	linkTexts := s.Find("a").Map(func(i int, s *goquery.Selection) string {
 		return s.Text()
 	})
 	linkLen := float64(len(strings.Join(linkTexts, "")))
 	textLen := float64(len(s.Text()))
+	if textLen == 0 {
+		return 0
+	}
 	return linkLen / textLen
 }
 
 type mySelection struct {
 	*goquery.Selection
+
+	// html caches the result of HTML, which is used as the key of candidates many times.
+	html     string
+	rendered bool
 }
 
 func newMySelection(s *goquery.Selection) *mySelection {
-	return &mySelection{s}
+	return &mySelection{Selection: s}
 }
 
+// HTML returns the inner HTML of s, which is rendered only once.
 func (s *mySelection) HTML() string {
-	html, _ := s.Html()
-	return html
+	if !s.rendered {
+		if s.Length() > 0 {
+			s.html = innerHTML(s.Get(0))
+		}
+		s.rendered = true
+	}
+	return s.html
 }
 
 func (s *mySelection) String() string {
@@ -676,6 +1628,9 @@ func (s *mySelection) String() string {
 type candidate struct {
 	Node  *mySelection
 	Score float64
+
+	// RawScore is the score before scaling by link density.
+	RawScore float64
 }
 
 func (c candidate) String() string {
@@ -702,109 +1657,336 @@ type candidates struct {
 	List candidateList
 }
 
-func sortCandidates(candidates map[string]candidate) candidateList {
-	cl := make(candidateList, len(candidates))
-	i := 0
-	for _, v := range candidates {
-		cl[i] = v
-		i++
+// sortCandidates returns the candidates of keys ordered by score. Candidates with the same score
+// are kept in the order of keys (the document order of their first paragraphs), so that
+// the best candidate doesn't depend on the iteration order of the map.
+func sortCandidates(keys []string, candidates map[string]candidate) candidateList {
+	cl := make(candidateList, 0, len(candidates))
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if c, ok := candidates[key]; ok && !seen[key] {
+			seen[key] = true
+			cl = append(cl, c)
+		}
 	}
-	sort.Sort(sort.Reverse(cl))
+	sort.Stable(sort.Reverse(cl))
 	return cl
 }
 
-func images(doc *goquery.Document, reqURL string, opt *Option) []Image {
+// images returns images in the document at least Option.MinImageWidth x Option.MinImageHeight,
+// ordered by Option.SortImagesBy. The hero image, if not empty and large enough, is always first.
+func images(doc *goquery.Document, reqURL string, opt *Option, hero string) []Image {
+	defer observeStage(opt, StageImages, time.Now())
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ch := make(chan *Image)
-
-	imgs := []Image{}
-	loopCnt := uint(0)
-	doc.Find("img").EachWithBreak(func(i int, s *goquery.Selection) bool {
-		loopCnt++
-		if loopCnt > opt.CheckImageLoopCount {
-			return false
-		}
+	ch := make(chan probeResult)
 
-		src, err := absPath(s.AttrOr("src", s.AttrOr("data-original", "")), reqURL)
-		if err != nil {
-			return true
-		}
-		if !isSupportedImage(src, opt) {
-			return true
-		}
-
-		w, _ := strconv.Atoi(s.AttrOr("width", "0"))
-		h, _ := strconv.Atoi(s.AttrOr("height", "0"))
-		logger.Printf("loopCnt: %v, src: %v, w: %v, h: %v\n", loopCnt, src, w, h)
+	// sem bounds the image requests sent at once if opt.ImageFetchConcurrency is set
+	var sem chan struct{}
+	if opt.ImageFetchConcurrency > 0 {
+		sem = make(chan struct{}, opt.ImageFetchConcurrency)
+	}
 
+	loopCnt := uint(0)
+	launched := 0
+	probe := func(src string, w, h int, source ImageSource, alt, caption string) {
+		index := launched
+		launched++
 		go func(loopCnt uint) {
 			logger.Printf("goroutine(%v) started: src: %v", loopCnt, src)
 			defer func() {
 				if err := recover(); err != nil {
-					logger.Printf("checkImageSize error: %v, src: %v", err, src)
+					logger.Warnf("checkImageSize error: %v, src: %v", err, src)
 				}
 
 				logger.Printf("goroutine(%v) finished", loopCnt)
 			}()
 
-			img := checkImageSize(src, w, h, opt)
+			if sem != nil && (w == 0 || h == 0) && !isDataURI(src) {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					return
+				}
+			}
+			img, signature := checkImageSize(src, w, h, opt)
+			img.Source = source
+			img.Caption = caption
 			select {
-			case ch <- img:
+			case ch <- probeResult{index: index, img: img, signature: signature, alt: alt}:
 				logger.Printf("goroutine(%v) sent data to ch", loopCnt)
 			case <-ctx.Done():
 				logger.Printf("goroutine(%v) didn't send data to ch (context canceled)", loopCnt)
 			}
 		}(loopCnt)
+	}
+
+	heroIndex := -1
+	requested := uint(0)
+	// seen contains the images found, so that images inside <noscript> duplicating
+	// their lazy-loaded <img>s are skipped.
+	seen := map[string]bool{}
+	candidate := func(s *goquery.Selection, noscript bool, caption string) bool {
+		loopCnt++
+		if opt.MaxImagesToParse > 0 && loopCnt > opt.MaxImagesToParse {
+			return false
+		}
+
+		rawSrc, source := imgSrc(s)
+		if noscript && source == ImageSourceArticle {
+			source = ImageSourceNoscript
+		}
+		src, err := absPath(rawSrc, reqURL)
+		if err != nil {
+			return true
+		}
+		if noscript && seen[src] {
+			return true
+		}
+		seen[src] = true
+		if !isSupportedImage(src, opt) {
+			return true
+		}
+
+		w, _ := strconv.Atoi(s.AttrOr("width", "0"))
+		h, _ := strconv.Atoi(s.AttrOr("height", "0"))
+		logger.Printf("loopCnt: %v, src: %v, w: %v, h: %v\n", loopCnt, src, w, h)
+		if opt.SkipImageProbing {
+			w, h = srcsetSize(s, rawSrc, w, h)
+		}
+		if (w == 0 || h == 0) && !isDataURI(src) {
+			if opt.SkipImageProbing || requested >= opt.CheckImageLoopCount {
+				return true
+			}
+			requested++
+			if opt.DryRun {
+				opt.plan.add(RequestImageProbe, src)
+				return true
+			}
+		}
 
+		if src == hero && heroIndex < 0 {
+			heroIndex = launched
+		}
+		probe(src, w, h, source, s.AttrOr("alt", ""), caption)
 		return true
+	}
+	doc.Find("img, video[poster], noscript").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		caption := figureCaption(s)
+		if goquery.NodeName(s) != "noscript" {
+			return candidate(s, s.ParentsFiltered("noscript").Length() > 0, caption)
+		}
+		more := true
+		eachNoscriptImage(s, func(img *goquery.Selection) bool {
+			more = candidate(img, true, firstNonEmpty(figureCaption(img), caption))
+			return more
+		})
+		return more
 	})
+	// the hero image is probed even if it is not in the first CheckImageLoopCount images
+	// or it was removed from doc during description extraction.
+	if hero != "" && heroIndex < 0 && isSupportedImage(hero, opt) && !opt.SkipImageProbing {
+		if opt.DryRun {
+			opt.plan.add(RequestImageProbe, hero)
+		} else {
+			heroIndex = launched
+			probe(hero, 0, 0, ImageSourceArticle, "", "")
+		}
+	}
+
+	if launched == 0 {
+		return []Image{}
+	}
 
-	timeout := time.After(time.Duration(opt.ImageRequestTimeout+50) * time.Millisecond)
+	// results are indexed in document order. nil means the probe has not finished yet.
+	results := make([]*probeResult, launched)
+	received := 0
+	probingTimeout := opt.ImageProbingTimeout
+	if probingTimeout == 0 {
+		probingTimeout = opt.ImageRequestTimeout + 50
+	}
+	timeout := time.After(time.Duration(probingTimeout) * time.Millisecond)
 	for {
 		select {
-		case result := <-ch:
-			if result.Size != nil &&
-				result.Size.Width >= opt.MinImageWidth &&
-				result.Size.Height >= opt.MinImageHeight {
-				imgs = append(imgs, *result)
-			}
-			if len(imgs) >= opt.MaxImageCount {
-				return imgs
+		case r := <-ch:
+			received++
+			results[r.index] = &r
+			if received == launched ||
+				!waitsForAllImages(opt) && leadingImagesResolved(results, heroIndex, opt) {
+				return orderImages(results, heroIndex, opt)
 			}
 		case <-timeout:
-			logger.Printf("checkImageSize timed out: reqURL: %s", reqURL)
-			return imgs
+			logger.Warnf("checkImageSize timed out: reqURL: %s", reqURL)
+			incTimeout(opt, StageImages)
+			return orderImages(results, heroIndex, opt)
 		}
 	}
 }
 
-func isSupportedImage(src string, opt *Option) bool {
-	for _, ext := range opt.IgnoreImageFormat {
-		if strings.Contains(src, ext) {
+type probeResult struct {
+	index int
+	img   *Image
+
+	// signature identifies the content of the image. It is empty unless
+	// Option.DedupeImagesBySignature is set and the image is requested.
+	signature string
+
+	// alt is the alt text of the element of the image.
+	alt string
+}
+
+// waitsForAllImages returns true if Option.SortImagesBy compares all images,
+// so every image request is waited for.
+func waitsForAllImages(opt *Option) bool {
+	return opt.SortImagesBy == ImageOrderArea || opt.SortImagesBy == ImageOrderScore
+}
+
+// leadingImagesResolved returns true if the hero image and the first Option.MaxImageCount
+// large enough images in document order are known, so pending probes cannot change the result.
+func leadingImagesResolved(results []*probeResult, heroIndex int, opt *Option) bool {
+	if heroIndex >= 0 && results[heroIndex] == nil {
+		return false
+	}
+	n := len(results)
+	for i, r := range results {
+		if r == nil {
+			n = i
+			break
+		}
+	}
+	large, _ := largeImages(results, n, heroIndex, opt)
+	return len(large) >= opt.MaxImageCount
+}
+
+// orderImages returns up to Option.MaxImageCount large enough images of results
+// ordered by Option.SortImagesBy, with the hero image first.
+func orderImages(results []*probeResult, heroIndex int, opt *Option) []Image {
+	large, hasHero := largeImages(results, len(results), heroIndex, opt)
+	rest := large
+	if hasHero {
+		rest = large[1:]
+	}
+	switch opt.SortImagesBy {
+	case ImageOrderArea:
+		sort.SliceStable(rest, func(i, j int) bool {
+			return rest[i].img.area() > rest[j].img.area()
+		})
+	case ImageOrderScore:
+		maxArea := uint64(0)
+		for _, r := range rest {
+			if a := r.img.area(); a > maxArea {
+				maxArea = a
+			}
+		}
+		scores := map[*probeResult]float64{}
+		for _, r := range rest {
+			scores[r] = imageScore(*r.img, r.alt, r.index, len(results), maxArea)
+		}
+		sort.SliceStable(rest, func(i, j int) bool {
+			return scores[rest[i]] > scores[rest[j]]
+		})
+	}
+
+	imgs := []Image{}
+	for _, r := range large {
+		if len(imgs) >= opt.MaxImageCount {
+			break
+		}
+		imgs = append(imgs, *r.img)
+	}
+	return imgs
+}
+
+// largeImages returns the results of large enough images of the hero image and results[:n]
+// in document order, with the hero image first if hasHero is true. If Option.DedupeImages is set,
+// variants of an image already returned, such as the same image in another size, are skipped.
+func largeImages(results []*probeResult, n, heroIndex int, opt *Option) (large []*probeResult, hasHero bool) {
+	keys := map[string]bool{}
+	signatures := map[string]bool{}
+	add := func(r *probeResult) bool {
+		if r == nil || !isLargeEnough(r.img, opt) {
 			return false
 		}
+		if opt.DedupeImages {
+			key := imageKey(r.img.URL)
+			if keys[key] || signatures[r.signature] {
+				return false
+			}
+			keys[key] = true
+			if r.signature != "" {
+				signatures[r.signature] = true
+			}
+		}
+		large = append(large, r)
+		return true
 	}
-	return true
+
+	if heroIndex >= 0 {
+		hasHero = add(results[heroIndex])
+	}
+	for i, r := range results[:n] {
+		if i != heroIndex {
+			add(r)
+		}
+	}
+	return large, hasHero
+}
+
+func isLargeEnough(img *Image, opt *Option) bool {
+	return img != nil && img.Size != nil &&
+		img.Size.Width >= opt.MinImageWidth &&
+		img.Size.Height >= opt.MinImageHeight
 }
 
-func checkImageSize(src string, widthFromAttr, heightFromAttr int, opt *Option) *Image {
+// isSupportedImage returns true if the format of the image src is supported by its URL.
+// See isSupportedImageFormat.
+func isSupportedImage(src string, opt *Option) bool {
+	return isSupportedImageFormat(src, "", opt)
+}
+
+// checkImageSize returns the image of src with the size from the attributes if both are known,
+// otherwise with the size detected by a request. The signature of the image is returned
+// if it is requested and Option.DedupeImagesBySignature is set.
+func checkImageSize(src string, widthFromAttr, heightFromAttr int, opt *Option) (*Image, string) {
 	width, height := widthFromAttr, heightFromAttr
+	signature := ""
+	var info *imageInfo
 	if width == 0 || height == 0 {
-		_, size, err := fastimage.DetectImageTypeWithTimeout(src, opt.ImageRequestTimeout)
+		var size *fastimage.ImageSize
+		var err error
+		if isDataURI(src) {
+			size, err = dataURIImageSize(src)
+		} else {
+			info, signature, err = probeImage(src, opt)
+			if err == nil {
+				size = info.size
+			}
+			if err != errImageHostSkipped {
+				incImageProbe(opt, err == nil)
+			}
+		}
 		logger.Printf("checkImageSize: src: %v, err: %v, size: %v\n", src, err, size)
 		if err != nil {
-			return &Image{}
+			return &Image{}, ""
+		}
+		if info != nil && info.mimeType != "" && !isSupportedImageFormat(src, info.mimeType, opt) {
+			logger.Printf("checkImageSize: src: %v, unsupported format: %v\n", src, info.mimeType)
+			return &Image{}, ""
 		}
 		if size != nil {
 			width, height = int(size.Width), int(size.Height)
 		}
 	}
-	return &Image{
+	img := &Image{
 		URL:  src,
 		Size: &fastimage.ImageSize{Width: uint32(width), Height: uint32(height)},
 	}
+	if info != nil {
+		img.Rotated, img.Animated = info.rotated, info.animated
+	}
+	return img, signature
 }
 
 func author(doc *goquery.Document) string {
@@ -855,8 +2037,12 @@ func author(doc *goquery.Document) string {
 	return author
 }
 
+// absPath resolves in, a URL reference like "../img/a.jpg", "?page=2" or "//cdn.example.com/a.jpg",
+// against reqURLStr as browsers do (RFC 3986), removing dot segments from the path.
+// Absolute URLs are returned as is.
 func absPath(in string, reqURLStr string) (out string, err error) {
-	if strings.TrimSpace(in) == "" {
+	in = strings.TrimSpace(in)
+	if in == "" {
 		return "", fmt.Errorf("empty input string for absPath")
 	}
 
@@ -876,26 +2062,21 @@ func absPath(in string, reqURLStr string) (out string, err error) {
 	if !isValidURLStr(reqURLStr) {
 		return "", fmt.Errorf("url %v has invalid scheme", reqURLStr)
 	}
+	return reqURL.ResolveReference(inURL).String(), nil
+}
 
-	if strings.HasPrefix(in, "//") {
-		return reqURL.Scheme + ":" + in, nil
-	}
-	if strings.HasPrefix(in, "/") {
-		return reqURL.Scheme + "://" + reqURL.Host + in, nil
-	}
-
-	var result string
-	sPos := strings.LastIndex(reqURLStr, "/")
-	if sPos < 8 {
-		result = reqURLStr + "/" + in
-	} else {
-		result = reqURLStr[:sPos+1] + in
+// documentBase returns the URL of <base href> of doc resolved against reqURL,
+// or reqURL if doc has no <base href> with a http or https URL.
+func documentBase(doc *goquery.Document, reqURL string) string {
+	href := strings.TrimSpace(doc.Find("base[href]").First().AttrOr("href", ""))
+	if href == "" {
+		return reqURL
 	}
-	_, err = url.Parse(result)
-	if err != nil {
-		return "", err
+	base, err := absPath(href, reqURL)
+	if err != nil || !isValidURLStr(base) {
+		return reqURL
 	}
-	return result, nil
+	return base
 }
 
 func isValidURLStr(s string) bool {
@@ -1,11 +1,19 @@
 package readability
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
 )
 
 var urlWithAbsoluteImgPaths = "http://www.espn.com/nba/insider/story/_/id/22450965/drafting-nba-rising-stars-future-star-potential-ben-simmons-lonzo-ball-joel-embiid-more"
@@ -15,12 +23,13 @@ func TestExtractWhenImgPathsAreAbsolute(t *testing.T) {
 	opt := NewOption()
 	opt.ImageRequestTimeout = 500
 
-	// not using opengraph (traditional readability rule)
+	// not using opengraph and structured data (traditional readability rule)
 	opt.LookupOpenGraphTags = false
+	opt.LookupStructuredData = false
 	c, err := Extract(urlWithAbsoluteImgPaths, opt)
 	assert.Nil(t, err)
 	assert.Equal(t, "Drafting NBA rising stars by future star potential - Ben Simmons, Lonzo Ball, Joel Embiid and more", c.Title)
-	assert.Equal(t, " ABOUT COOKIES To help make this website better, to improve and personalize your experience and for advertising purposes, are you happy to accept cookies and other technologies? Yes More Info Here Cookie Choices ", c.Description)
+	assert.Equal(t, "ABOUT COOKIES To help make this website better, to improve and personalize your experience and for advertising purposes, are you happy to accept cookies and other technologies? Yes More Info Here Cookie Choices", c.Description)
 	assert.NotContains(t, c.Description, "\n")
 	assert.Empty(t, c.Images) // empty since images are lazily-loaded
 
@@ -80,52 +89,70 @@ func TestLinkDensity(t *testing.T) {
 	html := `<div>Speak blah blah!<a>123</a><a>4</a></div>`
 	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
 	assert.Equal(t, 0.2, linkDensity(doc.Selection))
+
+	for _, html := range []string{`<div></div>`, `<div><a href="/"><img src="a.png"></a></div>`} {
+		doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+		assert.Equal(t, 0.0, linkDensity(doc.Find("div")), html)
+	}
 }
 
 func TestAbsPath(t *testing.T) {
-	// for absolute path
-	url := "http://www.kakao.com/talk"
-	in := url + "/img/a.jpg"
-	out, err := absPath(in, url)
-	assert.Nil(t, err)
-	assert.Equal(t, in, out)
+	for _, tc := range []struct {
+		in, base, want string
+	}{
+		// absolute URLs are kept
+		{"http://www.kakao.com/talk/img/a.jpg", "http://www.kakao.com/talk", "http://www.kakao.com/talk/img/a.jpg"},
+		{"https://example.com/a/../b.jpg", "http://www.kakao.com/talk", "https://example.com/a/../b.jpg"},
+		{"mailto:a@example.com", "http://www.kakao.com/talk", "mailto:a@example.com"},
+		{" http://example.com/a.jpg\n", "http://www.kakao.com/talk", "http://example.com/a.jpg"},
 
-	// for relative path starting with "/"
-	in = "/img/b.jpg"
-	out, err = absPath(in, url)
-	assert.Nil(t, err)
-	assert.Equal(t, "http://www.kakao.com/img/b.jpg", out)
-
-	// for relative path not starting with "/" and reqURL does not have subdirectories
-	in = "img/b.jpg"
-	out, err = absPath(in, "http://www.kakao.com")
-	assert.Nil(t, err)
-	assert.Equal(t, "http://www.kakao.com/img/b.jpg", out)
+		// root-relative and scheme-relative
+		{"/img/b.jpg", "http://www.kakao.com/talk", "http://www.kakao.com/img/b.jpg"},
+		{"/img/b.jpg", "http://www.kakao.com:8080/talk?q=1", "http://www.kakao.com:8080/img/b.jpg"},
+		{"//cdn.example.com/b.jpg", "https://example.com/news/1", "https://cdn.example.com/b.jpg"},
 
-	// for relative path not starting with "/"
-	url = "https://www.wto.org/english/tratop_e/envir_e/envir_req_e.htm"
-	in = "../../../images/top_logo.gif"
-	out, err = absPath(in, url)
-	assert.Nil(t, err)
-	assert.Equal(t, "https://www.wto.org/english/tratop_e/envir_e/../../../images/top_logo.gif", out)
+		// path-relative
+		{"img/b.jpg", "http://www.kakao.com", "http://www.kakao.com/img/b.jpg"},
+		{"img/b.jpg", "http://www.kakao.com/talk", "http://www.kakao.com/img/b.jpg"},
+		{"img/b.jpg", "http://www.kakao.com/talk/", "http://www.kakao.com/talk/img/b.jpg"},
+		{"./img/b.jpg", "http://www.kakao.com/talk/", "http://www.kakao.com/talk/img/b.jpg"},
+		{"../../../images/top_logo.gif", "https://www.wto.org/english/tratop_e/envir_e/envir_req_e.htm",
+			"https://www.wto.org/images/top_logo.gif"},
+		{"../../../../../a.gif", "https://www.wto.org/english/tratop_e/envir_e/envir_req_e.htm", "https://www.wto.org/a.gif"},
+		{"a/./b/../c.jpg", "https://example.com/x/y.html", "https://example.com/x/a/c.jpg"},
+		{"..", "https://example.com/x/y/z.html", "https://example.com/x/"},
 
-	// for empty input path
-	in = ""
-	out, err = absPath(in, url)
-	assert.Equal(t, "", out)
-	assert.NotNil(t, err)
+		// slashes in the query or the fragment of the base are not a part of the path
+		{"b.jpg", "https://example.com/x/y.html?next=/a/b", "https://example.com/x/b.jpg"},
+		{"b.jpg", "https://example.com/x/y.html#/a/b", "https://example.com/x/b.jpg"},
 
-	// failing case - invalid input path
-	url = "http://www.kakao.com"
-	in = "fhsjkdfhjsdf#$%^#&^"
-	_, err = absPath(in, url)
-	assert.NotNil(t, err)
+		// queries and fragments
+		{"?page=2", "https://example.com/x/y.html?page=1", "https://example.com/x/y.html?page=2"},
+		{"#note", "https://example.com/x/y.html?page=1#top", "https://example.com/x/y.html?page=1#note"},
+		{"b.jpg?w=100#c", "https://example.com/x/y.html", "https://example.com/x/b.jpg?w=100#c"},
+		{"../b.jpg?u=/a/../b", "https://example.com/x/y/z.html", "https://example.com/x/b.jpg?u=/a/../b"},
+	} {
+		out, err := absPath(tc.in, tc.base)
+		assert.Nil(t, err, tc.in)
+		assert.Equal(t, tc.want, out, "%s against %s", tc.in, tc.base)
+	}
 
-	// failing case - invalid requestURL string
-	url = "yirqywi8r4o"
-	in = "/a.jpg"
-	_, err = absPath(in, url)
-	assert.NotNil(t, err)
+	for _, tc := range []struct {
+		in, base string
+	}{
+		{"", "http://www.kakao.com"},
+		{" \t", "http://www.kakao.com"},
+		// invalid input path
+		{"fhsjkdfhjsdf#$%^#&^", "http://www.kakao.com"},
+		// invalid request URLs
+		{"/a.jpg", "yirqywi8r4o"},
+		{"/a.jpg", "ftp://example.com/"},
+		{"/a.jpg", "http://exa mple.com/"},
+	} {
+		out, err := absPath(tc.in, tc.base)
+		assert.NotNil(t, err, "%s against %s", tc.in, tc.base)
+		assert.Equal(t, "", out)
+	}
 }
 
 func TestAbsPathWithoutScheme(t *testing.T) {
@@ -136,6 +163,39 @@ func TestAbsPathWithoutScheme(t *testing.T) {
 	assert.Equal(t, "https:"+in, out)
 }
 
+func TestDocumentBase(t *testing.T) {
+	for _, tc := range []struct {
+		html, want string
+	}{
+		{`<head></head>`, "https://example.com/news/1"},
+		{`<head><base href="https://cdn.example.com/static/"></head>`, "https://cdn.example.com/static/"},
+		{`<head><base href="/archive/"></head>`, "https://example.com/archive/"},
+		{`<head><base target="_blank"><base href="javascript:x()"></head>`, "https://example.com/news/1"},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		assert.Equal(t, tc.want, documentBase(doc, "https://example.com/news/1"), tc.html)
+	}
+}
+
+func TestExtractWithBaseHref(t *testing.T) {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><head><base href="https://example.com/archive/"></head><body><div class="article">` +
+		`<p>First <a href="other.html">link</a> ` + p + `<img src="a.png" srcset="a.png 1x, b.png 2x" width="400" height="300"></p>` +
+		`<p>Second <a href="#note">note</a> ` + p + `</p></div></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	opt := benchOption()
+	opt.DescriptionAsPlainText = false
+	opt.HTMLPolicy = NewHTMLPolicy()
+	c, err := ExtractFromDocument(doc, "https://example.com/news/1", opt)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Description, `<a href="https://example.com/archive/other.html">link</a>`)
+	assert.Contains(t, c.Description, `src="https://example.com/archive/a.png" srcset="https://example.com/archive/a.png 1x, https://example.com/archive/b.png 2x"`)
+	assert.Contains(t, c.Description, `<a href="#note">note</a>`)
+	if assert.NotEmpty(t, c.Images) {
+		assert.Equal(t, "https://example.com/archive/a.png", c.Images[0].URL)
+	}
+}
+
 func TestDescriptionTimeout(t *testing.T) {
 	url := "https://tools.ietf.org/rfc/"
 	opt := NewOption()
@@ -177,3 +237,382 @@ func TestForOpengraph(t *testing.T) {
 	assert.Equal(t, "R&K Insider: Going to Dublin", c.Title)
 	assert.Equal(t, "This week on R&K: What to know before you go to Dublin, a ridiculously calorific breakfast in Norway, and how to hunt for food in Tokyo.", c.Description)
 }
+
+func TestPrepareDocument(t *testing.T) {
+	html := `<html><head><style>p{}</style></head><body>
+<div id="a"><span>text <b>only</b></span><script>x()</script></div>
+<div id="b"><span><img src="a.jpg"></span></div>
+<div id="c"><article>nested</article></div>
+<div id="d"><div id="e">inner</div></div>
+<div id="f"><div class="sidebar"><img src="b.jpg"></div>text</div>
+<div id="g"><img src="c.jpg"></div>
+<div id="h"><abbr>HTML</abbr> and a <picture><source srcset="d.jpg"></picture></div>
+</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	assert.Nil(t, prepareDocument(doc, opt))
+	assert.Equal(t, 0, doc.Find("script, style, .sidebar").Length())
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#a")))
+	// only children are checked, and tag names are matched exactly
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#b")))
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#c")))
+	assert.Equal(t, "div", goquery.NodeName(doc.Find("#d")))
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#e")))
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#f")))
+	assert.Equal(t, "div", goquery.NodeName(doc.Find("#g")))
+	assert.Equal(t, "p", goquery.NodeName(doc.Find("#h")))
+}
+
+func TestPrepareDocumentDeepNesting(t *testing.T) {
+	depth := 5000
+	html := strings.Repeat("<div><span>", depth) + "text" + strings.Repeat("</span></div>", depth)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	opt.DescriptionExtractionTimeout = 1000
+	assert.Nil(t, prepareDocument(doc, opt))
+	// no div has a block element child, so all of them are transformed
+	assert.Equal(t, 0, doc.Find("div").Length())
+	assert.Equal(t, depth, doc.Find("p").Length())
+}
+
+func TestExtractArticleRetrySeesWholePage(t *testing.T) {
+	text := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 4)
+	html := `<html><body><div class="sidebar-layout"><p>` + text + `</p><p>` + text + `</p><p>` + text + `</p></div></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	before, _ := doc.Html()
+
+	opt := NewOption()
+	a := extractArticle(doc, "", opt)
+	// the first pass removes the sidebar, and the retry without RemoveUnlikelyCandidates finds it again
+	assert.Contains(t, a.description, "Lorem ipsum")
+	assert.Equal(t, 1, a.prepared.Find(".sidebar-layout").Length())
+
+	after, _ := doc.Html()
+	assert.Equal(t, before, after)
+}
+
+func TestExtractFromDocumentDoesNotModifyDocument(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(benchPage(t, "medium")))
+	before, _ := doc.Html()
+	opt := benchOption()
+	c, err := ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Nil(t, err)
+	after, _ := doc.Html()
+	assert.Equal(t, before, after)
+
+	opt.ModifyDocument = true
+	c2, err := ExtractFromDocument(doc, "http://example.com/news/1", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Description, c2.Description)
+	assert.Equal(t, 0, doc.Find("script, style, nav").Length())
+}
+
+// TestModifyDocumentTimeout is meant to be run with -race: the timed out walk must stop
+// before the document is read for images and the author.
+func TestModifyDocumentTimeout(t *testing.T) {
+	section := `<div class="section"><div><p>` + strings.Repeat("Some text of the page, with commas. ", 5) +
+		`</p><img src="/a.png" width="640" height="480"></div><script>var a;</script><div class="sidebar">links</div></div>`
+	page := `<html><body><div id="content">` + strings.Repeat(section, 2000) + `</div></body></html>`
+	for i := 0; i < 3; i++ {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		opt := benchOption()
+		opt.ModifyDocument = true
+		opt.DescriptionExtractionTimeout = 1
+		opt.RetryLength = 0
+		_, err := ExtractFromDocument(doc, "http://example.com/", opt)
+		assert.Nil(t, err)
+
+		before, _ := doc.Html()
+		time.Sleep(10 * time.Millisecond)
+		after, _ := doc.Html()
+		assert.Equal(t, before, after)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	html := `<div><h2 class="comment">Comments</h2><h2>Heading</h2>
+<p>text <iframe src="a"></iframe></p><p> <form><input></form> </p>
+<p><h3 class="x">keep</h3></p></div>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	opt.CleanConditionally = false
+	sanitize(doc, &candidates{Map: map[string]candidate{}}, opt, nil)
+	assert.Equal(t, 0, doc.Find("h2.comment, iframe, form").Length())
+	assert.Equal(t, "Heading", doc.Find("h2").Text())
+	assert.Equal(t, 1, doc.Find("p").Length())
+	assert.Equal(t, 1, doc.Find("h3").Length())
+}
+
+func TestSubtreeStats(t *testing.T) {
+	html := `<div id="a"><p>one <a href="#">two</a></p><noscript><img src="a"></noscript><img src="b"></div>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	s := doc.Find("#a")
+	st := newSubtreeStats(s.Get(0))
+	assert.Equal(t, 1, st.counts["p"])
+	assert.Equal(t, 1, st.counts["a"])
+	assert.Equal(t, s.Find("img").Length(), st.counts["img"])
+	assert.Equal(t, s.Find("noscript").Find("img").Length(), st.noscriptImages)
+	assert.Equal(t, s.Text(), st.text)
+	assert.Equal(t, linkDensity(s), st.linkDensity())
+}
+
+func TestHTML5SemanticElements(t *testing.T) {
+	text := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 5)
+	html := `<html><body>
+<nav><p>` + text + `</p></nav>
+<article><header><h1>Title</h1></header>
+<div class="inner"><p>` + text + `</p><p>` + text + `</p></div>
+<section><p>` + text + `</p></section>
+</article>
+<aside><p>` + text + `</p></aside>
+<footer><p>` + text + `</p></footer>
+</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	c, err := prepareCandidates(doc, opt)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, doc.Find("nav, aside").Length())
+	assert.Equal(t, 2, doc.Find("footer, header").Length())
+
+	preferArticleAncestor(c, opt)
+	assert.Equal(t, "article", goquery.NodeName(c.List[0].Node.Selection))
+	assert.Equal(t, 3, c.List[0].Node.Find("p").Length())
+
+	// the header of the article is kept, and the footer of the page isn't
+	article, err := getArticle(c, opt, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, article.Find("header h1").Length())
+	assert.Equal(t, 0, article.Find("footer").Length())
+	assert.Equal(t, 3, article.Find("p").Length())
+}
+
+func TestPreferArticleAncestor(t *testing.T) {
+	text := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 5)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><article><header><h1>Title</h1></header>
+<div class="content"><p>` + text + `</p><p>` + text + `</p><p>` + text + `</p></div></article></body>`))
+	opt := NewOption()
+	c, err := prepareCandidates(doc, opt)
+	assert.Nil(t, err)
+	best := c.List[0]
+	assert.Equal(t, "content", best.Node.AttrOr("class", ""))
+	n := len(c.List)
+
+	preferArticleAncestor(c, opt)
+	assert.Equal(t, "article", goquery.NodeName(c.List[0].Node.Selection))
+	// the former best candidate is kept after the <article>, which is listed once
+	assert.Equal(t, best.Node.HTML(), c.List[1].Node.HTML())
+	assert.Equal(t, n, len(c.List))
+	assert.True(t, c.List[0].Score >= best.Score)
+}
+
+func TestRemovePageElements(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><div id="s"><header>Site</header><p>Text</p><div><footer>Links</footer></div></div></body>`))
+	s := doc.Find("#s")
+	clone := s.Clone()
+	removePageElements(s.Get(0), clone.Get(0), NewOption())
+	assert.Equal(t, "Text", clone.Text())
+	assert.Equal(t, 1, s.Find("header").Length())
+	assert.Equal(t, 1, s.Find("footer").Length())
+}
+
+// benchPage returns the fixture page of testdata/bench for size "small" or "medium".
+// "huge" is the medium page with its comment thread repeated 40 times (about 1MB),
+// which is built here instead of being bundled.
+func benchPage(tb testing.TB, size string) string {
+	name := size
+	if size == "huge" {
+		name = "medium"
+	}
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "bench", name+".html"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	page := string(b)
+	if size == "huge" {
+		start := strings.Index(page, `<div class="comment" `)
+		end := strings.Index(page, "</section>\n</div>")
+		page = page[:start] + strings.Repeat(page[start:end], 40) + page[end:]
+	}
+	return page
+}
+
+// offlineTransport fails every request immediately, so that benchmarks don't depend on the network.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
+
+// benchOption returns the option for benchmarks, which always runs readability rules
+// instead of using the description of opengraph tags or structured data, and never requests images.
+func benchOption() *Option {
+	opt := NewOption()
+	opt.DescriptionExtractionTimeout = 60000
+	opt.LookupOpenGraphTags = false
+	opt.LookupStructuredData = false
+	opt.ImageClient = &http.Client{Transport: offlineTransport{}}
+	return opt
+}
+
+func TestKeepFigures(t *testing.T) {
+	body := strings.Repeat("The harbor was quiet in the morning, and the boats waited for the tide. ", 6)
+	page := `<html><head><title>Harbor</title></head><body><article>
+<p>` + body + `</p>
+<figure class="wp-block-image"><img data-original="/harbor.jpg" alt="Harbor" class="lazy"><figcaption>The harbor at dawn</figcaption></figure>
+<p>` + body + `</p>
+</article></body></html>`
+
+	extract := func(keep, plain bool) *Content {
+		opt := benchOption()
+		opt.KeepFigures, opt.DescriptionAsPlainText = keep, plain
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		c, err := ExtractFromDocument(doc, "http://example.com/news", opt)
+		assert.Nil(t, err)
+		return c
+	}
+
+	c := extract(false, false)
+	assert.NotContains(t, c.Description, "<figure>")
+
+	c = extract(true, false)
+	assert.Contains(t, c.Description, `<figure><img src="http://example.com/harbor.jpg" alt="Harbor"/><figcaption>The harbor at dawn</figcaption></figure>`)
+	assert.Contains(t, c.Paragraphs, "The harbor at dawn")
+
+	c = extract(true, true)
+	assert.Contains(t, c.Description, "tide. The harbor at dawn The harbor")
+	assert.Contains(t, c.Paragraphs, "The harbor at dawn")
+}
+
+func TestExtractFromDocumentDeterministic(t *testing.T) {
+	// two candidates with the same score, and images ordered by area with the same size
+	p := func(word string) string {
+		return "<p>" + strings.Repeat(word+" lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 6) + "</p>"
+	}
+	page := `<html><head><title>Ties</title></head><body>
+<section><div>` + p("first") + `</div></section><section><div>` + p("secon") + `</div></section>
+<img src="/a.png" width="400" height="300"><img src="/b.png" width="400" height="300"><img src="/c.png" width="300" height="400">
+</body></html>`
+	pages := []string{page, benchPage(t, "medium")}
+
+	for _, page := range pages {
+		var want []byte
+		for i := 0; i < 20; i++ {
+			doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+			opt := benchOption()
+			opt.SortImagesBy = ImageOrderArea
+			opt.ParallelScoring = i%2 == 1
+			c, err := ExtractFromDocument(doc, "http://example.com/ties", opt)
+			assert.Nil(t, err)
+			b, _ := json.Marshal(c)
+			if want == nil {
+				want = b
+				continue
+			}
+			if !assert.Equal(t, string(want), string(b), "run %v", i) {
+				break
+			}
+		}
+	}
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	c, _ := ExtractFromDocument(doc, "http://example.com/ties", benchOption())
+	assert.True(t, strings.HasPrefix(c.Description, "first"), c.Description)
+}
+
+func TestParagraphs(t *testing.T) {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><body><div class="article"><h2>Heading</h2><p>First ` + p + `</p><p>Second
+   ` + p + `</p></div></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	c, err := ExtractFromDocument(doc, "http://example.com", benchOption())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Heading", "First " + strings.TrimSpace(p), "Second " + strings.TrimSpace(p)}, c.Paragraphs)
+
+	b, _ := json.Marshal(c)
+	assert.Contains(t, string(b), `"paragraphs":["Heading",`)
+}
+
+func TestArticleNode(t *testing.T) {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><body><div class="article"><p>First ` + p + `</p><p>Second ` + p + `</p></div></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	opt := benchOption()
+	opt.DescriptionAsPlainText = false
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	n := c.ArticleNode()
+	if assert.NotNil(t, n) {
+		assert.Equal(t, html.DocumentNode, n.Type)
+		var buf bytes.Buffer
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			html.Render(&buf, c)
+		}
+		assert.Equal(t, c.Description, buf.String())
+		assert.Equal(t, 2, goquery.NewDocumentFromNode(n).Find("p").Length())
+	}
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<html><head><meta property="og:description" content="desc"></head><body></body></html>`))
+	c, err = ExtractFromDocument(doc, "http://example.com", NewOption())
+	assert.Nil(t, err)
+	assert.Nil(t, c.ArticleNode())
+}
+
+func TestDescriptionEntities(t *testing.T) {
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><body><div class="article"><p>AT&amp;amp;T said <b>&lt;script&gt;alert(1)&lt;/script&gt;</b>` +
+		` &#8217;quoted&#8217;&nbsp;<em>R&amp;D</em> ` + p + `</p></div></body></html>`
+	for _, plain := range []bool{true, false} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		opt := benchOption()
+		opt.DescriptionAsPlainText = plain
+		c, err := ExtractFromDocument(doc, "http://example.com", opt)
+		assert.Nil(t, err)
+		want := "AT&amp;T said <script>alert(1)</script> ’quoted’\u00a0R&D lorem"
+		if !plain {
+			// the decoded text is escaped again in the HTML description
+			want = "AT&amp;amp;T said &lt;script&gt;alert(1)&lt;/script&gt; ’quoted’\u00a0R&amp;D lorem"
+		}
+		assert.Contains(t, c.Description, want)
+		assert.Equal(t, "AT&amp;T said <script>alert(1)</script> ’quoted’\u00a0R&D "+strings.TrimSpace(p), c.Paragraphs[0])
+	}
+}
+
+func TestBenchPages(t *testing.T) {
+	for _, size := range []string{"small", "medium", "huge"} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(benchPage(t, size)))
+		opt := benchOption()
+		c, err := ExtractFromDocument(doc, "http://example.com/news/1", opt)
+		assert.Nil(t, err, size)
+		assert.True(t, len(c.Description) > 500, size)
+		assert.NotContains(t, c.Description, "reader1", size)
+		assert.Equal(t, StatusCompleted, c.Completeness.Description, size)
+	}
+}
+
+func benchmarkExtractFromDocument(b *testing.B, size string) {
+	page := benchPage(b, size)
+	opt := benchOption()
+	b.SetBytes(int64(len(page)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		b.StartTimer()
+		if _, err := ExtractFromDocument(doc, "http://example.com/news/1", opt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExtractFromDocumentSmall(b *testing.B) {
+	benchmarkExtractFromDocument(b, "small")
+}
+
+func BenchmarkExtractFromDocumentMedium(b *testing.B) {
+	benchmarkExtractFromDocument(b, "medium")
+}
+
+func BenchmarkExtractFromDocumentHuge(b *testing.B) {
+	benchmarkExtractFromDocument(b, "huge")
+}
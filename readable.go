@@ -0,0 +1,77 @@
+package readability
+
+import (
+	"math"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	// readableMinContentLength is the minimum text length of a node counted by IsProbablyReadable.
+	readableMinContentLength = 140
+
+	// readableMinScore is the minimum accumulated score for IsProbablyReadable to return true.
+	readableMinScore = 20.0
+)
+
+// IsProbablyReadable estimates whether doc contains an extractable article,
+// without running the full extraction pipeline and without modifying doc.
+//
+// It accumulates the text mass of <p>, <pre> and <article> nodes which are not
+// hidden or unlikely candidates, so it is cheap enough for triaging a large number of pages.
+func IsProbablyReadable(doc *goquery.Document) bool {
+	score := 0.0
+	readable := false
+	doc.Find("p, pre, article").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if isHidden(s) || isUnlikelyCandidate(s) {
+			return true
+		}
+		// nested <p>s in <article> are counted by <article> itself
+		if goquery.NodeName(s) != "article" && s.ParentsFiltered("article").Length() > 0 {
+			return true
+		}
+
+		l := len(strings.TrimSpace(s.Text()))
+		if l < readableMinContentLength {
+			return true
+		}
+		score += math.Sqrt(float64(l - readableMinContentLength))
+		if score > readableMinScore {
+			readable = true
+			return false
+		}
+		return true
+	})
+	return readable
+}
+
+func isHidden(s *goquery.Selection) bool {
+	if _, ok := s.Attr("hidden"); ok {
+		return true
+	}
+	style := strings.Replace(strings.ToLower(s.AttrOr("style", "")), " ", "", -1)
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+func isUnlikelyCandidate(s *goquery.Selection) bool {
+	return s.Length() > 0 && isUnlikelyNode(s.Get(0), defaultClassPatterns)
+}
+
+// isUnlikelyNode returns true if class and id of n look like those of non-content elements
+// according to p.
+func isUnlikelyNode(n *html.Node, p *classPatterns) bool {
+	str := nodeAttr(n, "class") + nodeAttr(n, "id")
+	return p.unlikely.FindString(str) != "" && p.maybe.FindString(str) == ""
+}
+
+// nodeAttr returns the value of the attribute key of n, or "" if n doesn't have it.
+func nodeAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
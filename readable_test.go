@@ -0,0 +1,26 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsProbablyReadable(t *testing.T) {
+	long := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 10)
+	for _, tc := range []struct {
+		html     string
+		readable bool
+	}{
+		{`<html><body><p>` + long + `</p><p>` + long + `</p></body></html>`, true},
+		{`<html><body><article><p>` + long + `</p><p>` + long + `</p></article></body></html>`, true},
+		{`<html><body><p>Too short.</p></body></html>`, false},
+		{`<html><body><p style="display: none">` + long + `</p><p hidden>` + long + `</p></body></html>`, false},
+		{`<html><body><p class="comment">` + long + `</p><p class="sidebar">` + long + `</p></body></html>`, false},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		assert.Equal(t, tc.readable, IsProbablyReadable(doc), tc.html)
+	}
+}
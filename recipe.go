@@ -0,0 +1,242 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Recipe is a recipe declared by a page, for meal-planning applications.
+type Recipe struct {
+	Name string `json:"name,omitempty"`
+
+	// Ingredients contains the ingredients with their quantities, like "2 cups flour".
+	Ingredients []string `json:"ingredients"`
+
+	// Steps contains the instructions in order.
+	Steps []string `json:"steps"`
+
+	// PrepTime, CookTime and TotalTime are the durations declared by the page, as is.
+	// Structured data declare them in ISO 8601, like "PT1H30M".
+	PrepTime  string `json:"prepTime,omitempty"`
+	CookTime  string `json:"cookTime,omitempty"`
+	TotalTime string `json:"totalTime,omitempty"`
+
+	// Yield is the quantity produced, like "4 servings".
+	Yield string `json:"yield,omitempty"`
+}
+
+var (
+	// recipeIngredientsClass and recipeStepsClass match class and id of the ingredient and
+	// instruction lists of recipe plugins and themes, like "wprm-recipe-ingredients".
+	recipeIngredientsClass = regexp.MustCompile(`(?i)ingredient`)
+	recipeStepsClass       = regexp.MustCompile(`(?i)instruction|direction|method|steps`)
+
+	// recipeYieldClass and recipeTimeClass match class of the yield and the durations of recipe cards,
+	// like "wprm-recipe-servings" or "tasty-recipes-cook-time".
+	recipeYieldClass = regexp.MustCompile(`(?i)\byield\b|servings\b`)
+	recipeTimeClass  = regexp.MustCompile(`(?i)(prep|cook|total)[-_]?time\b`)
+)
+
+// recipe returns the recipe of doc declared by JSON-LD, microdata or the markup of
+// common recipe plugins, in that order, or nil if doc has no recipe.
+func recipe(doc *goquery.Document) *Recipe {
+	if r := jsonLDRecipe(doc); r != nil {
+		return r
+	}
+	if r := microdataRecipe(doc); r != nil {
+		return r
+	}
+	return markupRecipe(doc)
+}
+
+// jsonLDRecipe returns the first JSON-LD Recipe object with ingredients or steps.
+func jsonLDRecipe(doc *goquery.Document) *Recipe {
+	for _, obj := range jsonLDObjects(doc) {
+		if !jsonLDType(obj, "Recipe") {
+			continue
+		}
+		r := &Recipe{
+			Name:        jsonLDString(obj["name"]),
+			Ingredients: recipeTexts(jsonLDStrings(firstNonNil(obj["recipeIngredient"], obj["ingredients"]))),
+			Steps:       recipeTexts(jsonLDSteps(obj["recipeInstructions"])),
+			PrepTime:    jsonLDString(obj["prepTime"]),
+			CookTime:    jsonLDString(obj["cookTime"]),
+			TotalTime:   jsonLDString(obj["totalTime"]),
+			Yield:       jsonLDValue(obj["recipeYield"]),
+		}
+		if len(r.Ingredients) > 0 || len(r.Steps) > 0 {
+			return r
+		}
+	}
+	return nil
+}
+
+// jsonLDType returns true if the @type of obj includes the schema.org type name.
+func jsonLDType(obj map[string]interface{}, name string) bool {
+	for _, t := range jsonLDStrings(obj["@type"]) {
+		if schemaTypeName(t) == strings.ToLower(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTypeName returns the lowercased type name of t, which can be a URL like "https://schema.org/Recipe".
+func schemaTypeName(t string) string {
+	t = strings.TrimRight(strings.TrimSpace(t), "/")
+	if i := strings.LastIndexAny(t, "/#"); i >= 0 {
+		t = t[i+1:]
+	}
+	return strings.ToLower(t)
+}
+
+// jsonLDSteps returns the instructions of v, which can be a string, HowToStep objects having "text",
+// HowToSection objects having "itemListElement", or an array of them.
+func jsonLDSteps(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var steps []string
+		for _, e := range t {
+			steps = append(steps, jsonLDSteps(e)...)
+		}
+		return steps
+	case map[string]interface{}:
+		if items, ok := t["itemListElement"]; ok {
+			return jsonLDSteps(items)
+		}
+		return jsonLDStrings(firstNonNil(t["text"], t["name"]))
+	}
+	return nil
+}
+
+// microdataRecipe returns the first microdata Recipe item with ingredients or steps.
+func microdataRecipe(doc *goquery.Document) *Recipe {
+	var r *Recipe
+	doc.Find("[itemscope][itemtype]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		isRecipe := false
+		for _, t := range strings.Fields(s.AttrOr("itemtype", "")) {
+			isRecipe = isRecipe || schemaTypeName(t) == "recipe"
+		}
+		if !isRecipe {
+			return true
+		}
+		props := microdataProps(s)
+		var ingredients, steps []string
+		for _, p := range microdataPropAll(s, "recipeIngredient", "ingredients") {
+			ingredients = append(ingredients, microdataValue(p))
+		}
+		for _, p := range microdataPropAll(s, "recipeInstructions") {
+			if items := p.Find("li"); items.Length() > 0 {
+				items.Each(func(_ int, li *goquery.Selection) {
+					steps = append(steps, li.Text())
+				})
+			} else {
+				steps = append(steps, microdataValue(p))
+			}
+		}
+		c := &Recipe{
+			Name:        microdataValue(props["name"]),
+			Ingredients: recipeTexts(ingredients),
+			Steps:       recipeTexts(steps),
+			PrepTime:    microdataValue(props["prepTime"]),
+			CookTime:    microdataValue(props["cookTime"]),
+			TotalTime:   microdataValue(props["totalTime"]),
+			Yield:       normalizeSpaces(microdataValue(props["recipeYield"])),
+		}
+		if len(c.Ingredients) == 0 && len(c.Steps) == 0 {
+			return true
+		}
+		r = c
+		return false
+	})
+	return r
+}
+
+// microdataPropAll returns all elements of the properties names belonging to scope in document order,
+// excluding properties of nested items.
+func microdataPropAll(scope *goquery.Selection, names ...string) []*goquery.Selection {
+	var list []*goquery.Selection
+	scopeNode := scope.Get(0)
+	scope.Find("[itemprop]").Each(func(_ int, s *goquery.Selection) {
+		owner := s.Parent().Closest("[itemscope]")
+		if owner.Length() == 0 || owner.Get(0) != scopeNode {
+			return
+		}
+		for _, prop := range strings.Fields(s.AttrOr("itemprop", "")) {
+			for _, name := range names {
+				if prop == name {
+					list = append(list, s)
+					return
+				}
+			}
+		}
+	})
+	return list
+}
+
+// markupRecipe returns the recipe found by the class and id of recipe cards of common plugins,
+// like WP Recipe Maker and Tasty Recipes: the items of the first ingredient list and
+// the first instruction list. It returns nil unless both lists are found.
+func markupRecipe(doc *goquery.Document) *Recipe {
+	ingredients := recipeList(doc, recipeIngredientsClass)
+	steps := recipeList(doc, recipeStepsClass)
+	if len(ingredients) == 0 || len(steps) == 0 {
+		return nil
+	}
+	r := &Recipe{Ingredients: ingredients, Steps: steps}
+	doc.Find("[class]").Each(func(_ int, s *goquery.Selection) {
+		c := s.AttrOr("class", "")
+		if r.Yield == "" && recipeYieldClass.MatchString(c) {
+			r.Yield = normalizeSpaces(s.Text())
+		}
+		if m := recipeTimeClass.FindStringSubmatch(c); m != nil {
+			var d *string
+			switch strings.ToLower(m[1]) {
+			case "prep":
+				d = &r.PrepTime
+			case "cook":
+				d = &r.CookTime
+			default:
+				d = &r.TotalTime
+			}
+			if *d == "" {
+				*d = normalizeSpaces(s.Text())
+			}
+		}
+	})
+	return r
+}
+
+// recipeList returns the texts of the items of the first list inside (or being) an element
+// whose class or id matches re.
+func recipeList(doc *goquery.Document, re *regexp.Regexp) []string {
+	var items []string
+	doc.Find("ul, ol").EachWithBreak(func(_ int, l *goquery.Selection) bool {
+		for s, i := l, 0; s.Length() > 0 && i < 3; s, i = s.Parent(), i+1 {
+			if re.MatchString(s.AttrOr("class", "") + " " + s.AttrOr("id", "")) {
+				l.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+					items = append(items, li.Text())
+				})
+				items = recipeTexts(items)
+				return len(items) == 0
+			}
+		}
+		return true
+	})
+	return items
+}
+
+// recipeTexts returns texts with whitespaces collapsed, without empty ones.
+func recipeTexts(texts []string) []string {
+	list := []string{}
+	for _, t := range texts {
+		if t = normalizeSpaces(t); t != "" {
+			list = append(list, t)
+		}
+	}
+	return list
+}
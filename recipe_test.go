@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecipeFromJSONLD(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "WebPage", "name": "Pancakes"},
+  {"@type": ["Recipe"], "name": "Pancakes",
+   "recipeIngredient": ["2 cups  flour", "1 egg", ""],
+   "recipeInstructions": [
+     {"@type": "HowToSection", "name": "Batter", "itemListElement": [
+       {"@type": "HowToStep", "text": "Mix the flour and the egg."},
+       {"@type": "HowToStep", "text": "Rest for 10 minutes."}]},
+     {"@type": "HowToStep", "text": "Fry in a hot pan."}],
+   "prepTime": "PT10M", "cookTime": "PT20M", "totalTime": "PT30M", "recipeYield": [4, "4 servings"]}
+]}
+</script>`))
+	assert.Equal(t, &Recipe{
+		Name:        "Pancakes",
+		Ingredients: []string{"2 cups flour", "1 egg"},
+		Steps:       []string{"Mix the flour and the egg.", "Rest for 10 minutes.", "Fry in a hot pan."},
+		PrepTime:    "PT10M",
+		CookTime:    "PT20M",
+		TotalTime:   "PT30M",
+		Yield:       "4",
+	}, recipe(doc))
+}
+
+func TestRecipeFromMicrodata(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div itemscope itemtype="http://schema.org/Recipe">
+<h1 itemprop="name">Tomato Soup</h1>
+<meta itemprop="cookTime" content="PT25M">
+<span itemprop="recipeYield">2 bowls</span>
+<ul><li itemprop="recipeIngredient">4 tomatoes</li><li itemprop="recipeIngredient">1 onion</li></ul>
+<ol itemprop="recipeInstructions"><li>Chop everything.</li><li>Simmer and blend.</li></ol>
+<div itemprop="author" itemscope itemtype="http://schema.org/Person"><span itemprop="name">Cook</span></div>
+</div>`))
+	assert.Equal(t, &Recipe{
+		Name:        "Tomato Soup",
+		Ingredients: []string{"4 tomatoes", "1 onion"},
+		Steps:       []string{"Chop everything.", "Simmer and blend."},
+		CookTime:    "PT25M",
+		Yield:       "2 bowls",
+	}, recipe(doc))
+}
+
+func TestRecipeFromMarkup(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div class="wprm-recipe">
+<span class="wprm-recipe-servings">6</span>
+<span class="wprm-recipe-prep_time">15 minutes</span>
+<div class="wprm-recipe-ingredients-container"><ul class="wprm-recipe-ingredients">
+<li class="wprm-recipe-ingredient">300 g pasta</li><li class="wprm-recipe-ingredient">Salt</li></ul></div>
+<div class="wprm-recipe-instructions-container"><ol>
+<li>Boil the water.</li><li>Cook the pasta.</li></ol></div>
+</div>`))
+	assert.Equal(t, &Recipe{
+		Ingredients: []string{"300 g pasta", "Salt"},
+		Steps:       []string{"Boil the water.", "Cook the pasta."},
+		PrepTime:    "15 minutes",
+		Yield:       "6",
+	}, recipe(doc))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<div class="steps"><ol><li>Sign up.</li></ol></div>`))
+	assert.Nil(t, recipe(doc))
+}
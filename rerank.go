@@ -0,0 +1,104 @@
+package readability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CandidateFeatures contains the features of a description candidate
+// passed to a Reranker.
+type CandidateFeatures struct {
+	// Node is a short description of the candidate node like "div#id.class".
+	Node string
+
+	// Score is the score given by readability rules.
+	Score float64
+
+	// TextLength is the length of the inner text of the candidate.
+	TextLength int
+
+	// LinkDensity is the ratio of link text length to inner text length.
+	LinkDensity float64
+
+	// Depth is the number of ancestors of the candidate node.
+	Depth int
+
+	// ClassTokens contains the lowercased tokens of class and id attributes.
+	ClassTokens []string
+
+	// HTML is the inner HTML of the candidate.
+	HTML string
+}
+
+// Reranker re-ranks the top description candidates, typically using an external model
+// (ONNX runtime, remote service, ...).
+//
+// Rerank receives the features of the top candidates ordered by readability score
+// and returns a new score for each of them. Candidates are reordered by the returned scores.
+// If Rerank returns an error, the original order is kept.
+type Reranker interface {
+	Rerank(candidates []CandidateFeatures) ([]float64, error)
+}
+
+// RerankerFunc is an adapter to allow the use of ordinary functions as a Reranker.
+type RerankerFunc func(candidates []CandidateFeatures) ([]float64, error)
+
+// Rerank calls f(candidates).
+func (f RerankerFunc) Rerank(candidates []CandidateFeatures) ([]float64, error) {
+	return f(candidates)
+}
+
+func rerankCandidates(c *candidates, opt *Option) error {
+	if opt.Reranker == nil || c == nil || len(c.List) < 2 {
+		return nil
+	}
+
+	n := opt.RerankTopN
+	if n <= 0 || n > len(c.List) {
+		n = len(c.List)
+	}
+	top := c.List[:n]
+
+	features := make([]CandidateFeatures, len(top))
+	for i, cand := range top {
+		features[i] = candidateFeatures(cand)
+	}
+
+	scores, err := opt.Reranker.Rerank(features)
+	if err != nil {
+		return fmt.Errorf("Reranker failed: %v", err)
+	}
+	if len(scores) != len(top) {
+		return fmt.Errorf("Reranker returned %v scores for %v candidates", len(scores), len(top))
+	}
+
+	order := make([]int, len(top))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	reranked := make(candidateList, len(top))
+	for i, idx := range order {
+		reranked[i] = top[idx]
+	}
+	copy(c.List, reranked)
+	return nil
+}
+
+func candidateFeatures(c candidate) CandidateFeatures {
+	s := c.Node.Selection
+	cls, _ := s.Attr("class")
+	id, _ := s.Attr("id")
+	return CandidateFeatures{
+		Node:        c.Node.String(),
+		Score:       c.Score,
+		TextLength:  len(s.Text()),
+		LinkDensity: linkDensity(s),
+		Depth:       s.Parents().Length(),
+		ClassTokens: strings.Fields(strings.ToLower(cls + " " + id)),
+		HTML:        c.Node.HTML(),
+	}
+}
@@ -0,0 +1,45 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRerankCandidates(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	opt := NewOption()
+	c, err := prepareCandidates(doc, opt)
+	assert.Nil(t, err)
+	assert.True(t, len(c.List) > 1)
+	best := c.List[0]
+
+	var got []CandidateFeatures
+	opt.Reranker = RerankerFunc(func(cs []CandidateFeatures) ([]float64, error) {
+		got = cs
+		scores := make([]float64, len(cs))
+		for i := range cs {
+			scores[i] = float64(i)
+		}
+		return scores, nil
+	})
+	assert.Nil(t, rerankCandidates(c, opt))
+	assert.Equal(t, len(c.List), len(got))
+	assert.Equal(t, "div#content.article", got[0].Node)
+	assert.Contains(t, got[0].ClassTokens, "article")
+	assert.Contains(t, got[0].ClassTokens, "content")
+	assert.True(t, got[0].Depth > 0)
+	assert.NotEqual(t, best.Node.String(), c.List[0].Node.String())
+	assert.Equal(t, best.Node.String(), c.List[len(c.List)-1].Node.String())
+
+	// original order is kept on errors
+	opt.Reranker = RerankerFunc(func(cs []CandidateFeatures) ([]float64, error) {
+		return nil, fmt.Errorf("unavailable")
+	})
+	first := c.List[0].Node.String()
+	assert.NotNil(t, rerankCandidates(c, opt))
+	assert.Equal(t, first, c.List[0].Node.String())
+}
@@ -0,0 +1,64 @@
+package readability
+
+import (
+	"regexp"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// responsiveClass matches classes of CSS frameworks (Bootstrap, Foundation, Bulma, Tailwind, ...)
+// and common templates which show or hide elements by screen size.
+var responsiveClass = regexp.MustCompile(`(?i)(^|\s)(hidden-(xs|sm|md|lg|xl)|visible-(xs|sm|md|lg|xl)(-block|-inline|-inline-block)?|d-(sm|md|lg|xl)-(none|block|flex|inline)|d-none|show-for-[a-z-]+|hide-for-[a-z-]+|is-hidden-[a-z]+|(sm|md|lg|xl):(hidden|block|flex)|(mobile|desktop|tablet)(-only)?|only-(mobile|desktop|tablet)|hide-(mobile|desktop|tablet)|show-(mobile|desktop|tablet))(\s|$)`)
+
+// removeResponsiveDuplicates removes elements shown only on some screen sizes
+// whose text is the same as another element of the same tag, since responsive templates
+// often repeat the same content for mobile and desktop and hide one of them with CSS.
+// Of each duplicate pair, the element with a responsive class is removed, keeping the other one.
+func removeResponsiveDuplicates(doc *goquery.Document, opt *Option) {
+	if !opt.RemoveResponsiveDuplicates {
+		return
+	}
+
+	removed := map[*html.Node]bool{}
+	doc.Find("[class]").Each(func(_ int, s *goquery.Selection) {
+		n := s.Get(0)
+		if !responsiveClass.MatchString(s.AttrOr("class", "")) || isRemoved(n, removed) {
+			return
+		}
+		text := plainText(s)
+		if len(text) < opt.MinTextLength {
+			return
+		}
+		doc.Find(goquery.NodeName(s)).EachWithBreak(func(_ int, o *goquery.Selection) bool {
+			m := o.Get(0)
+			if m == n || isRemoved(m, removed) || isAncestor(n, m) || isAncestor(m, n) || plainText(o) != text {
+				return true
+			}
+			opt.trace.addRemoval(RuleResponsiveDuplicate, "", n)
+			removed[n] = true
+			s.Remove()
+			return false
+		})
+	})
+}
+
+// isRemoved returns true if n or one of its ancestors is in removed.
+func isRemoved(n *html.Node, removed map[*html.Node]bool) bool {
+	for ; n != nil; n = n.Parent {
+		if removed[n] {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor returns true if a is an ancestor of n.
+func isAncestor(a, n *html.Node) bool {
+	for n = n.Parent; n != nil; n = n.Parent {
+		if n == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveResponsiveDuplicates(t *testing.T) {
+	html := `<body>
+<div class="summary d-md-none"><p>The quick brown fox jumps over the lazy dog.</p></div>
+<div class="summary d-none d-md-block"><p>The quick brown fox jumps over the lazy dog.</p></div>
+<div class="hidden-xs"><p>Only on desktop, without a duplicate anywhere.</p></div>
+<p class="mobile">Short</p><p>Short</p>
+<ul class="desktop-only"><li>Not the same text as the other list at all.</li></ul><ul><li>Other</li></ul>
+</body>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	removeResponsiveDuplicates(doc, NewOption())
+	assert.Equal(t, 1, doc.Find("div.summary").Length())
+	assert.True(t, doc.Find("div.summary").HasClass("d-md-block"))
+	assert.Equal(t, 1, doc.Find("div.hidden-xs").Length())
+	assert.Equal(t, 2, doc.Find("p").Length()-doc.Find("div p").Length())
+	assert.Equal(t, 2, doc.Find("ul").Length())
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	opt.RemoveResponsiveDuplicates = false
+	removeResponsiveDuplicates(doc, opt)
+	assert.Equal(t, 2, doc.Find("div.summary").Length())
+}
@@ -0,0 +1,215 @@
+package readability
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// paragraphScore is the score given by a <p> or <td> to its parent or grandparent.
+type paragraphScore struct {
+	node  *html.Node
+	score float64
+}
+
+// collectParagraphScores returns the scores given by all <p> and <td> of doc to their parents
+// and grandparents, in document order. The tree is walked once, and if opt.ParallelScoring is set,
+// the sections of the page are walked in parallel.
+func collectParagraphScores(ctx context.Context, doc *goquery.Document, opt *Option) []paragraphScore {
+	body := doc.Find("body").First()
+	if !opt.ParallelScoring || body.Length() == 0 {
+		return scoreParagraphs(ctx, doc.Get(0), opt, nil)
+	}
+
+	chain, sections := pageSections(body.Get(0))
+	if len(sections) < 2 {
+		return scoreParagraphs(ctx, doc.Get(0), opt, nil)
+	}
+	var scores []paragraphScore
+	for _, n := range chain {
+		scores = appendParagraphScore(n, opt, scores)
+	}
+
+	results := make([][]paragraphScore, len(sections))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, n := range sections {
+		wg.Add(1)
+		go func(i int, n *html.Node) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = scoreParagraphs(ctx, n, opt, nil)
+		}(i, n)
+	}
+	wg.Wait()
+	for _, r := range results {
+		scores = append(scores, r...)
+	}
+	return scores
+}
+
+// pageSections returns the chain of single-child wrappers from body
+// and the sections below them, which are the element children of the last wrapper.
+func pageSections(body *html.Node) (chain, sections []*html.Node) {
+	for n := body; ; {
+		chain = append(chain, n)
+		var children []*html.Node
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				children = append(children, c)
+			}
+		}
+		if len(children) != 1 {
+			return chain, children
+		}
+		n = children[0]
+	}
+}
+
+// scoreParagraphs appends the scores given by <p> and <td> in n and its descendants to scores,
+// in document order. It stops if ctx is done.
+func scoreParagraphs(ctx context.Context, n *html.Node, opt *Option, scores []paragraphScore) []paragraphScore {
+	if ctx.Err() != nil {
+		return scores
+	}
+	scores = appendParagraphScore(n, opt, scores)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			scores = scoreParagraphs(ctx, c, opt, scores)
+		}
+	}
+	return scores
+}
+
+// appendParagraphScore appends the scores given by n to its parent and grandparent
+// if n is a <p> or <td> with text at least Option.MinTextLength.
+func appendParagraphScore(n *html.Node, opt *Option, scores []paragraphScore) []paragraphScore {
+	if n.Type != html.ElementNode || n.Data != "p" && n.Data != "td" {
+		return scores
+	}
+	parent := n.Parent
+	if parent == nil || parent.Type != html.ElementNode {
+		return scores
+	}
+	innerText := nodeText(n)
+	if len(innerText) < opt.MinTextLength {
+		return scores
+	}
+
+	score := 1.0
+	score += float64(strings.Count(innerText, ",") + 1)
+	score += math.Min((float64(len(innerText)) / 100.0), 3.0)
+
+	scores = append(scores, paragraphScore{node: parent, score: score})
+	if gp := parent.Parent; gp != nil && gp.Type == html.ElementNode {
+		scores = append(scores, paragraphScore{node: gp, score: score / 2.0})
+	}
+	return scores
+}
+
+// nodeText returns the concatenated text nodes of n, same as goquery's Selection.Text.
+func nodeText(n *html.Node) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return buf.String()
+}
+
+// isBlank returns true if n has no text other than whitespaces, without collecting the text.
+func isBlank(n *html.Node) bool {
+	if n.Type == html.TextNode && strings.TrimSpace(n.Data) != "" {
+		return false
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if !isBlank(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// subtreeStats are the counts of elements, text and link text in a subtree,
+// collected in a single walk for cleanConditionally.
+type subtreeStats struct {
+	counts         map[string]int // descendant elements by tag name
+	noscriptImages int            // <img>s under <noscript>
+	text           string
+	linkTextLength int // text length under <a>s, counted once per <a> ancestor
+}
+
+func newSubtreeStats(n *html.Node) *subtreeStats {
+	st := &subtreeStats{counts: map[string]int{}}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	var walk func(n *html.Node, links int, noscript bool)
+	walk = func(n *html.Node, links int, noscript bool) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				buf.WriteString(c.Data)
+				st.linkTextLength += len(c.Data) * links
+			case html.ElementNode:
+				st.counts[c.Data]++
+				if noscript && c.Data == "img" {
+					st.noscriptImages++
+				}
+				l := links
+				if c.Data == "a" {
+					l++
+				}
+				walk(c, l, noscript || c.Data == "noscript")
+			default:
+				walk(c, links, noscript)
+			}
+		}
+	}
+	walk(n, 0, false)
+	st.text = buf.String()
+	return st
+}
+
+// linkDensity is the same as linkDensity of the root of the subtree.
+func (st *subtreeStats) linkDensity() float64 {
+	if len(st.text) == 0 {
+		return 0
+	}
+	return float64(st.linkTextLength) / float64(len(st.text))
+}
+
+// forEach calls f with 0 to n-1, concurrently with a goroutine per CPU if parallel is true.
+func forEach(n int, parallel bool, f func(i int)) {
+	workers := runtime.NumCPU()
+	if !parallel || workers < 2 || n < 2 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				f(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+// largePage returns a page with n sections of paragraphs and tables inside a wrapper.
+func largePage(n int) string {
+	var sb strings.Builder
+	sb.WriteString(`<html><body><div id="wrapper"><p>Paragraph directly in the wrapper, with a comma.</p>`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, `<div class="section-%d"><div class="content"><p>Section %d, lorem ipsum dolor sit amet, consectetur adipiscing elit.</p>`, i, i)
+		fmt.Fprintf(&sb, `<p>Sed do eiusmod tempor incididunt ut labore et dolore magna aliqua, <a href="/%d">link</a>.</p></div>`, i)
+		fmt.Fprintf(&sb, `<table><tr><td>Table cell %d with enough text to be scored.</td></tr></table></div>`, i)
+	}
+	sb.WriteString(`</div></body></html>`)
+	return sb.String()
+}
+
+func TestParallelScoring(t *testing.T) {
+	html := largePage(50)
+	opt := NewOption()
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	sequential, err := getCandidates(doc, opt)
+	assert.Nil(t, err)
+
+	opt.ParallelScoring = true
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	parallel, err := getCandidates(doc, opt)
+	assert.Nil(t, err)
+
+	assert.Equal(t, len(sequential.Map), len(parallel.Map))
+	for k, c := range sequential.Map {
+		assert.Equal(t, c.Score, parallel.Map[k].Score)
+		assert.Equal(t, c.RawScore, parallel.Map[k].RawScore)
+	}
+}
+
+func TestPageSections(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<body><div id="a"><div id="b"><p>1</p><p>2</p>text</div></div></body>`))
+	chain, sections := pageSections(doc.Find("body").Get(0))
+	assert.Equal(t, 3, len(chain))
+	assert.Equal(t, "b", chain[2].Attr[0].Val)
+	assert.Equal(t, 2, len(sections))
+}
+
+func benchmarkGetCandidates(b *testing.B, parallel bool) {
+	html := largePage(2000)
+	opt := NewOption()
+	opt.DescriptionExtractionTimeout = 60000
+	opt.ParallelScoring = parallel
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+		b.StartTimer()
+		getCandidates(doc, opt)
+	}
+}
+
+func BenchmarkGetCandidates(b *testing.B) {
+	benchmarkGetCandidates(b, false)
+}
+
+func BenchmarkGetCandidatesParallel(b *testing.B) {
+	benchmarkGetCandidates(b, true)
+}
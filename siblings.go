@@ -0,0 +1,33 @@
+package readability
+
+import "math"
+
+// SiblingThresholds are the thresholds deciding which siblings of the best candidate
+// are included in the article with it. A sibling is included if its score is at least
+// max(MinScore, ScoreRatio * the score of the best candidate), or if it is a <p> qualified
+// by its length: longer than ParagraphLength with link density less than MaxLinkDensity,
+// or shorter than ParagraphLength without links and ending with a sentence.
+type SiblingThresholds struct {
+	MinScore        float64 `json:"minScore"`
+	ScoreRatio      float64 `json:"scoreRatio"`
+	ParagraphLength int     `json:"paragraphLength"`
+	MaxLinkDensity  float64 `json:"maxLinkDensity"`
+}
+
+// NewSiblingThresholds returns the thresholds used if Option.SiblingThresholds is nil.
+func NewSiblingThresholds() *SiblingThresholds {
+	return &SiblingThresholds{MinScore: 10, ScoreRatio: 0.2, ParagraphLength: 80, MaxLinkDensity: 0.25}
+}
+
+// siblingThresholds returns opt.SiblingThresholds, or the default thresholds if it is nil.
+func siblingThresholds(opt *Option) *SiblingThresholds {
+	if opt.SiblingThresholds != nil {
+		return opt.SiblingThresholds
+	}
+	return NewSiblingThresholds()
+}
+
+// scoreThreshold returns the minimum score of siblings of the best candidate of bestScore.
+func (t *SiblingThresholds) scoreThreshold(bestScore float64) float64 {
+	return math.Max(t.MinScore, bestScore*t.ScoreRatio)
+}
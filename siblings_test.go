@@ -0,0 +1,56 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+var siblingsArticle = `<html><head><title>Siblings</title></head><body>
+<div id="content" class="article">
+<p>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.</p>
+<p>Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat, duis aute irure.</p>
+<p>Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est laborum, sed ut perspiciatis.</p>
+</div>
+<p>A short closing note of the story ends here.</p>
+<p>Read more about this story <a href="/related">in the related article about the story</a> and the other news of the day</p>
+</body></html>`
+
+// siblingDecisions returns the sibling score threshold and the decisions of the <p> siblings,
+// the short note and the paragraph with a link.
+func siblingDecisions(t *testing.T, thresholds *SiblingThresholds) (float64, []SiblingDecision) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(siblingsArticle))
+	opt := NewOption()
+	opt.Explain = true
+	opt.RetryLength = 0
+	opt.SiblingThresholds = thresholds
+	_, exp := description(doc, opt)
+	var ds []SiblingDecision
+	for _, d := range exp.Siblings {
+		if strings.HasPrefix(d.Node, "p") {
+			ds = append(ds, d)
+		}
+	}
+	assert.Equal(t, 2, len(ds))
+	return exp.SiblingScoreThreshold, ds
+}
+
+func TestSiblingThresholds(t *testing.T) {
+	threshold, ds := siblingDecisions(t, nil)
+	assert.InDelta(t, 12.046, threshold, 0.001)
+	assert.Equal(t, ReasonShortSentence, ds[0].Code)
+	assert.True(t, ds[0].Kept)
+	assert.False(t, ds[1].Kept)
+
+	threshold, ds = siblingDecisions(t, &SiblingThresholds{MinScore: 1000, ScoreRatio: 0.2, ParagraphLength: 40, MaxLinkDensity: 0.6})
+	assert.Equal(t, 1000.0, threshold)
+	assert.Equal(t, ReasonLongParagraph, ds[0].Code)
+	assert.Equal(t, ReasonLongParagraph, ds[1].Code)
+	assert.True(t, ds[1].Kept)
+
+	opt := NewOption()
+	opt.SiblingThresholds = &SiblingThresholds{ParagraphLength: -1}
+	assert.NotNil(t, opt.Validate())
+}
@@ -0,0 +1,256 @@
+package readability
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Snapshot is a readable snapshot of a page, written as a self-contained bundle for archiving
+// by WriteZip or WriteTar. The bundle contains:
+//
+//	manifest.json   SnapshotManifest
+//	content.json    Content in the JSON schema of this package
+//	index.html      the title and the article, referring to the downloaded images
+//	images/         the images of the article and Content.Images, named like "1.jpg"
+type Snapshot struct {
+	// URL is the original URL of the page.
+	URL string
+
+	// FetchedAt is when the page is fetched. It is also the modification time of the files.
+	FetchedAt time.Time
+
+	Content *Content
+}
+
+// SnapshotManifest describes the files of a snapshot bundle.
+type SnapshotManifest struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Title     string    `json:"title"`
+
+	// Images contains the images downloaded, or failed to download, in the order of their first use.
+	Images []SnapshotImage `json:"images"`
+}
+
+// SnapshotImage is an image of a snapshot bundle.
+type SnapshotImage struct {
+	// URL is the original URL of the image.
+	URL string `json:"url"`
+
+	// Path is the path of the downloaded image in the bundle, omitted if it is failed to download.
+	Path string `json:"path,omitempty"`
+
+	// Error is the error downloading the image, omitted if it is downloaded.
+	Error string `json:"error,omitempty"`
+}
+
+// WriteZip writes the snapshot bundle to w as a zip archive.
+// Images are downloaded by Image.Fetch with opt; failures are recorded in the manifest.
+// An error is returned if ctx is done or w fails.
+func (s *Snapshot) WriteZip(ctx context.Context, w io.Writer, opt *Option) error {
+	zw := zip.NewWriter(w)
+	err := s.write(ctx, func(name string, b []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: s.FetchedAt})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(b)
+		return err
+	}, opt)
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// WriteTar writes the snapshot bundle to w as an uncompressed tar archive. See WriteZip.
+func (s *Snapshot) WriteTar(ctx context.Context, w io.Writer, opt *Option) error {
+	tw := tar.NewWriter(w)
+	err := s.write(ctx, func(name string, b []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(b)), ModTime: s.FetchedAt, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(b)
+		return err
+	}, opt)
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// write downloads the images and passes each file of the bundle to create.
+func (s *Snapshot) write(ctx context.Context, create func(name string, b []byte) error, opt *Option) error {
+	c := s.Content
+	if c == nil {
+		c = &Content{}
+	}
+	article := snapshotArticle(c)
+
+	// images of the article first, then the images of the page
+	var urls []string
+	if article != nil {
+		article.Find("img[src]").Each(func(_ int, img *goquery.Selection) {
+			urls = append(urls, img.AttrOr("src", ""))
+		})
+	}
+	if c.PrimaryImage != nil {
+		urls = append(urls, c.PrimaryImage.URL)
+	}
+	for _, img := range c.Images {
+		urls = append(urls, img.URL)
+	}
+
+	m := &SnapshotManifest{URL: s.URL, FetchedAt: s.FetchedAt, Title: c.Title, Images: []SnapshotImage{}}
+	paths := map[string]string{}
+	var files []struct {
+		name string
+		b    []byte
+	}
+	for _, u := range urls {
+		if _, ok := paths[u]; ok || strings.TrimSpace(u) == "" {
+			continue
+		}
+		b, err := Image{URL: u}.Fetch(ctx, opt)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		si := SnapshotImage{URL: u}
+		if err != nil {
+			si.Error = err.Error()
+			paths[u] = ""
+		} else {
+			si.Path = fmt.Sprintf("images/%d%s", len(files)+1, snapshotImageExt(b, u))
+			paths[u] = si.Path
+			files = append(files, struct {
+				name string
+				b    []byte
+			}{si.Path, b})
+		}
+		m.Images = append(m.Images, si)
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	index, err := snapshotIndex(s.URL, c, article, paths)
+	if err != nil {
+		return err
+	}
+	if err := create("manifest.json", manifest); err != nil {
+		return err
+	}
+	if err := create("content.json", content); err != nil {
+		return err
+	}
+	if err := create("index.html", index); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := create(f.name, f.b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotArticle returns a copy of the article DOM of c, or nil if it is unknown.
+func snapshotArticle(c *Content) *goquery.Document {
+	n := c.ArticleNode()
+	if n == nil {
+		return nil
+	}
+	return goquery.CloneDocument(goquery.NewDocumentFromNode(n))
+}
+
+// snapshotIndex returns index.html of a snapshot: the title, the primary image unless
+// the article contains it, and the article whose images refer to paths.
+// If article is nil, the description is written in paragraphs.
+func snapshotIndex(reqURL string, c *Content, article *goquery.Document, paths map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	esc := html.EscapeString
+	buf.WriteString("<!DOCTYPE html>\n<html")
+	if c.TextDirection != "" {
+		buf.WriteString(` dir="` + esc(string(c.TextDirection)) + `"`)
+	}
+	buf.WriteString(">\n<head>\n<meta charset=\"utf-8\">\n<title>" + esc(c.Title) + "</title>\n")
+	buf.WriteString(`<link rel="canonical" href="` + esc(reqURL) + "\">\n</head>\n<body>\n")
+	buf.WriteString("<h1>" + esc(c.Title) + "</h1>\n")
+
+	inArticle := false
+	if article != nil && c.PrimaryImage != nil {
+		article.Find("img[src]").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+			inArticle = img.AttrOr("src", "") == c.PrimaryImage.URL
+			return !inArticle
+		})
+	}
+	if c.PrimaryImage != nil && !inArticle {
+		if p := paths[c.PrimaryImage.URL]; p != "" {
+			buf.WriteString(`<figure><img src="` + esc(p) + `"></figure>` + "\n")
+		}
+	}
+
+	if article == nil {
+		for _, p := range descriptionParagraphs(c.Description) {
+			buf.WriteString("<p>" + esc(p) + "</p>\n")
+		}
+	} else {
+		article.Find("img").Each(func(_ int, img *goquery.Selection) {
+			img.RemoveAttr("srcset")
+			if p := paths[img.AttrOr("src", "")]; p != "" {
+				img.SetAttr("src", p)
+			}
+		})
+		for n := article.Get(0).FirstChild; n != nil; n = n.NextSibling {
+			if err := html.Render(&buf, n); err != nil {
+				return nil, err
+			}
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes(), nil
+}
+
+// snapshotImageExts maps content types of images to file extensions.
+var snapshotImageExts = map[string]string{
+	"image/jpeg": ".jpg", "image/png": ".png", "image/gif": ".gif", "image/webp": ".webp",
+	"image/bmp": ".bmp", "image/x-icon": ".ico", "image/vnd.microsoft.icon": ".ico",
+}
+
+// snapshotImageExt returns the file extension of the image b downloaded from src,
+// detected from its content, or taken from src, or "" if unknown.
+func snapshotImageExt(b []byte, src string) string {
+	if isAVIF(b) {
+		return ".avif"
+	}
+	if ext, ok := snapshotImageExts[http.DetectContentType(b)]; ok {
+		return ext
+	}
+	if u, err := url.Parse(src); err == nil && !isDataURI(src) {
+		switch ext := strings.ToLower(path.Ext(u.Path)); ext {
+		case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif", ".svg", ".bmp", ".ico":
+			return ext
+		}
+	}
+	return ""
+}
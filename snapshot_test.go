@@ -0,0 +1,122 @@
+package readability
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshotServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a.png":
+			w.Write(pngBytes(400, 300))
+		case "/b.png":
+			w.Write(pngBytes(300, 200))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestSnapshotWriteZip(t *testing.T) {
+	ts := snapshotServer()
+	defer ts.Close()
+
+	p := strings.Repeat("lorem ipsum dolor sit amet, ", 10)
+	page := `<html><body><div class="article"><p>First ` + p + `<img src="/a.png" width="400" height="300"></p>` +
+		`<p>Second &lt;b&gt; ` + p + `<img src="/missing.png"></p></div></body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	opt := NewOption()
+	opt.DescriptionAsPlainText = false
+	opt.HTMLPolicy = NewHTMLPolicy()
+	opt.LookupOpenGraphTags = false
+	opt.LookupStructuredData = false
+	opt.MinImageWidth, opt.MinImageHeight = 100, 100
+	c, err := ExtractFromDocument(doc, ts.URL+"/news/1", opt)
+	assert.Nil(t, err)
+	c.PrimaryImage = &Image{URL: ts.URL + "/b.png"}
+
+	fetchedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &Snapshot{URL: ts.URL + "/news/1", FetchedAt: fetchedAt, Content: c}
+	var buf bytes.Buffer
+	assert.Nil(t, s.WriteZip(context.Background(), &buf, opt))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, _ := f.Open()
+		files[f.Name], _ = ioutil.ReadAll(r)
+		r.Close()
+		assert.True(t, f.Modified.Equal(fetchedAt), f.Name)
+	}
+	assert.Equal(t, 5, len(files)) // manifest, content, index and 2 images
+
+	var m SnapshotManifest
+	assert.Nil(t, json.Unmarshal(files["manifest.json"], &m))
+	assert.Equal(t, s.URL, m.URL)
+	assert.True(t, m.FetchedAt.Equal(fetchedAt))
+	assert.Equal(t, []SnapshotImage{
+		{URL: ts.URL + "/a.png", Path: "images/1.png"},
+		{URL: ts.URL + "/missing.png", Error: (&HTTPError{URL: ts.URL + "/missing.png", StatusCode: 404}).Error()},
+		{URL: ts.URL + "/b.png", Path: "images/2.png"},
+	}, m.Images)
+	assert.Equal(t, pngBytes(400, 300), files["images/1.png"])
+
+	var content Content
+	assert.Nil(t, json.Unmarshal(files["content.json"], &content))
+	assert.Equal(t, c.Description, content.Description)
+
+	index := string(files["index.html"])
+	assert.Contains(t, index, `<figure><img src="images/2.png"></figure>`)
+	assert.Contains(t, index, `<img src="images/1.png" width="400" height="300"/>`)
+	assert.Contains(t, index, `<img src="`+ts.URL+`/missing.png"/>`)
+	assert.Contains(t, index, `Second &lt;b&gt; lorem`)
+}
+
+func TestSnapshotWriteTar(t *testing.T) {
+	c := &Content{Title: "Title <1>", Description: "First.\n\nSecond & third.", TextDirection: TextDirectionLTR}
+	s := &Snapshot{URL: "https://example.com/1", FetchedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Content: c}
+	var buf bytes.Buffer
+	assert.Nil(t, s.WriteTar(context.Background(), &buf, nil))
+
+	tr := tar.NewReader(&buf)
+	var names []string
+	var index string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		names = append(names, hdr.Name)
+		if hdr.Name == "index.html" {
+			b, _ := ioutil.ReadAll(tr)
+			index = string(b)
+		}
+	}
+	assert.Equal(t, []string{"manifest.json", "content.json", "index.html"}, names)
+	assert.Contains(t, index, `<html dir="ltr">`)
+	assert.Contains(t, index, "<title>Title &lt;1&gt;</title>")
+	assert.Contains(t, index, "<p>First.</p>\n<p>Second &amp; third.</p>")
+}
+
+func TestSnapshotCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := &Snapshot{URL: "https://example.com/1", Content: &Content{Images: []Image{{URL: "https://example.com/a.png"}}}}
+	assert.Equal(t, context.Canceled, s.WriteZip(ctx, ioutil.Discard, nil))
+}
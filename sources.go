@@ -0,0 +1,121 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Source is an entry of the sources cited by an article, such as an item of a reference list
+// or of a "Sources:" block.
+type Source struct {
+	// Text is the plain text of the entry.
+	Text string `json:"text"`
+
+	// URL is the absolute URL of the first link of the entry, except links within the page.
+	URL string `json:"url,omitempty"`
+}
+
+var (
+	// sourcesHeading matches the text of headings of source lists, like "References" or "Sources:".
+	sourcesHeading = regexp.MustCompile(`(?i)^\s*(references|sources|bibliography|works cited|citations|notes and references|footnotes)\s*:?\s*$`)
+
+	// sourcesClass matches class and id of source lists and their containers, like "reflist".
+	sourcesClass = regexp.MustCompile(`(?i)\b(references|reflist|citations|bibliography|footnotes|sources)\b`)
+
+	// sourcesLead matches the beginning of paragraphs listing sources, like "Sources: Reuters; AP".
+	sourcesLead = regexp.MustCompile(`(?i)^\s*(sources?|references)\s*:\s*`)
+)
+
+// maxSources is the maximum number of sources of a page.
+const maxSources = 100
+
+// sources returns the deduplicated sources cited by doc in document order:
+// the items of lists following a heading like "References" or inside a container like
+// <div class="reflist">, and the entries of paragraphs like "Sources: Reuters; AP".
+func sources(doc *goquery.Document, reqURL string) []Source {
+	lists := map[*html.Node]bool{}
+	doc.Find("h2, h3, h4, h5, h6, p").Each(func(_ int, h *goquery.Selection) {
+		if !sourcesHeading.MatchString(h.Text()) {
+			return
+		}
+		for s := h.Next(); s.Length() > 0 && !s.Is("h1, h2, h3, h4, h5, h6"); s = s.Next() {
+			found := s.Find("ol, ul").AddSelection(s.Filter("ol, ul"))
+			found.Each(func(_ int, l *goquery.Selection) {
+				lists[l.Get(0)] = true
+			})
+			if found.Length() > 0 {
+				break
+			}
+		}
+	})
+	doc.Find("ol, ul").Each(func(_ int, l *goquery.Selection) {
+		for s, i := l, 0; s.Length() > 0 && i < 3; s, i = s.Parent(), i+1 {
+			if sourcesClass.MatchString(s.AttrOr("class", "") + " " + s.AttrOr("id", "")) {
+				lists[l.Get(0)] = true
+				return
+			}
+		}
+	})
+
+	var list []Source
+	seen := map[Source]bool{}
+	add := func(src Source) {
+		if src.Text == "" || seen[src] || len(list) >= maxSources {
+			return
+		}
+		seen[src] = true
+		list = append(list, src)
+	}
+	doc.Find("li, p").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "li" {
+			if lists[s.Parent().Get(0)] && s.ParentsFiltered("li").Length() == 0 {
+				add(Source{Text: sourceText(s.Text()), URL: sourceURL(s, reqURL)})
+			}
+			return
+		}
+
+		text := s.Text()
+		loc := sourcesLead.FindStringIndex(text)
+		if loc == nil {
+			return
+		}
+		if links := s.Find("a[href]"); links.Length() > 0 {
+			links.Each(func(_ int, a *goquery.Selection) {
+				add(Source{Text: sourceText(a.Text()), URL: sourceURL(a, reqURL)})
+			})
+			return
+		}
+		for _, entry := range strings.Split(text[loc[1]:], ";") {
+			add(Source{Text: sourceText(entry)})
+		}
+	})
+	return list
+}
+
+// sourceText returns text normalized for Source.Text, without the back-reference marks
+// of footnotes like "^" or "↑".
+func sourceText(text string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.Join(strings.Fields(text), " "), "^↑ "))
+}
+
+// sourceURL returns the absolute URL of the first link of s (or of s itself),
+// except links within the page, or "" if none.
+func sourceURL(s *goquery.Selection, reqURL string) string {
+	u := ""
+	s.Find("a[href]").AddSelection(s.Filter("a[href]")).EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		href := strings.TrimSpace(a.AttrOr("href", ""))
+		if href == "" || strings.HasPrefix(href, "#") {
+			return true
+		}
+		abs, err := absPath(href, reqURL)
+		if err != nil {
+			return true
+		}
+		u = abs
+		return false
+	})
+	return u
+}
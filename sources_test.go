@@ -0,0 +1,47 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSources(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		html string
+		want []Source
+	}{
+		{"none", `<p>Body text.</p><ul><li>Home</li></ul>`, nil},
+		{"heading", `<p>Body text.</p>
+<h2>References</h2>
+<ol>
+  <li>Doe, J. (2020). <a href="https://journal.example.org/a/1">A study</a>.</li>
+  <li>Roe, R. <i>A book</i>. 2019.</li>
+  <li>Roe, R. <i>A book</i>. 2019.</li>
+</ol>
+<h2>See also</h2><ul><li><a href="/other">Other page</a></li></ul>`, []Source{
+			{Text: "Doe, J. (2020). A study.", URL: "https://journal.example.org/a/1"},
+			{Text: "Roe, R. A book. 2019."},
+		}},
+		{"class", `<div class="reflist"><ol class="references">
+<li id="cite_note-1"><a href="#cite_ref-1">^</a> <a href="/wiki/Source">Source title</a></li>
+</ol></div>`, []Source{
+			{Text: "Source title", URL: "https://example.com/wiki/Source"},
+		}},
+		{"lead", `<p>Sources: Reuters; Associated Press</p>
+<p>Source: <a href="https://data.example.org/">Example Data</a></p>`, []Source{
+			{Text: "Reuters"},
+			{Text: "Associated Press"},
+			{Text: "Example Data", URL: "https://data.example.org/"},
+		}},
+		{"strong heading", `<p><strong>Sources:</strong></p><ul><li>Interview with the author</li></ul>`, []Source{
+			{Text: "Interview with the author"},
+		}},
+	} {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+		assert.Equal(t, tc.want, sources(doc, "https://example.com/article"), tc.name)
+	}
+}
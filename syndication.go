@@ -0,0 +1,97 @@
+package readability
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SyndicationSignal is how the original source of a syndicated article is found.
+type SyndicationSignal string
+
+// Signals of syndication, in order of precedence.
+const (
+	// SyndicationJSONLD is isBasedOn of the JSON-LD article.
+	SyndicationJSONLD SyndicationSignal = "json-ld"
+
+	// SyndicationCanonical is <link rel="canonical"> pointing to another site.
+	SyndicationCanonical SyndicationSignal = "canonical"
+
+	// SyndicationText is a link in a credit line like "This article was originally published on ...".
+	SyndicationText SyndicationSignal = "text"
+)
+
+// Syndication is the original source of a syndicated article.
+type Syndication struct {
+	// URL is the absolute URL of the original article, or of the original site for some credit lines.
+	URL string `json:"url"`
+
+	// Signal is how URL is found.
+	Signal SyndicationSignal `json:"signal"`
+}
+
+// syndicationCredit matches credit lines of republished articles.
+var syndicationCredit = regexp.MustCompile(`(?i)\b(originally|first) (published|appeared|posted)\b|\brepublished (from|with permission|under)\b|\bsyndicated from\b`)
+
+// maxCreditLength is the maximum text length of a credit line around a link.
+const maxCreditLength = 300
+
+// originalSource returns the original source of the article at reqURL if it is republished
+// from another page, or nil. md is the structured data of the page.
+func originalSource(doc *goquery.Document, reqURL string, md *Metadata) *Syndication {
+	if md != nil && md.BasedOn != "" && md.BasedOn != reqURL {
+		return &Syndication{URL: md.BasedOn, Signal: SyndicationJSONLD}
+	}
+	if href, ok := doc.Find(`link[rel~="canonical"]`).First().Attr("href"); ok {
+		if u, err := absPath(strings.TrimSpace(href), reqURL); err == nil && !sameSite(u, reqURL) {
+			return &Syndication{URL: u, Signal: SyndicationCanonical}
+		}
+	}
+
+	var src *Syndication
+	doc.Find("body a[href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		u, err := absPath(strings.TrimSpace(s.AttrOr("href", "")), reqURL)
+		if err != nil || sameSite(u, reqURL) || !isCreditLine(s) {
+			return true
+		}
+		src = &Syndication{URL: u, Signal: SyndicationText}
+		return false
+	})
+	return src
+}
+
+// isCreditLine returns true if the parent or the grandparent of the link s is a short text
+// like "This story was originally published by The Example Times".
+func isCreditLine(s *goquery.Selection) bool {
+	for p, i := s.Parent(), 0; p.Length() > 0 && i < 2; p, i = p.Parent(), i+1 {
+		text := p.Text()
+		if len(text) > maxCreditLength {
+			return false
+		}
+		if syndicationCredit.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSite returns true if the hosts of URLs a and b are the same or one is a subdomain of the other,
+// ignoring "www.", so that mobile and AMP subdomains are not taken as other sites.
+func sameSite(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return true
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return true
+	}
+	ha := strings.TrimPrefix(strings.ToLower(ua.Hostname()), "www.")
+	hb := strings.TrimPrefix(strings.ToLower(ub.Hostname()), "www.")
+	if ha == "" || hb == "" {
+		return true
+	}
+	return ha == hb || strings.HasSuffix(ha, "."+hb) || strings.HasSuffix(hb, "."+ha)
+}
@@ -0,0 +1,41 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginalSource(t *testing.T) {
+	tests := []struct {
+		head string
+		body string
+		want *Syndication
+	}{
+		{``, `<p>Nothing special. <a href="https://other.example.org/">Other</a></p>`, nil},
+		{`<link rel="canonical" href="https://m.example.com/a">`, ``, nil},
+		{`<link rel="canonical" href="https://origin.example.org/a">`, ``,
+			&Syndication{URL: "https://origin.example.org/a", Signal: SyndicationCanonical}},
+		{`<script type="application/ld+json">{"@type": "NewsArticle", "headline": "A", "isBasedOn": {"@id": "https://wire.example.net/a"}}</script>
+<link rel="canonical" href="https://origin.example.org/a">`, ``,
+			&Syndication{URL: "https://wire.example.net/a", Signal: SyndicationJSONLD}},
+		{``, `<p><em>This article was originally published on <a href="https://blog.example.org/post">Example Blog</a>.</em></p>
+<p><a href="/related">Related</a></p>`,
+			&Syndication{URL: "https://blog.example.org/post", Signal: SyndicationText}},
+		{``, `<p>Originally published at <a href="/elsewhere">our archive</a>.</p>`, nil},
+	}
+	for _, tt := range tests {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>` + tt.head + `</head><body>` + tt.body + `</body></html>`))
+		md := getMetadata(doc, "https://www.example.com/news/1")
+		assert.Equal(t, tt.want, originalSource(doc, "https://www.example.com/news/1", md), tt.head+tt.body)
+	}
+}
+
+func TestSameSite(t *testing.T) {
+	assert.True(t, sameSite("https://www.example.com/a", "http://example.com/b"))
+	assert.True(t, sameSite("https://amp.example.com/a", "https://example.com/b"))
+	assert.False(t, sameSite("https://example.org/a", "https://example.com/b"))
+	assert.False(t, sameSite("https://notexample.com/a", "https://example.com/b"))
+}
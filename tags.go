@@ -0,0 +1,124 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var tagListSelector = strings.Join([]string{
+	`a[rel~="tag"]`,
+	".tags a",
+	".tag-list a",
+	".tag_list a",
+	".post-tags a",
+	".article-tags a",
+	".entry-tags a",
+	".keywords a",
+}, ", ")
+
+// tags returns the deduplicated and normalized tags of the document, collected from
+// article:tag meta properties, keywords meta, JSON-LD keywords and visible tag lists, in this order.
+// Tag lists are collected from the scope of the article whose best candidate is top (see tagScope),
+// or from the whole document if the scope has none, leaving out <aside> and <nav> either way,
+// so that site-wide tag clouds don't add tags of other articles.
+func tags(doc *goquery.Document, top *html.Node) []string {
+	ts := &tagSet{seen: map[string]bool{}, list: []string{}}
+
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		content, ok := s.Attr("content")
+		if !ok {
+			return
+		}
+		if s.AttrOr("property", "") == "article:tag" {
+			ts.add(content)
+		}
+	})
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		content, ok := s.Attr("content")
+		if !ok {
+			return
+		}
+		if strings.EqualFold(s.AttrOr("name", ""), "keywords") ||
+			strings.EqualFold(s.AttrOr("name", ""), "news_keywords") {
+			ts.addList(content)
+		}
+	})
+
+	for _, obj := range jsonLDObjects(doc) {
+		for _, kw := range jsonLDStrings(obj["keywords"]) {
+			ts.addList(kw)
+		}
+	}
+
+	links := tagLinks(doc.Selection)
+	if scope := tagScope(doc, top); scope != nil {
+		if scoped := tagLinks(doc.FindNodes(scope)); len(scoped) > 0 {
+			links = scoped
+		}
+	}
+	for _, n := range links {
+		ts.add(nodeText(n))
+	}
+
+	return ts.list
+}
+
+// tagScope returns the element holding the tag lists of the article whose best candidate is top:
+// the closest <article> around top, or else the parent of top, which holds the siblings joining
+// the article too. If top is nil, it is the only <article> of doc, or nil if there is not one.
+func tagScope(doc *goquery.Document, top *html.Node) *html.Node {
+	if top == nil {
+		if articles := doc.Find("article"); articles.Length() == 1 {
+			return articles.Get(0)
+		}
+		return nil
+	}
+	for n := top; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && n.Data == "article" {
+			return n
+		}
+	}
+	if top.Parent != nil && top.Parent.Type == html.ElementNode {
+		return top.Parent
+	}
+	return top
+}
+
+// tagLinks returns the links of the tag lists inside s, except those in <aside> or <nav>.
+func tagLinks(s *goquery.Selection) []*html.Node {
+	var links []*html.Node
+	s.Find(tagListSelector).Each(func(i int, link *goquery.Selection) {
+		n := link.Get(0)
+		if !hasAncestor(n, "aside") && !hasAncestor(n, "nav") {
+			links = append(links, n)
+		}
+	})
+	return links
+}
+
+type tagSet struct {
+	seen map[string]bool
+	list []string
+}
+
+func (ts *tagSet) addList(s string) {
+	for _, t := range strings.Split(s, ",") {
+		ts.add(t)
+	}
+}
+
+func (ts *tagSet) add(s string) {
+	t := normalizeTag(s)
+	if t == "" || ts.seen[t] {
+		return
+	}
+	ts.seen[t] = true
+	ts.list = append(ts.list, t)
+}
+
+func normalizeTag(s string) string {
+	s = strings.TrimLeft(strings.TrimSpace(s), "#")
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,51 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	html := `<html><head>
+<meta property="article:tag" content="Go" />
+<meta property="article:tag" content="  Web   Scraping " />
+<meta name="keywords" content="go, readability,, parsing" />
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [{"@type": "NewsArticle", "keywords": ["HTML", "go"]}]}
+</script>
+<script type="application/ld+json">{"@type": "WebPage", "keywords": "crawler, Parsing"}</script>
+</head><body>
+<ul class="tags"><li><a href="/t/1">#Golang</a></li><li><a href="/t/2">html</a></li></ul>
+<a rel="tag" href="/t/3">Open Source</a>
+</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.Equal(t, []string{"go", "web scraping", "readability", "parsing", "html", "crawler", "golang", "open source"}, tags(doc, nil))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<html><head><script type="application/ld+json">{invalid</script></head></html>`))
+	assert.Empty(t, tags(doc, nil))
+}
+
+func TestTagsScope(t *testing.T) {
+	html := `<html><body>
+<div class="tags"><a href="/t/1">Politics</a><a href="/t/2">Sports</a></div>
+<article><div class="content">
+<p>A long enough paragraph of the article, which is written as sentences to be extracted.</p>
+<p>Another long enough paragraph of the article, which is written as sentences as well.</p>
+</div><footer><ul class="post-tags"><li><a href="/t/3">Go</a></li></ul></footer></article>
+<aside><ul class="tags"><li><a href="/t/4">Weather</a></li></ul></aside>
+</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.Equal(t, []string{"go"}, tags(doc, doc.Find(".content").Get(0)))
+	assert.Equal(t, []string{"go"}, tags(doc, nil))
+
+	c, err := ExtractFromDocument(doc, "", NewOption())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"go"}, c.Tags)
+
+	// the page is used if the article has no tag lists, except <aside> and <nav>
+	doc.Find("footer").Remove()
+	assert.Equal(t, []string{"politics", "sports"}, tags(doc, doc.Find(".content").Get(0)))
+}
@@ -0,0 +1,220 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// regexChunkSize is the approximate size of chunks for replaceAllChunked.
+// Small inputs let the regexp package use its faster backtracking matcher,
+// and bound the work of a single match on huge single-line (minified) documents.
+const regexChunkSize = 8 * 1024
+
+// plainText returns the text of s in a single pass over its nodes, separating elements
+// with a space, collapsing whitespaces into a single space and trimming both ends.
+// Comments are dropped. Entities are already decoded in text nodes by the HTML parser.
+func plainText(s *goquery.Selection) string {
+	tb := getTextBuilder()
+	defer putTextBuilder(tb)
+	for _, n := range s.Nodes {
+		tb.writeNode(n)
+	}
+	return string(tb.buf)
+}
+
+// decodeEntities decodes HTML entities (like "&amp;", "&#8217;" and "&nbsp;") left in s,
+// which is a value not decoded by the HTML parser, like a string of JSON-LD,
+// or encoded twice by the page, like <meta content="Rock &amp;amp; Roll">.
+func decodeEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	return html.UnescapeString(s)
+}
+
+// descriptionText returns the description of c in plain text,
+// joining c.Paragraphs if they are known instead of parsing the description.
+func descriptionText(c *Content, opt *Option) string {
+	if opt.DescriptionAsPlainText {
+		return c.Description
+	}
+	if len(c.Paragraphs) > 0 {
+		return strings.Join(c.Paragraphs, " ")
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(c.Description))
+	if err != nil {
+		return c.Description
+	}
+	return plainText(doc.Selection)
+}
+
+// graphemes splits s into user-perceived characters, so that a base character is never separated
+// from its combining marks (such as Arabic harakat and Hebrew niqqud), variation selectors
+// and characters joined by a zero width joiner.
+func graphemes(s string) []string {
+	var gs []string
+	start := 0
+	joined := false
+	for i, r := range s {
+		if i > start && !joined && !isGraphemeExtend(r) {
+			gs = append(gs, s[start:i])
+			start = i
+		}
+		joined = r == '\u200d'
+	}
+	if start < len(s) {
+		gs = append(gs, s[start:])
+	}
+	return gs
+}
+
+// isGraphemeExtend returns true if r extends the preceding character.
+func isGraphemeExtend(r rune) bool {
+	return r == '\u200d' || unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector)
+}
+
+// paragraphBlocks are elements whose boundaries separate paragraphs.
+var paragraphBlocks = map[string]bool{
+	"p": true, "div": true, "li": true, "pre": true, "blockquote": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "article": true, "figure": true, "figcaption": true,
+}
+
+// paragraphs returns the plain text of each block of s, such as <p> and <div>,
+// with the text of nested blocks separated from the text of their parents.
+// Empty paragraphs are dropped.
+func paragraphs(s *goquery.Selection) []string {
+	var ps []string
+	tb := getTextBuilder()
+	defer putTextBuilder(tb)
+	flush := func() {
+		if len(tb.buf) > 0 {
+			ps = append(ps, string(tb.buf))
+		}
+		tb.reset()
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			tb.writeString(n.Data)
+		case html.ElementNode, html.DocumentNode:
+			block := n.Type == html.ElementNode && paragraphBlocks[n.Data]
+			if block {
+				flush()
+			}
+			tb.space = true
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+			tb.space = true
+			if block {
+				flush()
+			}
+		}
+	}
+	for _, n := range s.Nodes {
+		walk(n)
+	}
+	flush()
+	return ps
+}
+
+// textBuilder builds whitespace-collapsed, trimmed text.
+type textBuilder struct {
+	buf   []byte
+	space bool
+}
+
+func (tb *textBuilder) reset() {
+	tb.buf = tb.buf[:0]
+	tb.space = false
+}
+
+func (tb *textBuilder) writeNode(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		tb.writeString(n.Data)
+	case html.ElementNode:
+		tb.space = true
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			tb.writeNode(c)
+		}
+		tb.space = true
+	case html.DocumentNode:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			tb.writeNode(c)
+		}
+	}
+}
+
+func (tb *textBuilder) writeString(s string) {
+	for len(s) > 0 {
+		i := 0
+		for i < len(s) && isSpaceByte(s[i]) {
+			i++
+		}
+		if i > 0 {
+			tb.space = true
+			s = s[i:]
+			continue
+		}
+		for i < len(s) && !isSpaceByte(s[i]) {
+			i++
+		}
+		if tb.space && len(tb.buf) > 0 {
+			tb.buf = append(tb.buf, ' ')
+		}
+		tb.space = false
+		tb.buf = append(tb.buf, s[:i]...)
+		s = s[i:]
+	}
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// replaceAllChunked acts same as re.ReplaceAllString(s, repl), except that s is processed
+// in chunks of about regexChunkSize bytes, each ending right after a '>'.
+//
+// It must be used only with patterns whose matches never contain '>' except as the last byte,
+// such as whitespaces, so that no match is split across chunks.
+func replaceAllChunked(re *regexp.Regexp, s, repl string) string {
+	return replaceChunks(s, func(chunk string) string {
+		return re.ReplaceAllString(chunk, repl)
+	})
+}
+
+// replaceAllChunkedFunc acts same as re.ReplaceAllStringFunc(s, repl)
+// with the chunks and the restriction of replaceAllChunked.
+func replaceAllChunkedFunc(re *regexp.Regexp, s string, repl func(string) string) string {
+	return replaceChunks(s, func(chunk string) string {
+		return re.ReplaceAllStringFunc(chunk, repl)
+	})
+}
+
+func replaceChunks(s string, replace func(chunk string) string) string {
+	if len(s) <= regexChunkSize {
+		return replace(s)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		end := len(s)
+		if len(s) > regexChunkSize {
+			if i := strings.IndexByte(s[regexChunkSize:], '>'); i >= 0 {
+				end = regexChunkSize + i + 1
+			}
+		}
+		b.WriteString(replace(s[:end]))
+		s = s[end:]
+	}
+	return b.String()
+}
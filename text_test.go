@@ -0,0 +1,78 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func minifiedHTML(n int) string {
+	var b strings.Builder
+	b.WriteString("<div>")
+	for i := 0; i < n; i++ {
+		b.WriteString(`<p class="text">Lorem ipsum, dolor   sit amet.</p>  <br/>	<span>consectetur</span>`)
+	}
+	b.WriteString("</div>")
+	return b.String()
+}
+
+func TestReplaceAllChunked(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"<p>short</p>",
+		minifiedHTML(1000),
+		strings.Repeat("a", regexChunkSize*3),
+		strings.Repeat("<b> \n ", regexChunkSize),
+		"<" + strings.Repeat("a", regexChunkSize*2) + ">" + strings.Repeat(" <i>x</i> ", regexChunkSize),
+	} {
+		assert.Equal(t, patterns.Trimmable.ReplaceAllString(s, " "), replaceAllChunked(patterns.Trimmable, s, " "))
+		assert.Equal(t, patterns.Newlines.ReplaceAllString(s, "\n"), replaceAllChunked(patterns.Newlines, s, "\n"))
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader("<p>Hello,\n<b>world</b>!<!-- comment --><br>AT&amp;T</p>"))
+	assert.Equal(t, "Hello, world ! AT&T", plainText(doc.Selection))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader("<div>\n\t<p> 안녕  하세요 </p>\f\r\n</div>"))
+	assert.Equal(t, "안녕 하세요", plainText(doc.Selection))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(""))
+	assert.Equal(t, "", plainText(doc.Selection))
+}
+
+func BenchmarkPlainText(b *testing.B) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plainText(doc.Selection)
+	}
+}
+
+func BenchmarkPlainTextMinified(b *testing.B) {
+	s := minifiedHTML(50000)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(s))
+	b.SetBytes(int64(len(s)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plainText(doc.Selection)
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	// Arabic letters with harakat, Hebrew letters with niqqud, and an emoji ZWJ sequence
+	assert.Equal(t, []string{"بِ", "سْ", "مِ"}, graphemes("بِسْمِ"))
+	assert.Equal(t, []string{"שָׁ", "ל", "וֹ", "ם"}, graphemes("שָׁלוֹם"))
+	assert.Equal(t, []string{"a", "👩‍💻", "b"}, graphemes("a👩‍💻b"))
+	assert.Equal(t, []string{"é"}, graphemes("é"))
+	assert.Nil(t, graphemes(""))
+}
+
+func TestPlainTextRTL(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader("<p>  مرحبا‏   <b>بِالعالم</b>\n\tשָׁלוֹם  </p>"))
+	assert.Equal(t, "مرحبا‏ بِالعالم שָׁלוֹם", plainText(doc.Find("p")))
+}
@@ -0,0 +1,44 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// themeColor returns the content of <meta name="theme-color">.
+// If several are declared for color schemes with media queries, the one without media
+// or for the light scheme is preferred, then the first one.
+func themeColor(doc *goquery.Document) string {
+	first := ""
+	preferred := ""
+	doc.Find(`meta[name="theme-color"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		color := strings.TrimSpace(s.AttrOr("content", ""))
+		if color == "" {
+			return true
+		}
+		if first == "" {
+			first = color
+		}
+		media := strings.ToLower(s.AttrOr("media", ""))
+		if media == "" || strings.Contains(media, "light") {
+			preferred = color
+			return false
+		}
+		return true
+	})
+	return firstNonEmpty(preferred, first)
+}
+
+// tileColor returns the content of <meta name="msapplication-TileColor">.
+func tileColor(doc *goquery.Document) string {
+	color := ""
+	doc.Find("meta[name]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if !strings.EqualFold(s.AttrOr("name", ""), "msapplication-TileColor") {
+			return true
+		}
+		color = strings.TrimSpace(s.AttrOr("content", ""))
+		return color == ""
+	})
+	return color
+}
@@ -0,0 +1,30 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeColor(t *testing.T) {
+	tests := []struct {
+		head string
+		want string
+	}{
+		{``, ""},
+		{`<meta name="theme-color" content=" #4285f4 ">`, "#4285f4"},
+		{`<meta name="theme-color" media="(prefers-color-scheme: dark)" content="#000"><meta name="theme-color" media="(prefers-color-scheme: light)" content="#fff">`, "#fff"},
+		{`<meta name="theme-color" media="(prefers-color-scheme: dark)" content="#000">`, "#000"},
+	}
+	for _, tt := range tests {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>` + tt.head + `</head></html>`))
+		assert.Equal(t, tt.want, themeColor(doc), tt.head)
+	}
+}
+
+func TestTileColor(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><meta name="msapplication-tilecolor" content=""><meta name="msapplication-TileColor" content="#da532c"></head></html>`))
+	assert.Equal(t, "#da532c", tileColor(doc))
+}
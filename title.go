@@ -0,0 +1,263 @@
+package readability
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// titleSeparator matches separators between the headline, the site name and the section
+// in titles like "Article Headline | Site Name – Section".
+// Hyphens are separators only if surrounded by spaces, so that hyphenated words are kept.
+var titleSeparator = regexp.MustCompile(`\s+(?:\||–|—|-|::|»|·|/)\s+|\s*(?:\||::|»)\s*`)
+
+// cleanTitle returns title without the site name and the section.
+//
+// A part of title (or consecutive parts) equal to og:title, twitter:title or an <h1> is preferred.
+// Otherwise parts equal to og:site_name or application-name are removed,
+// and the part with the most words among the rest is returned.
+func cleanTitle(doc *goquery.Document, title string) string {
+	parts := titleSeparator.Split(title, -1)
+	if len(parts) < 2 {
+		return title
+	}
+
+	refs := map[string]bool{}
+	for _, s := range []string{
+		metaContent(doc, `meta[property="og:title"]`),
+		metaContent(doc, `meta[name="twitter:title"], meta[property="twitter:title"]`),
+	} {
+		refs[titleKey(s)] = true
+	}
+	doc.Find("h1").Each(func(_ int, s *goquery.Selection) {
+		refs[titleKey(s.Text())] = true
+	})
+	delete(refs, "")
+	delete(refs, titleKey(title))
+
+	// the longest run of consecutive parts matching a reference
+	for n := len(parts) - 1; n > 0; n-- {
+		for i := 0; i+n <= len(parts); i++ {
+			if refs[normalizeTitle(strings.Join(parts[i:i+n], " "))] {
+				return strings.TrimSpace(titleSubstring(title, parts[i], parts[i+n-1]))
+			}
+		}
+	}
+
+	siteNames := map[string]bool{
+		normalizeTitle(metaContent(doc, `meta[property="og:site_name"]`)): true,
+		normalizeTitle(metaContent(doc, `meta[name="application-name"]`)): true,
+	}
+	best := ""
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" || siteNames[normalizeTitle(p)] {
+			continue
+		}
+		if len(strings.Fields(p)) > len(strings.Fields(best)) {
+			best = p
+		}
+	}
+	if best == "" {
+		return title
+	}
+	return best
+}
+
+// titleSubstring returns the substring of title from the first part to the last part,
+// keeping the separators between them.
+func titleSubstring(title, first, last string) string {
+	start := strings.Index(title, first)
+	end := strings.LastIndex(title, last)
+	if start < 0 || end < start {
+		return first
+	}
+	return title[start : end+len(last)]
+}
+
+// metaContent returns the trimmed content attribute of the first element matching selector.
+func metaContent(doc *goquery.Document, selector string) string {
+	v, _ := doc.Find(selector).First().Attr("content")
+	return strings.TrimSpace(v)
+}
+
+// titleKey returns s without separators, normalized for comparison with parts of titles.
+func titleKey(s string) string {
+	return normalizeTitle(strings.Join(titleSeparator.Split(s, -1), " "))
+}
+
+// normalizeTitle lowercases s and collapses its whitespaces for comparison.
+func normalizeTitle(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// TitleSource is the source of Content.Title.
+type TitleSource string
+
+// Title sources of Content.TitleSource.
+const (
+	TitleSourceOpenGraph TitleSource = "og"
+	TitleSourceMetadata  TitleSource = "metadata"
+	TitleSourceTitle     TitleSource = "title"
+	TitleSourceH1        TitleSource = "h1"
+	TitleSourceHeading   TitleSource = "heading"
+
+	// TitleSourceURL is a title derived from the URL slug, like "Man bites dog" for
+	// "/news/2024/05/01/man-bites-dog-123456.html", or the host name if the URL has no slug.
+	// It is a low-confidence last resort used only if no other title is found,
+	// and Content.Warnings has WarningTitleFromURL.
+	TitleSourceURL TitleSource = "url"
+)
+
+// genericTitles are normalized titles which don't describe the page.
+var genericTitles = map[string]bool{
+	"home": true, "homepage": true, "home page": true, "index": true, "default": true,
+	"untitled": true, "untitled document": true, "page": true, "article": true,
+	"news": true, "blog": true, "welcome": true,
+}
+
+// documentTitle returns the text of <title> unless it is empty or generic,
+// otherwise the text of the first <h1>. If both are unavailable,
+// the generic (or empty) title is returned with an empty source.
+func documentTitle(doc *goquery.Document, reqURL string) (string, TitleSource) {
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	if !isGenericTitle(doc, title, reqURL) {
+		return title, TitleSourceTitle
+	}
+	if h1 := strings.TrimSpace(plainText(doc.Find("h1").First())); h1 != "" {
+		return h1, TitleSourceH1
+	}
+	return title, ""
+}
+
+// isGenericTitle returns true if title is empty, a generic word like "Home",
+// the site name or the host name of reqURL.
+func isGenericTitle(doc *goquery.Document, title, reqURL string) bool {
+	t := normalizeTitle(title)
+	if t == "" || genericTitles[t] {
+		return true
+	}
+	if t == normalizeTitle(metaContent(doc, `meta[property="og:site_name"]`)) ||
+		t == normalizeTitle(metaContent(doc, `meta[name="application-name"]`)) {
+		return true
+	}
+	if u, err := url.Parse(reqURL); err == nil && u.Hostname() != "" {
+		host := strings.ToLower(u.Hostname())
+		return t == host || t == strings.TrimPrefix(host, "www.")
+	}
+	return false
+}
+
+// headingLevels are the headings considered inside the best candidate, by importance.
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3}
+
+// bestHeading returns the text of the most important heading inside s:
+// the one with the highest level, then with the highest class weight, then the first one.
+func bestHeading(s *goquery.Selection, opt *Option) string {
+	best, bestLevel, bestWeight := "", 0, 0.0
+	s.Find("h1, h2, h3").Each(func(_ int, h *goquery.Selection) {
+		text := plainText(h)
+		if text == "" {
+			return
+		}
+		level, weight := headingLevels[goquery.NodeName(h)], classWeight(h, opt)
+		if best == "" || level < bestLevel || level == bestLevel && weight > bestWeight {
+			best, bestLevel, bestWeight = text, level, weight
+		}
+	})
+	return best
+}
+
+// slugSeparator splits URL slugs into words.
+var slugSeparator = regexp.MustCompile(`[-_+.~\s]+`)
+
+// genericSlugs are path segments which don't describe the page.
+var genericSlugs = map[string]bool{
+	"index": true, "default": true, "home": true, "article": true, "articles": true, "post": true,
+	"posts": true, "news": true, "story": true, "amp": true,
+}
+
+// slugTitle returns a title humanized from the last descriptive path segment of reqURL,
+// with dashes and underscores turned into spaces, file extensions, leading dates and
+// trailing IDs removed, and the first letter capitalized.
+// The host name without "www." is returned if no segment describes the page.
+func slugTitle(reqURL string) string {
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return ""
+	}
+	segs := strings.Split(u.EscapedPath(), "/")
+	for i := len(segs) - 1; i >= 0; i-- {
+		seg, err := url.PathUnescape(segs[i])
+		if err != nil {
+			continue
+		}
+		seg = strings.TrimSuffix(seg, path.Ext(seg))
+		if genericSlugs[strings.ToLower(seg)] {
+			continue
+		}
+		if words := slugWords(seg); len(words) > 0 {
+			title := strings.Join(words, " ")
+			r, n := utf8.DecodeRuneInString(title)
+			return string(unicode.ToUpper(r)) + title[n:]
+		}
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// slugWords returns the words of the path segment seg without a leading date
+// like "2024-05-01" and leading or trailing IDs like "123456" or "5f3a9c2e".
+// It returns nil if seg has no words.
+func slugWords(seg string) []string {
+	var words []string
+	for _, w := range slugSeparator.Split(seg, -1) {
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	if len(words) > 0 && isYear(words[0]) {
+		words = words[1:]
+		for i := 0; i < 2 && len(words) > 0 && len(words[0]) <= 2 && isDigits(words[0]); i++ {
+			words = words[1:]
+		}
+	}
+	for len(words) > 0 && isSlugID(words[0]) {
+		words = words[1:]
+	}
+	for len(words) > 0 && isSlugID(words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	for _, w := range words {
+		if strings.IndexFunc(w, unicode.IsLetter) >= 0 {
+			return words
+		}
+	}
+	return nil
+}
+
+// isSlugID returns true if w looks like an ID rather than a word:
+// a number of 4 or more digits, or 6 or more letters and digits mixed, like "a1b2c3".
+func isSlugID(w string) bool {
+	if isDigits(w) {
+		return len(w) >= 4
+	}
+	return len(w) >= 6 && strings.IndexFunc(w, unicode.IsDigit) >= 0 &&
+		strings.IndexFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) < 0
+}
+
+// isYear returns true if w is a year from 1900 to 2099.
+func isYear(w string) bool {
+	y, err := strconv.Atoi(w)
+	return err == nil && len(w) == 4 && y >= 1900 && y < 2100
+}
+
+// isDigits returns true if w is a non-empty string of ASCII digits.
+func isDigits(w string) bool {
+	return w != "" && strings.Trim(w, "0123456789") == ""
+}
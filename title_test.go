@@ -0,0 +1,112 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		head  string
+		body  string
+		title string
+		want  string
+	}{
+		{``, ``, "Article Headline", "Article Headline"},
+		{``, ``, "A Long Article Headline | Site Name – Section", "A Long Article Headline"},
+		{``, ``, "Site :: A Long Article Headline", "A Long Article Headline"},
+		{``, ``, "A well-known fact - Blog", "A well-known fact"},
+		{``, `<h1>Short - Title</h1>`, "Short - Title | Very Long Site Name Here", "Short - Title"},
+		{`<meta property="og:title" content="Go 2">`, ``, "Go 2 | The Go Programming Language Blog", "Go 2"},
+		{`<meta property="og:site_name" content="The Daily Example Newspaper">`, ``, "Rain | The Daily Example Newspaper", "Rain"},
+	}
+	for _, tt := range tests {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>` + tt.head + `</head><body>` + tt.body + `</body></html>`))
+		assert.Equal(t, tt.want, cleanTitle(doc, tt.title), tt.title)
+	}
+}
+
+func TestExtractFromDocumentCleanTitle(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Breaking News | Example Site</title></head><body><h1>Breaking News</h1></body></html>`))
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Breaking News | Example Site", c.Title)
+	assert.Equal(t, "", c.RawTitle)
+
+	opt.CleanTitle = true
+	c, err = ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Breaking News", c.Title)
+	assert.Equal(t, "Breaking News | Example Site", c.RawTitle)
+}
+
+func TestDocumentTitle(t *testing.T) {
+	tests := []struct {
+		html       string
+		want       string
+		wantSource TitleSource
+	}{
+		{`<title>Headline</title><h1>Other</h1>`, "Headline", TitleSourceTitle},
+		{`<title>Home</title><h1>Headline</h1>`, "Headline", TitleSourceH1},
+		{`<title></title><h1> Headline </h1>`, "Headline", TitleSourceH1},
+		{`<title>www.example.com</title><h1>Headline</h1>`, "Headline", TitleSourceH1},
+		{`<meta property="og:site_name" content="Example"><title>Example</title><h1>Headline</h1>`, "Headline", TitleSourceH1},
+		{`<title>Home</title>`, "Home", ""},
+	}
+	for _, tt := range tests {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		title, source := documentTitle(doc, "http://www.example.com/a")
+		assert.Equal(t, tt.want, title, tt.html)
+		assert.Equal(t, tt.wantSource, source, tt.html)
+	}
+}
+
+func TestBestHeading(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div><h3>Minor</h3><h2 class="sidebar">Related</h2><h2 class="article-title">Headline</h2></div>`))
+	assert.Equal(t, "Headline", bestHeading(doc.Find("div"), NewOption()))
+}
+
+func TestExtractFromDocumentTitleFallback(t *testing.T) {
+	p := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor. ", 4)
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Untitled</title></head><body><div class="content"><h2>Headline</h2><p>` + p + `</p><p>` + p + `</p></div></body></html>`))
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Headline", c.Title)
+	assert.Equal(t, TitleSourceHeading, c.TitleSource)
+}
+
+func TestSlugTitle(t *testing.T) {
+	for _, tt := range []struct{ url, want string }{
+		{"https://example.com/news/2024/05/01/man-bites-dog-123456.html", "Man bites dog"},
+		{"https://example.com/2024-05-01-my_first_post/", "My first post"},
+		{"https://example.com/blog/top-10-tips?utm_source=x", "Top 10 tips"},
+		{"https://example.com/p/5f3a9c2e7b/how%20it%20works", "How it works"},
+		{"https://example.com/article/98765432/index.php", "example.com"},
+		{"https://www.example.com/", "example.com"},
+		{"", ""},
+	} {
+		assert.Equal(t, tt.want, slugTitle(tt.url), tt.url)
+	}
+}
+
+func TestExtractFromDocumentSlugTitle(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><p>Short.</p></body></html>`))
+	opt := NewOption()
+	opt.ImageRequestTimeout = 10
+	c, err := ExtractFromDocument(doc, "https://example.com/world/rescue-team-reaches-trapped-miners-42137", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Rescue team reaches trapped miners", c.Title)
+	assert.Equal(t, TitleSourceURL, c.TitleSource)
+	codes := []WarningCode{}
+	for _, w := range c.Warnings {
+		codes = append(codes, w.Code)
+	}
+	assert.Contains(t, codes, WarningTitleFromURL)
+}
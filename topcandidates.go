@@ -0,0 +1,35 @@
+package readability
+
+// ArticleCandidate is a description candidate of Content.Candidates, for pages where
+// the best candidate may be wrong, such as pages of several articles.
+type ArticleCandidate struct {
+	// Node is a short description of the candidate node like "div#id.class".
+	Node string `json:"node"`
+
+	// Score is the score given by readability rules.
+	Score float64 `json:"score"`
+
+	// LinkDensity is the ratio of link text length to inner text length.
+	LinkDensity float64 `json:"linkDensity"`
+
+	// Text is the inner text of the candidate in plain text.
+	Text string `json:"text"`
+}
+
+// topCandidates returns the first n candidates of c in the order the best candidate is chosen,
+// that is, by score or by the order of Option.Reranker.
+func topCandidates(c *candidates, n int) []ArticleCandidate {
+	if c == nil || n <= 0 {
+		return nil
+	}
+	if n > len(c.List) {
+		n = len(c.List)
+	}
+	top := make([]ArticleCandidate, n)
+	for i, cand := range c.List[:n] {
+		s := cand.Node.Selection
+		top[i] = ArticleCandidate{Node: cand.Node.String(), Score: cand.Score,
+			LinkDensity: linkDensity(s), Text: plainText(s)}
+	}
+	return top
+}
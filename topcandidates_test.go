@@ -0,0 +1,47 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandidates(t *testing.T) {
+	extract := func(count int, reranker Reranker) *Content {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+		opt := benchOption()
+		opt.RetryLength = 0
+		opt.CandidateCount = count
+		opt.Reranker = reranker
+		c, err := ExtractFromDocument(doc, "http://example.com/", opt)
+		assert.Nil(t, err)
+		return c
+	}
+
+	assert.Nil(t, extract(0, nil).Candidates)
+
+	cands := extract(2, nil).Candidates
+	if assert.Equal(t, 2, len(cands)) {
+		assert.Equal(t, "div#content.article", cands[0].Node)
+		assert.True(t, cands[0].Score >= cands[1].Score)
+		assert.True(t, strings.HasPrefix(cands[0].Text, "Lorem ipsum dolor sit amet"))
+		assert.NotContains(t, cands[0].Text, "\n")
+	}
+	all := extract(100, nil).Candidates
+	assert.True(t, len(all) >= 2 && len(all) < 100, "%v candidates", len(all))
+
+	// the order of the reranker is kept
+	reversed := RerankerFunc(func(cs []CandidateFeatures) ([]float64, error) {
+		scores := make([]float64, len(cs))
+		for i := range cs {
+			scores[i] = float64(i)
+		}
+		return scores, nil
+	})
+	reranked := extract(2, reversed).Candidates
+	if assert.Equal(t, 2, len(reranked)) {
+		assert.NotEqual(t, "div#content.article", reranked[0].Node)
+	}
+}
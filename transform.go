@@ -0,0 +1,60 @@
+package readability
+
+import "fmt"
+
+// Transformer transforms the text fields of a result before ExtractFromDocument returns it,
+// such as machine translation, profanity masking or summary injection, so that pipelines
+// can keep all content processing inside one extraction.
+//
+// Transform receives each text of field and returns the text replacing it:
+// the title (FieldTitle), the description (FieldDescription; HTML unless
+// Option.DescriptionAsPlainText is set), each of Content.Paragraphs (FieldParagraphs)
+// and the text of each of Content.Outline (FieldOutline).
+// Empty texts are not passed, and Content.ArticleNode is not transformed.
+// If Transform returns an error, the extraction fails with it.
+type Transformer interface {
+	Transform(field Field, text string) (string, error)
+}
+
+// TransformerFunc is an adapter to allow the use of ordinary functions as a Transformer.
+type TransformerFunc func(field Field, text string) (string, error)
+
+// Transform calls f(field, text).
+func (f TransformerFunc) Transform(field Field, text string) (string, error) {
+	return f(field, text)
+}
+
+// transform applies opt.Transformers to the text fields of c in order.
+func transform(c *Content, opt *Option) error {
+	for _, t := range opt.Transformers {
+		apply := func(field Field, text *string) error {
+			if *text == "" {
+				return nil
+			}
+			v, err := t.Transform(field, *text)
+			if err != nil {
+				return fmt.Errorf("transform %v: %w", field, err)
+			}
+			*text = v
+			return nil
+		}
+
+		if err := apply(FieldTitle, &c.Title); err != nil {
+			return err
+		}
+		if err := apply(FieldDescription, &c.Description); err != nil {
+			return err
+		}
+		for i := range c.Paragraphs {
+			if err := apply(FieldParagraphs, &c.Paragraphs[i]); err != nil {
+				return err
+			}
+		}
+		for i := range c.Outline {
+			if err := apply(FieldOutline, &c.Outline[i].Text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
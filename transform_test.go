@@ -0,0 +1,55 @@
+package readability
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformers(t *testing.T) {
+	p := strings.Repeat("darn lorem ipsum dolor sit amet, ", 10)
+	page := `<html dir="ltr"><head><title>Darn title</title></head><body><div class="article">` +
+		`<h2>Darn heading</h2><p>` + p + `</p><p>` + p + `</p></div></body></html>`
+
+	var fields []Field
+	mask := TransformerFunc(func(field Field, text string) (string, error) {
+		fields = append(fields, field)
+		return strings.Replace(strings.Replace(text, "darn", "****", -1), "Darn", "****", -1), nil
+	})
+	rtl := TransformerFunc(func(field Field, text string) (string, error) {
+		if field == FieldTitle {
+			return "عنوان " + text, nil
+		}
+		return strings.Replace(text, "lorem ipsum dolor sit amet", "هذا نص عربي طويل", -1), nil
+	})
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+	opt := benchOption()
+	opt.Transformers = []Transformer{mask, rtl}
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, err)
+	assert.Equal(t, "عنوان **** title", c.Title)
+	assert.NotContains(t, c.Description, "darn")
+	assert.Contains(t, c.Description, "**** هذا نص عربي طويل,")
+	assert.Equal(t, "****", c.Paragraphs[0][:4])
+	assert.Equal(t, "**** heading", c.Outline[0].Text)
+	assert.Equal(t, []Field{FieldTitle, FieldDescription, FieldParagraphs, FieldParagraphs, FieldParagraphs, FieldOutline}, fields)
+	// the direction is detected from the transformed text, ignoring the dir attribute
+	assert.Equal(t, TextDirectionRTL, c.TextDirection)
+}
+
+func TestTransformerError(t *testing.T) {
+	errTranslate := errors.New("translation failed")
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Title</title></head><body><p>Text</p></body></html>`))
+	opt := benchOption()
+	opt.Transformers = []Transformer{TransformerFunc(func(field Field, text string) (string, error) {
+		return "", errTranslate
+	})}
+	c, err := ExtractFromDocument(doc, "http://example.com", opt)
+	assert.Nil(t, c)
+	assert.True(t, errors.Is(err, errTranslate))
+	assert.Contains(t, err.Error(), "transform title")
+}
@@ -0,0 +1,181 @@
+package readability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/philipjkim/fastimage"
+)
+
+var (
+	// youTubeIDPattern matches YouTube video IDs.
+	youTubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+	// vimeoIDPattern matches Vimeo video IDs.
+	vimeoIDPattern = regexp.MustCompile(`^[0-9]+$`)
+
+	// vimeoOEmbedEndpoint is the oEmbed endpoint of Vimeo, replaced in tests.
+	vimeoOEmbedEndpoint = "https://vimeo.com/api/oembed.json"
+)
+
+// youTubeThumbnailSize is the size of the hqdefault thumbnail of YouTube videos.
+var youTubeThumbnailSize = fastimage.ImageSize{Width: 480, Height: 360}
+
+// videoThumbnails returns the thumbnails of the videos embedded in doc by YouTube and Vimeo players
+// (<iframe> or <embed>), up to opt.MaxImageCount in document order.
+// YouTube thumbnails are synthesized from the video IDs. Vimeo thumbnails are looked up with
+// the oEmbed API by the image client, and skipped if opt.SkipImageProbing is set.
+func videoThumbnails(doc *goquery.Document, reqURL string, opt *Option) []Image {
+	var players []string
+	seen := map[string]bool{}
+	doc.Find("iframe, embed").Each(func(_ int, s *goquery.Selection) {
+		src := strings.TrimSpace(s.AttrOr("src", ""))
+		if src == "" || src == "about:blank" {
+			src = strings.TrimSpace(s.AttrOr("data-src", ""))
+		}
+		u, err := absPath(src, reqURL)
+		if err != nil || seen[u] {
+			return
+		}
+		seen[u] = true
+		players = append(players, u)
+	})
+
+	thumbs := make([]*Image, len(players))
+	var wg sync.WaitGroup
+	for i, p := range players {
+		provider, id := videoID(p)
+		switch {
+		case provider == "youtube":
+			size := youTubeThumbnailSize
+			thumbs[i] = &Image{URL: "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg", Size: &size, Source: ImageSourceVideoEmbed}
+		case provider == "vimeo" && opt.DryRun && !opt.SkipImageProbing:
+			opt.plan.add(RequestOEmbed, vimeoOEmbedURL(id))
+		case provider == "vimeo" && !opt.SkipImageProbing:
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				img, err := vimeoThumbnail(id, opt)
+				if err != nil {
+					logger.Warnf("vimeoThumbnail error: %v, id: %v", err, id)
+					return
+				}
+				thumbs[i] = img
+			}(i, id)
+		}
+	}
+	wg.Wait()
+
+	imgs := []Image{}
+	for _, img := range thumbs {
+		if img != nil && len(imgs) < opt.MaxImageCount {
+			imgs = append(imgs, *img)
+		}
+	}
+	return imgs
+}
+
+// videoID returns the provider ("youtube" or "vimeo") and the video ID of the player URL,
+// or empty strings if it is not a supported player.
+func videoID(player string) (provider, id string) {
+	u, err := url.Parse(player)
+	if err != nil {
+		return "", ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch host {
+	case "youtube.com", "m.youtube.com", "youtube-nocookie.com":
+		switch {
+		case len(segs) == 2 && (segs[0] == "embed" || segs[0] == "v"):
+			id = segs[1]
+		case len(segs) == 1 && segs[0] == "watch":
+			id = u.Query().Get("v")
+		}
+		// "videoseries" embeds a playlist
+		if youTubeIDPattern.MatchString(id) && id != "videoseries" {
+			return "youtube", id
+		}
+	case "youtu.be":
+		if len(segs) == 1 && youTubeIDPattern.MatchString(segs[0]) {
+			return "youtube", segs[0]
+		}
+	case "player.vimeo.com":
+		if len(segs) == 2 && segs[0] == "video" && vimeoIDPattern.MatchString(segs[1]) {
+			return "vimeo", segs[1]
+		}
+	case "vimeo.com":
+		if len(segs) == 1 && vimeoIDPattern.MatchString(segs[0]) {
+			return "vimeo", segs[0]
+		}
+	}
+	return "", ""
+}
+
+// vimeoThumbnail looks up the thumbnail of the Vimeo video id with the oEmbed API,
+// within opt.ImageRequestTimeout.
+func vimeoThumbnail(id string, opt *Option) (*Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(opt.ImageRequestTimeout)*time.Millisecond)
+	defer cancel()
+
+	endpoint := vimeoOEmbedURL(id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := imageClient(opt).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, &HTTPError{URL: endpoint, StatusCode: resp.StatusCode}
+	}
+
+	var oembed struct {
+		ThumbnailURL    string `json:"thumbnail_url"`
+		ThumbnailWidth  uint32 `json:"thumbnail_width"`
+		ThumbnailHeight uint32 `json:"thumbnail_height"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&oembed); err != nil {
+		return nil, err
+	}
+	if !isValidURLStr(oembed.ThumbnailURL) {
+		return nil, fmt.Errorf("no thumbnail_url in oEmbed response: %v", endpoint)
+	}
+	return &Image{
+		URL:    oembed.ThumbnailURL,
+		Size:   &fastimage.ImageSize{Width: oembed.ThumbnailWidth, Height: oembed.ThumbnailHeight},
+		Source: ImageSourceVideoEmbed,
+	}, nil
+}
+
+// vimeoOEmbedURL returns the URL of the oEmbed API for the Vimeo video id.
+func vimeoOEmbedURL(id string) string {
+	return vimeoOEmbedEndpoint + "?url=" + url.QueryEscape("https://vimeo.com/"+id)
+}
+
+// appendVideoThumbnails appends the thumbnails not in imgs, up to opt.MaxImageCount images.
+func appendVideoThumbnails(imgs, thumbs []Image, opt *Option) []Image {
+	for _, t := range thumbs {
+		if len(imgs) >= opt.MaxImageCount {
+			break
+		}
+		dup := false
+		for _, img := range imgs {
+			dup = dup || img.URL == t.URL
+		}
+		if !dup {
+			imgs = append(imgs, t)
+		}
+	}
+	return imgs
+}
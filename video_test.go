@@ -0,0 +1,95 @@
+package readability
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVideoID(t *testing.T) {
+	for _, tc := range []struct {
+		player, provider, id string
+	}{
+		{"https://www.youtube.com/embed/dQw4w9WgXcQ?rel=0", "youtube", "dQw4w9WgXcQ"},
+		{"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", "youtube", "dQw4w9WgXcQ"},
+		{"http://youtube.com/v/dQw4w9WgXcQ", "youtube", "dQw4w9WgXcQ"},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ&t=10", "youtube", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "youtube", "dQw4w9WgXcQ"},
+		{"https://player.vimeo.com/video/76979871?h=8272103f6e", "vimeo", "76979871"},
+		{"https://vimeo.com/76979871", "vimeo", "76979871"},
+		{"https://www.youtube.com/embed/videoseries?list=PL123", "", ""},
+		{"https://vimeo.com/channels/staffpicks", "", ""},
+		{"https://example.com/embed/dQw4w9WgXcQ", "", ""},
+	} {
+		provider, id := videoID(tc.player)
+		assert.Equal(t, tc.provider, provider, tc.player)
+		assert.Equal(t, tc.id, id, tc.player)
+	}
+}
+
+func TestVideoThumbnails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("url") != "https://vimeo.com/76979871" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"type":"video","thumbnail_url":"https://i.vimeocdn.com/video/452001751-640.jpg","thumbnail_width":640,"thumbnail_height":360}`)
+	}))
+	defer ts.Close()
+	defer func(endpoint string) { vimeoOEmbedEndpoint = endpoint }(vimeoOEmbedEndpoint)
+	vimeoOEmbedEndpoint = ts.URL
+
+	html := `<body><p>Watch this.</p>
+<iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ"></iframe>
+<iframe src="about:blank" data-src="//player.vimeo.com/video/76979871"></iframe>
+<iframe src="https://player.vimeo.com/video/1"></iframe>
+<iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ"></iframe>
+<iframe src="https://maps.example.com/embed"></iframe></body>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	opt := NewOption()
+	imgs := videoThumbnails(doc, "https://example.com/post", opt)
+	if assert.Len(t, imgs, 2) {
+		assert.Equal(t, "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg", imgs[0].URL)
+		assert.Equal(t, uint32(480), imgs[0].Size.Width)
+		assert.Equal(t, "https://i.vimeocdn.com/video/452001751-640.jpg", imgs[1].URL)
+		assert.Equal(t, uint32(360), imgs[1].Size.Height)
+		assert.Equal(t, ImageSourceVideoEmbed, imgs[1].Source)
+	}
+
+	// Vimeo thumbnails are never requested if probing is skipped
+	opt.SkipImageProbing = true
+	imgs = videoThumbnails(doc, "https://example.com/post", opt)
+	assert.Len(t, imgs, 1)
+
+	opt.MaxImageCount = 0
+	assert.Empty(t, videoThumbnails(doc, "https://example.com/post", opt))
+}
+
+func TestExtractFromDocumentVideoThumbnails(t *testing.T) {
+	html := `<html><head><title>A video</title></head><body><article>
+<p>The video of the keynote, which is long enough to be the article of this page.</p>
+<iframe width="560" height="315" src="https://www.youtube.com/embed/dQw4w9WgXcQ"></iframe>
+</article></body></html>`
+	opt := benchOption()
+	opt.SkipImageProbing = true
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err := ExtractFromDocument(doc, "https://example.com/keynote", opt)
+	assert.Nil(t, err)
+	assert.Empty(t, c.Images)
+	assert.Equal(t, ImageSourceFavicon, c.PrimaryImage.Source)
+
+	opt.VideoThumbnails = true
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	c, err = ExtractFromDocument(doc, "https://example.com/keynote", opt)
+	assert.Nil(t, err)
+	if assert.Len(t, c.Images, 1) {
+		assert.Equal(t, "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg", c.Images[0].URL)
+	}
+	assert.Equal(t, ImageSourceVideoEmbed, c.PrimaryImage.Source)
+}
@@ -0,0 +1,188 @@
+package readability
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// WarningCode is a machine-readable code of a Warning.
+type WarningCode string
+
+// Warning codes.
+const (
+	// WarningDescriptionEmpty means no description is extracted.
+	WarningDescriptionEmpty WarningCode = "DESCRIPTION_EMPTY"
+
+	// WarningDescriptionEqualsTitle means the description is the same as the title or a part of it,
+	// which usually means the article body was not found. See TitleDescriptionRedundant.
+	WarningDescriptionEqualsTitle WarningCode = "DESCRIPTION_EQUALS_TITLE"
+
+	// WarningDescriptionIsCookieNotice means the description looks like a cookie consent notice.
+	WarningDescriptionIsCookieNotice WarningCode = "DESCRIPTION_IS_COOKIE_NOTICE"
+
+	// WarningDescriptionIsNavigation means the description looks like a navigation menu or breadcrumbs.
+	WarningDescriptionIsNavigation WarningCode = "DESCRIPTION_IS_NAVIGATION"
+
+	// WarningTitleFromURL means no title is found in the page, and the title is derived from the URL.
+	WarningTitleFromURL WarningCode = "TITLE_FROM_URL"
+
+	// WarningImageIsLogoSuspect means an image looks like a logo, an icon or a banner.
+	WarningImageIsLogoSuspect WarningCode = "IMAGE_IS_LOGO_SUSPECT"
+
+	// WarningHTMLTruncated means the page was larger than Option.MaxHTMLSize and is truncated,
+	// so the end of the article may be missing.
+	WarningHTMLTruncated WarningCode = "HTML_TRUNCATED"
+)
+
+// Warning describes why an extraction is likely bad.
+type Warning struct {
+	Code    WarningCode `json:"code"`
+	Message string      `json:"message"`
+}
+
+const (
+	// navigationMinSegments is the minimum number of segments split by navigationSeparators
+	// for a description to be considered as navigation.
+	navigationMinSegments = 4
+
+	// navigationMaxSegmentLength is the maximum average length of the segments
+	// for a description to be considered as navigation.
+	navigationMaxSegmentLength = 20
+
+	// navigationMinWordRatio is the minimum ratio of navigationWords to all words
+	// for a description to be considered as navigation.
+	navigationMinWordRatio = 0.3
+
+	// cookieNoticeMaxLength is the maximum length (runes) of a text considered as a cookie notice
+	// by a single phrase of cookieNoticePhrases.
+	cookieNoticeMaxLength = 500
+
+	// logoMaxAspectRatio is the maximum ratio of the longer side to the shorter side
+	// of an image not considered as a banner.
+	logoMaxAspectRatio = 4
+)
+
+var navigationSeparators = []string{"|", "»", "›", "·", "•", ">"}
+
+var navigationWords = map[string]bool{
+	"home": true, "menu": true, "login": true, "log": true, "sign": true, "in": true, "up": true,
+	"subscribe": true, "search": true, "contact": true, "about": true, "us": true, "privacy": true,
+	"policy": true, "terms": true, "sitemap": true, "next": true, "previous": true, "prev": true,
+	"more": true, "skip": true, "to": true, "content": true, "main": true, "navigation": true,
+}
+
+// cookieNoticePhrases matches phrases of cookie consent notices, like "We use cookies" or "Accept all".
+var cookieNoticePhrases = regexp.MustCompile(`(?i)\b(we|this (web)?site|our (web)?site|our partners)( and our partners)? (use|uses|may use|store|stores) cookies|accept (all )?cookies|\baccept all\b|\breject all\b|cookie (policy|settings|preferences|consent|notice|banner)|by (continuing|clicking|using)[^.]{0,80}(cookies|you agree|you consent)|manage (your )?(cookie |privacy )?(preferences|consent|choices|options)`)
+
+var logoURLWords = []string{"logo", "icon", "sprite", "avatar", "badge", "banner"}
+
+// warnings returns the warnings for suspicious values of c extracted with opt.
+func warnings(c *Content, opt *Option) []Warning {
+	var ws []Warning
+	warn := func(code WarningCode, format string, args ...interface{}) {
+		ws = append(ws, Warning{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.TitleSource == TitleSourceURL {
+		warn(WarningTitleFromURL, "title is derived from the URL: %q", c.Title)
+	}
+
+	desc := descriptionText(c, opt)
+	switch {
+	case strings.TrimSpace(desc) == "":
+		warn(WarningDescriptionEmpty, "no description is extracted")
+	case isRedundant(c.Title, desc):
+		warn(WarningDescriptionEqualsTitle, "description is the same as the title: %q", c.Title)
+	case LooksLikeCookieNotice(desc):
+		warn(WarningDescriptionIsCookieNotice, "description looks like a cookie notice: %.80q", desc)
+	case IsLikelyNavigationText(desc):
+		warn(WarningDescriptionIsNavigation, "description looks like navigation: %.80q", desc)
+	}
+
+	imgs := c.Images
+	if c.PrimaryImage != nil {
+		imgs = append([]Image{*c.PrimaryImage}, imgs...)
+	}
+	seen := map[string]bool{}
+	for _, img := range imgs {
+		if seen[img.URL] {
+			continue
+		}
+		seen[img.URL] = true
+		if reason := logoReason(img); reason != "" {
+			warn(WarningImageIsLogoSuspect, "image %.200v %v", img.URL, reason)
+		}
+	}
+	return ws
+}
+
+// normalizeForComparison returns s lowercased, with whitespaces collapsed and punctuations trimmed.
+func normalizeForComparison(s string) string {
+	return strings.Trim(strings.ToLower(strings.Join(strings.Fields(s), " ")), ".,:;!?-–—|\"' ")
+}
+
+// TitleDescriptionRedundant returns true if the description of c adds nothing to the title:
+// ignoring cases, whitespaces and punctuations, it is the same as the title or a part of it,
+// like "Breaking News" of the title "Breaking News | Example Times". An empty description is not redundant.
+func TitleDescriptionRedundant(c *Content) bool {
+	return isRedundant(c.Title, strings.Join(descriptionParagraphs(c.Description), " "))
+}
+
+// isRedundant returns true if desc, a description in plain text, is redundant with title.
+func isRedundant(title, desc string) bool {
+	d := normalizeForComparison(desc)
+	return d != "" && strings.Contains(normalizeForComparison(title), d)
+}
+
+// LooksLikeCookieNotice returns true if s, a text like a description, looks like a cookie consent
+// notice, such as "We use cookies to improve your experience. Accept all / Manage preferences":
+// it has two phrases of such notices, or one if s is short.
+func LooksLikeCookieNotice(s string) bool {
+	n := len(cookieNoticePhrases.FindAllStringIndex(s, 2))
+	return n >= 2 || n == 1 && utf8.RuneCountInString(s) <= cookieNoticeMaxLength
+}
+
+// IsLikelyNavigationText returns true if s, a text like a description, consists of
+// short segments split by separators, such as "Home > News > World",
+// or mostly of words used in navigation menus.
+func IsLikelyNavigationText(s string) bool {
+	for _, sep := range navigationSeparators {
+		segs := strings.Split(s, sep)
+		if len(segs) >= navigationMinSegments &&
+			len(strings.TrimSpace(s))/len(segs) <= navigationMaxSegmentLength {
+			return true
+		}
+	}
+
+	words := strings.Fields(strings.ToLower(s))
+	hits := 0
+	for _, w := range words {
+		if navigationWords[strings.Trim(w, ".,:;!?|")] {
+			hits++
+		}
+	}
+	return hits >= navigationMinSegments && float64(hits)/float64(len(words)) >= navigationMinWordRatio
+}
+
+// logoReason returns why img looks like a logo, an icon or a banner, or empty string.
+func logoReason(img Image) string {
+	if img.Source == ImageSourceFavicon {
+		return "is the site icon"
+	}
+	if u, err := url.Parse(img.URL); err == nil && !isDataURI(img.URL) {
+		p := strings.ToLower(u.Path)
+		for _, w := range logoURLWords {
+			if strings.Contains(p, w) {
+				return fmt.Sprintf("has %q in its path", w)
+			}
+		}
+	}
+	w, h := img.size()
+	if w > 0 && h > 0 && (w > h*logoMaxAspectRatio || h > w*logoMaxAspectRatio) {
+		return fmt.Sprintf("has an extreme aspect ratio %vx%v", w, h)
+	}
+	return ""
+}
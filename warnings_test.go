@@ -0,0 +1,77 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/philipjkim/fastimage"
+	"github.com/stretchr/testify/assert"
+)
+
+func warningCodes(ws []Warning) []WarningCode {
+	codes := []WarningCode{}
+	for _, w := range ws {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func TestWarnings(t *testing.T) {
+	opt := NewOption()
+	article := "A long enough description of the article, which is written as sentences."
+	for _, tc := range []struct {
+		c     *Content
+		codes []WarningCode
+	}{
+		{&Content{Title: "Title", Description: article}, []WarningCode{}},
+		{&Content{Title: "Title"}, []WarningCode{WarningDescriptionEmpty}},
+		{&Content{Title: "Breaking News", Description: " breaking  news. "}, []WarningCode{WarningDescriptionEqualsTitle}},
+		{&Content{Title: "Breaking News | Example Times", Description: "Breaking News"}, []WarningCode{WarningDescriptionEqualsTitle}},
+		{&Content{Title: "Title", Description: "We use cookies to improve your experience. Accept all"}, []WarningCode{WarningDescriptionIsCookieNotice}},
+		{&Content{Title: "Title", Description: "Home > World > Asia > Korea"}, []WarningCode{WarningDescriptionIsNavigation}},
+		{&Content{Title: "Title", Description: "Skip to content Home About us Contact Sign in Subscribe"}, []WarningCode{WarningDescriptionIsNavigation}},
+		{&Content{Title: "Title", Description: article, Images: []Image{
+			Image{URL: "http://example.com/static/site-logo.png"},
+			Image{URL: "http://example.com/a.jpg", Size: &fastimage.ImageSize{Width: 970, Height: 90}},
+			Image{URL: "http://example.com/b.jpg", Size: &fastimage.ImageSize{Width: 640, Height: 480}},
+		}, PrimaryImage: &Image{URL: "http://example.com/favicon.ico", Source: ImageSourceFavicon}},
+			[]WarningCode{WarningImageIsLogoSuspect, WarningImageIsLogoSuspect, WarningImageIsLogoSuspect}},
+	} {
+		assert.Equal(t, tc.codes, warningCodes(warnings(tc.c, opt)), tc.c.Description)
+	}
+}
+
+func TestIsLikelyNavigationText(t *testing.T) {
+	assert.True(t, IsLikelyNavigationText("Home > World > Asia > Korea"))
+	assert.True(t, IsLikelyNavigationText("Home | News | Sports | Contact"))
+	assert.False(t, IsLikelyNavigationText("A long enough description of the article, which is written as sentences."))
+	assert.False(t, IsLikelyNavigationText(""))
+}
+
+func TestLooksLikeCookieNotice(t *testing.T) {
+	assert.True(t, LooksLikeCookieNotice("We use cookies to give you the best experience. Accept all cookies"))
+	assert.True(t, LooksLikeCookieNotice("This website uses cookies."))
+	assert.True(t, LooksLikeCookieNotice("By continuing to browse the site, you agree to our use of cookies."))
+	assert.True(t, LooksLikeCookieNotice(strings.Repeat("Some text. ", 100)+"We use cookies. Read our cookie policy."))
+	// a single phrase in a long text is an article about cookies
+	assert.False(t, LooksLikeCookieNotice(strings.Repeat("Some text. ", 100)+"We use cookies."))
+	assert.False(t, LooksLikeCookieNotice("Bake the cookies for ten minutes."))
+}
+
+func TestTitleDescriptionRedundant(t *testing.T) {
+	assert.True(t, TitleDescriptionRedundant(&Content{Title: "Breaking News", Description: "breaking news."}))
+	assert.True(t, TitleDescriptionRedundant(&Content{Title: "Breaking News | Example Times", Description: "<div><p>Breaking News</p></div>"}))
+	assert.False(t, TitleDescriptionRedundant(&Content{Title: "Breaking News", Description: "Breaking News: the article"}))
+	assert.False(t, TitleDescriptionRedundant(&Content{Title: "Breaking News"}))
+}
+
+func TestExtractFromDocumentWarnings(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>
+<meta property="og:title" content="Same" /><meta property="og:description" content="Same" />
+</head></html>`))
+	c, err := ExtractFromDocument(doc, "http://example.com", NewOption())
+	assert.Nil(t, err)
+	// the primary image falls back to /favicon.ico
+	assert.Equal(t, []WarningCode{WarningDescriptionEqualsTitle, WarningImageIsLogoSuspect}, warningCodes(c.Warnings))
+}
@@ -0,0 +1,102 @@
+package readability
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// xhtmlNamespace is the XML namespace of XHTML elements.
+const xhtmlNamespace = "http://www.w3.org/1999/xhtml"
+
+// xhtmlSniffLength is the number of leading bytes searched for an XML declaration or an XHTML doctype.
+const xhtmlSniffLength = 1024
+
+var (
+	xmlDeclaration  = regexp.MustCompile(`^\s*<\?xml\s`)
+	xhtmlDoctype    = regexp.MustCompile(`(?i)<!DOCTYPE\s+html\s+PUBLIC\s+"-//W3C//DTD XHTML`)
+	xhtmlPrefixDecl = regexp.MustCompile(`xmlns:([A-Za-z_][\w.-]*)\s*=\s*["']` + regexp.QuoteMeta(xhtmlNamespace) + `["']`)
+	selfClosingTag  = regexp.MustCompile(`<([A-Za-z][\w:.-]*)([^<>]*?)\s*/>`)
+)
+
+// voidElements are HTML elements without end tags, which the HTML parser already treats as self-closing.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// ParseDocument parses r as a document served with contentType, which may be empty.
+// The document is decoded into UTF-8 as decided by CharsetPolicyAuto.
+//
+// XHTML documents, served as application/xhtml+xml (or XML) or declaring an XML declaration
+// or an XHTML doctype, are rewritten before parsing so that the HTML parser reads them as intended:
+// self-closing non-void elements such as <div/> or <script src="..."/> are closed right away
+// instead of swallowing the rest of the page, and elements prefixed with the XHTML namespace
+// such as <html:p> lose their prefix. Documents with HTML 4 or older doctypes are parsed as is.
+//
+// Plain text and markdown documents, as served by text-only mirrors, are wrapped into
+// a paragraph per block separated by blank lines.
+func ParseDocument(r io.Reader, contentType string) (*goquery.Document, error) {
+	doc, _, err := parseDocument(r, contentType, NewOption())
+	return doc, err
+}
+
+// parseDocument acts same as ParseDocument, except that the charset is decided by
+// opt.CharsetPolicy and decoded with opt.CharsetReader. The decision is returned with the document.
+func parseDocument(r io.Reader, contentType string, opt *Option) (*goquery.Document, *CharsetDecision, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	cs := decideCharset(b, contentType, opt.CharsetPolicy)
+	b = decodeCharset(b, cs, opt.CharsetReader)
+	if isPlainText(contentType) {
+		b = []byte(plainTextToHTML(string(b)))
+	} else if isXHTML(contentType, b) {
+		b = []byte(normalizeXHTML(string(b)))
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, cs, nil
+}
+
+// isXHTML returns true if a document of contentType starting with b is XHTML.
+func isXHTML(contentType string, b []byte) bool {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mt {
+		case "application/xhtml+xml", "application/xml", "text/xml":
+			return true
+		}
+	}
+	if len(b) > xhtmlSniffLength {
+		b = b[:xhtmlSniffLength]
+	}
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	return xmlDeclaration.Match(b) || xhtmlDoctype.Match(b)
+}
+
+// normalizeXHTML rewrites XHTML markup s into markup parsed by the HTML parser as intended.
+func normalizeXHTML(s string) string {
+	s = replaceAllChunkedFunc(selfClosingTag, s, func(tag string) string {
+		m := selfClosingTag.FindStringSubmatch(tag)
+		name := strings.ToLower(m[1])
+		if voidElements[name] {
+			return tag
+		}
+		return fmt.Sprintf("<%s%s></%s>", m[1], m[2], m[1])
+	})
+
+	for _, m := range xhtmlPrefixDecl.FindAllStringSubmatch(s, -1) {
+		prefixed := regexp.MustCompile(`<(/?)` + regexp.QuoteMeta(m[1]) + `:`)
+		s = replaceAllChunked(prefixed, s, "<$1")
+	}
+	return s
+}
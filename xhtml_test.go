@@ -0,0 +1,47 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDocumentXHTML(t *testing.T) {
+	x := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:h="http://www.w3.org/1999/xhtml"><head><title>T</title>
+<script type="text/javascript" src="/a.js"/></head>
+<body><div id="anchor"/><h:p>First<br/>paragraph</h:p><img src="/a.jpg"/></body></html>`
+	doc, err := ParseDocument(strings.NewReader(x), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, doc.Find("#anchor").Children().Length())
+	assert.Equal(t, "Firstparagraph", doc.Find("body p").Text())
+	assert.Equal(t, 1, doc.Find("body br").Length())
+	assert.Equal(t, 1, doc.Find("body img").Length())
+
+	// not rewritten unless the document is XHTML
+	doc, _ = ParseDocument(strings.NewReader(`<body><div id="a"/><p>text</p></body>`), "text/html; charset=utf-8")
+	assert.Equal(t, 1, doc.Find("#a p").Length())
+	doc, _ = ParseDocument(strings.NewReader(`<body><div id="a"/><p>text</p></body>`), "application/xhtml+xml")
+	assert.Equal(t, 0, doc.Find("#a p").Length())
+}
+
+func TestExtractorExtractXHTML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xhtml+xml; charset=utf-8")
+		body := strings.Repeat("This is a long paragraph of the legacy page, which is served as XHTML. ", 5)
+		fmt.Fprintf(w, `<html xmlns="http://www.w3.org/1999/xhtml"><head><title>Legacy</title><script src="/a.js"/></head>
+<body><div><p>%s</p><p>%s</p></div></body></html>`, body, body)
+	}))
+	defer ts.Close()
+
+	c, err := NewExtractor(nil).Extract(context.Background(), ts.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "Legacy", c.Title)
+	assert.Contains(t, c.Description, "served as XHTML")
+}